@@ -72,6 +72,7 @@ type DetectionOptions struct {
 	ExcludeMethods     []string                       `json:"exclude_methods"`     // Exclude specific methods
 	MinConfidence      float64                        `json:"min_confidence"`      // Minimum confidence level for reporting
 	IgnoreImprovements bool                           `json:"ignore_improvements"` // Don't report improvements as negative regressions
+	LatencyMode        types.LatencyMode              `json:"latency_mode"`        // "raw" (default) or "corrected" latency figures
 }
 
 // RegressionReport provides comprehensive regression analysis results
@@ -379,11 +380,15 @@ func (rd *regressionDetector) DetectRegressions(ctx context.Context, runID strin
 
 	switch options.ComparisonMode {
 	case "sequential":
-		regressions, err = rd.CompareToSequential(ctx, runID, options.LookbackCount)
+		regressions, err = rd.compareToSequential(ctx, runID, options.LookbackCount, options.LatencyMode)
 	case "baseline":
 		if options.BaselineName == "" {
 			return nil, fmt.Errorf("baseline name required for baseline comparison mode")
 		}
+		// Baselines keep raw latency summaries only
+		if options.LatencyMode == types.LatencyModeCorrected {
+			return nil, fmt.Errorf("corrected latency mode is not supported for baseline comparison mode")
+		}
 		regressions, err = rd.CompareToBaseline(ctx, runID, options.BaselineName)
 		// Get baseline info
 		baseline, baselineErr := rd.baselineManager.GetBaseline(ctx, options.BaselineName)
@@ -396,7 +401,7 @@ func (rd *regressionDetector) DetectRegressions(ctx context.Context, runID strin
 			}
 		}
 	case "rolling_average":
-		regressions, err = rd.CompareToRollingAverage(ctx, runID, options.WindowSize)
+		regressions, err = rd.compareToRollingAverage(ctx, runID, options.WindowSize, options.LatencyMode)
 	default:
 		return nil, fmt.Errorf("invalid comparison mode: %s", options.ComparisonMode)
 	}
@@ -559,6 +564,10 @@ func (rd *regressionDetector) ClassifyRegression(regression *types.Regression) s
 
 // CompareToSequential compares against previous sequential runs
 func (rd *regressionDetector) CompareToSequential(ctx context.Context, runID string, lookback int) ([]*types.Regression, error) {
+	return rd.compareToSequential(ctx, runID, lookback, types.LatencyModeRaw)
+}
+
+func (rd *regressionDetector) compareToSequential(ctx context.Context, runID string, lookback int, latencyMode types.LatencyMode) ([]*types.Regression, error) {
 	rd.log.WithFields(logrus.Fields{
 		"run_id":   sanitize.LogValue(runID),
 		"lookback": lookback,
@@ -606,7 +615,7 @@ func (rd *regressionDetector) CompareToSequential(ctx context.Context, runID str
 	// Compare against the most recent previous run primarily
 	baseline := prevRuns[0]
 
-	return rd.compareRuns(ctx, currentRun, baseline, "sequential")
+	return rd.compareRuns(ctx, currentRun, baseline, "sequential", latencyMode)
 }
 
 // CompareToBaseline compares against a specific baseline
@@ -631,6 +640,10 @@ func (rd *regressionDetector) CompareToBaseline(ctx context.Context, runID, base
 
 // CompareToRollingAverage compares against rolling average of previous runs
 func (rd *regressionDetector) CompareToRollingAverage(ctx context.Context, runID string, windowSize int) ([]*types.Regression, error) {
+	return rd.compareToRollingAverage(ctx, runID, windowSize, types.LatencyModeRaw)
+}
+
+func (rd *regressionDetector) compareToRollingAverage(ctx context.Context, runID string, windowSize int, latencyMode types.LatencyMode) ([]*types.Regression, error) {
 	rd.log.WithFields(logrus.Fields{
 		"run_id":      sanitize.LogValue(runID),
 		"window_size": windowSize,
@@ -681,7 +694,7 @@ func (rd *regressionDetector) CompareToRollingAverage(ctx context.Context, runID
 	// Calculate rolling averages
 	avgRun := rd.calculateRollingAverage(prevRuns)
 
-	return rd.compareRuns(ctx, currentRun, avgRun, "rolling_average")
+	return rd.compareRuns(ctx, currentRun, avgRun, "rolling_average", latencyMode)
 }
 
 // SaveRegressions saves regressions to the database
@@ -835,6 +848,15 @@ func (rd *regressionDetector) initializeDefaultThresholds() {
 			IsPercentage:      true,
 			Direction:         "decrease",
 		},
+		"efficiency": {
+			MinorThreshold:    5.0,  // 5% decrease
+			MajorThreshold:    15.0, // 15% decrease
+			CriticalThreshold: 30.0, // 30% decrease
+			MinSampleSize:     10,
+			SignificanceLevel: 0.05,
+			IsPercentage:      true,
+			Direction:         "decrease",
+		},
 	}
 
 	for name, threshold := range defaultThresholds {
@@ -877,7 +899,9 @@ func (rd *regressionDetector) createRegressionsTable(ctx context.Context) error
 	return err
 }
 
-func (rd *regressionDetector) compareRuns(ctx context.Context, current, baseline *types.HistoricRun, comparisonMode string) ([]*types.Regression, error) {
+// compareRuns compares current against baseline using the latency figures of
+// latencyMode
+func (rd *regressionDetector) compareRuns(ctx context.Context, current, baseline *types.HistoricRun, comparisonMode string, latencyMode types.LatencyMode) ([]*types.Regression, error) {
 	var regressions []*types.Regression
 
 	// Parse full results for detailed comparison
@@ -889,6 +913,21 @@ func (rd *regressionDetector) compareRuns(ctx context.Context, current, baseline
 		return nil, fmt.Errorf("failed to unmarshal baseline results: %w", err)
 	}
 
+	// Compare corrected latency only when both sides recorded it; mixing a
+	// corrected current run with a raw baseline would report queueing delay
+	// as a regression.
+	if latencyMode == types.LatencyModeCorrected {
+		if currentResult.HasCorrectedLatency() && baselineResult.HasCorrectedLatency() {
+			currentResult = *currentResult.WithLatencyMode(latencyMode)
+			baselineResult = *baselineResult.WithLatencyMode(latencyMode)
+		} else {
+			rd.log.WithFields(logrus.Fields{
+				"run_id":      sanitize.LogValue(current.ID),
+				"baseline_id": sanitize.LogValue(baseline.ID),
+			}).Warn("Corrected latency not recorded for both runs, comparing raw latency")
+		}
+	}
+
 	// Compare client-level metrics
 	for clientName, currentMetrics := range currentResult.ClientMetrics {
 		baselineMetrics, exists := baselineResult.ClientMetrics[clientName]
@@ -926,6 +965,25 @@ func (rd *regressionDetector) compareRuns(ctx context.Context, current, baseline
 			regressions = append(regressions, regression)
 		}
 
+		// Compare efficiency when both runs sampled the client's resources
+		if currentEff, baselineEff := currentResult.Efficiency[clientName], baselineResult.Efficiency[clientName]; currentEff != nil && baselineEff != nil {
+			efficiencyChecks := []struct {
+				metric            string
+				baseline, current float64
+			}{
+				{types.MetricRequestsPerCPUSecond, baselineEff.RequestsPerCPUSecond, currentEff.RequestsPerCPUSecond},
+				{types.MetricRPSPerGB, baselineEff.RPSPerGB, currentEff.RPSPerGB},
+				{types.MetricP99LatencyGB, baselineEff.P99LatencyGB, currentEff.P99LatencyGB},
+				{types.MetricRPSPerWatt, baselineEff.RPSPerWatt, currentEff.RPSPerWatt},
+			}
+			for _, check := range efficiencyChecks {
+				if regression := rd.checkMetricRegression(current.ID, baseline.ID, clientName, "",
+					check.metric, check.baseline, check.current); regression != nil {
+					regressions = append(regressions, regression)
+				}
+			}
+		}
+
 		// Compare method-level metrics
 		for methodName, currentMethodMetrics := range currentMetrics.Methods {
 			if baselineMethodMetrics, exists := baselineMetrics.Methods[methodName]; exists {
@@ -970,6 +1028,8 @@ func (rd *regressionDetector) checkMetricRegression(runID, baselineRunID, client
 			threshold = rd.thresholds["error_rate"]
 		} else if strings.Contains(metric, "throughput") {
 			threshold = rd.thresholds["throughput"]
+		} else if strings.Contains(metric, "_per_") {
+			threshold = rd.thresholds["efficiency"]
 		} else {
 			threshold = rd.thresholds["default"]
 		}
@@ -1069,15 +1129,17 @@ func (rd *regressionDetector) calculateRollingAverage(runs []*types.HistoricRun)
 		avgRun.PerformanceScores[client] = totalScore / count
 	}
 
-	// Build synthetic full results for detailed comparison
-	avgRun.FullResults = rd.buildSyntheticFullResults(runs)
+	// Average the full results for detailed comparison
+	avgRun.FullResults = rd.averageFullResults(runs)
 
 	return avgRun
 }
 
-func (rd *regressionDetector) buildSyntheticFullResults(runs []*types.HistoricRun) json.RawMessage {
-	// Create a synthetic BenchmarkResult by averaging all runs
-	syntheticResult := types.BenchmarkResult{
+// averageFullResults averages the client and method metrics of the runs' full
+// results. Payloads of older schema versions are upgraded as they are decoded.
+func (rd *regressionDetector) averageFullResults(runs []*types.HistoricRun) json.RawMessage {
+	averaged := types.BenchmarkResult{
+		SchemaVersion: types.ResultSchemaVersion,
 		ClientMetrics: make(map[string]*types.ClientMetrics),
 	}
 
@@ -1088,6 +1150,7 @@ func (rd *regressionDetector) buildSyntheticFullResults(runs []*types.HistoricRu
 	for _, run := range runs {
 		var result types.BenchmarkResult
 		if err := json.Unmarshal(run.FullResults, &result); err != nil {
+			rd.log.WithError(err).WithField("run_id", run.ID).Warn("Skipping run with unreadable full results in the average baseline")
 			continue
 		}
 
@@ -1109,6 +1172,11 @@ func (rd *regressionDetector) buildSyntheticFullResults(runs []*types.HistoricRu
 			clientMetricsSum[clientName].Latency.P99 += metrics.Latency.P99
 			clientMetricsSum[clientName].Latency.Max += metrics.Latency.Max
 			clientMetricsSum[clientName].Latency.Throughput += metrics.Latency.Throughput
+			clientMetricsSum[clientName].Latency.CorrectedAvg += metrics.Latency.CorrectedAvg
+			clientMetricsSum[clientName].Latency.CorrectedP50 += metrics.Latency.CorrectedP50
+			clientMetricsSum[clientName].Latency.CorrectedP95 += metrics.Latency.CorrectedP95
+			clientMetricsSum[clientName].Latency.CorrectedP99 += metrics.Latency.CorrectedP99
+			clientMetricsSum[clientName].Latency.CorrectedMax += metrics.Latency.CorrectedMax
 
 			// Sum method metrics
 			for methodName, methodMetrics := range metrics.Methods {
@@ -1121,6 +1189,11 @@ func (rd *regressionDetector) buildSyntheticFullResults(runs []*types.HistoricRu
 					existing.P99 += methodMetrics.P99
 					existing.Max += methodMetrics.Max
 					existing.Throughput += methodMetrics.Throughput
+					existing.CorrectedAvg += methodMetrics.CorrectedAvg
+					existing.CorrectedP50 += methodMetrics.CorrectedP50
+					existing.CorrectedP95 += methodMetrics.CorrectedP95
+					existing.CorrectedP99 += methodMetrics.CorrectedP99
+					existing.CorrectedMax += methodMetrics.CorrectedMax
 				} else {
 					clientMetricsSum[clientName].Methods[methodName] = methodMetrics
 				}
@@ -1147,6 +1220,11 @@ func (rd *regressionDetector) buildSyntheticFullResults(runs []*types.HistoricRu
 		avgMetrics.Latency.P99 = sumMetrics.Latency.P99 / count
 		avgMetrics.Latency.Max = sumMetrics.Latency.Max / count
 		avgMetrics.Latency.Throughput = sumMetrics.Latency.Throughput / count
+		avgMetrics.Latency.CorrectedAvg = sumMetrics.Latency.CorrectedAvg / count
+		avgMetrics.Latency.CorrectedP50 = sumMetrics.Latency.CorrectedP50 / count
+		avgMetrics.Latency.CorrectedP95 = sumMetrics.Latency.CorrectedP95 / count
+		avgMetrics.Latency.CorrectedP99 = sumMetrics.Latency.CorrectedP99 / count
+		avgMetrics.Latency.CorrectedMax = sumMetrics.Latency.CorrectedMax / count
 
 		// Average method metrics
 		for methodName, sumMethodMetrics := range sumMetrics.Methods {
@@ -1159,15 +1237,20 @@ func (rd *regressionDetector) buildSyntheticFullResults(runs []*types.HistoricRu
 			avgMethodMetrics.P99 = sumMethodMetrics.P99 / count
 			avgMethodMetrics.Max = sumMethodMetrics.Max / count
 			avgMethodMetrics.Throughput = sumMethodMetrics.Throughput / count
+			avgMethodMetrics.CorrectedAvg = sumMethodMetrics.CorrectedAvg / count
+			avgMethodMetrics.CorrectedP50 = sumMethodMetrics.CorrectedP50 / count
+			avgMethodMetrics.CorrectedP95 = sumMethodMetrics.CorrectedP95 / count
+			avgMethodMetrics.CorrectedP99 = sumMethodMetrics.CorrectedP99 / count
+			avgMethodMetrics.CorrectedMax = sumMethodMetrics.CorrectedMax / count
 
 			avgMetrics.Methods[methodName] = avgMethodMetrics
 		}
 
-		syntheticResult.ClientMetrics[clientName] = avgMetrics
+		averaged.ClientMetrics[clientName] = avgMetrics
 	}
 
 	// Marshal to JSON
-	data, err := json.Marshal(syntheticResult)
+	data, err := json.Marshal(averaged)
 	if err != nil {
 		return json.RawMessage("{}")
 	}
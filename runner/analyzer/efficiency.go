@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+const bytesPerGB = 1024 * 1024 * 1024
+
+// calculateEfficiency normalizes each client's throughput and p99 latency by
+// its sampled CPU, memory and power. Clients without resources are left out;
+// it returns nil when none has them.
+func (pa *PerformanceAnalyzer) calculateEfficiency(clients map[string]*types.ClientMetrics, duration time.Duration) map[string]*types.ClientEfficiency {
+	efficiency := make(map[string]*types.ClientEfficiency)
+	for name, client := range clients {
+		res := client.Resources
+		if res == nil || len(res.Samples) == 0 || res.CPUSeconds <= 0 || res.AvgMemoryBytes <= 0 {
+			continue
+		}
+
+		var rps float64
+		if duration > 0 {
+			rps = float64(client.TotalRequests) / duration.Seconds()
+		}
+		memoryGB := float64(res.AvgMemoryBytes) / bytesPerGB
+
+		eff := &types.ClientEfficiency{
+			RequestsPerCPUSecond: float64(client.TotalRequests) / res.CPUSeconds,
+			RPSPerGB:             rps / memoryGB,
+			P99LatencyGB:         client.Latency.P99 * memoryGB,
+		}
+		if res.AvgWatts > 0 {
+			eff.RPSPerWatt = rps / res.AvgWatts
+		}
+		efficiency[name] = eff
+	}
+	if len(efficiency) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(efficiency))
+	var perCPU, perGB, p99GB []float64
+	for name, eff := range efficiency {
+		names = append(names, name)
+		perCPU = append(perCPU, eff.RequestsPerCPUSecond)
+		perGB = append(perGB, eff.RPSPerGB)
+		p99GB = append(p99GB, eff.P99LatencyGB)
+	}
+	perCPUScores := pa.normalizeMetric(perCPU, false) // Higher is better
+	perGBScores := pa.normalizeMetric(perGB, false)   // Higher is better
+	p99GBScores := pa.normalizeMetric(p99GB, true)    // Lower is better
+	for i, name := range names {
+		efficiency[name].Score = (perCPUScores[i] + perGBScores[i] + p99GBScores[i]) / 3
+	}
+
+	return efficiency
+}
+
+// applyEfficiencyScores blends efficiency into the performance scores when
+// every scored client has one, so clients aren't ranked on mixed criteria
+func (pa *PerformanceAnalyzer) applyEfficiencyScores(scores map[string]float64, efficiency map[string]*types.ClientEfficiency) {
+	if pa.weights.Efficiency <= 0 || len(efficiency) == 0 {
+		return
+	}
+	for name := range scores {
+		if _, ok := efficiency[name]; !ok {
+			return
+		}
+	}
+	for name, score := range scores {
+		scores[name] = (1-pa.weights.Efficiency)*score + pa.weights.Efficiency*efficiency[name].Score
+	}
+}
+
+// efficiencyExtremes returns the clients with the most and fewest requests
+// per CPU-second, breaking ties by name
+func efficiencyExtremes(efficiency map[string]*types.ClientEfficiency) (best, worst string) {
+	for name, eff := range efficiency {
+		if best == "" || eff.RequestsPerCPUSecond > efficiency[best].RequestsPerCPUSecond ||
+			(eff.RequestsPerCPUSecond == efficiency[best].RequestsPerCPUSecond && name < best) {
+			best = name
+		}
+		if worst == "" || eff.RequestsPerCPUSecond < efficiency[worst].RequestsPerCPUSecond ||
+			(eff.RequestsPerCPUSecond == efficiency[worst].RequestsPerCPUSecond && name < worst) {
+			worst = name
+		}
+	}
+	return best, worst
+}
+
+// parseRunDuration reads BenchmarkResult.Duration, which holds a
+// time.Duration string; it returns zero when it can't be parsed
+func parseRunDuration(duration string) time.Duration {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0
+	}
+	return d
+}
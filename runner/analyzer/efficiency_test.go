@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// resourceClient is a client that served requests using cpuSeconds of CPU and
+// memoryGB of average memory
+func resourceClient(requests int64, p99, cpuSeconds, memoryGB, watts float64) *types.ClientMetrics {
+	return &types.ClientMetrics{
+		TotalRequests: requests,
+		Latency:       types.MetricSummary{P99: p99},
+		Resources: &types.ClientResources{
+			Samples:        []types.ClientResourceSample{{Timestamp: 1}},
+			CPUSeconds:     cpuSeconds,
+			AvgMemoryBytes: int64(memoryGB * bytesPerGB),
+			AvgWatts:       watts,
+		},
+	}
+}
+
+func TestCalculateEfficiency(t *testing.T) {
+	pa := NewPerformanceAnalyzer()
+	efficiency := pa.calculateEfficiency(map[string]*types.ClientMetrics{
+		// 100 rps on 60 CPU-seconds, 2 GB and 50 W
+		"geth": resourceClient(6000, 100, 60, 2, 50),
+		// The same load on half the CPU and memory, power not measured
+		"reth":   resourceClient(6000, 100, 30, 1, 0),
+		"remote": {TotalRequests: 6000},
+	}, time.Minute)
+
+	if len(efficiency) != 2 || efficiency["remote"] != nil {
+		t.Fatalf("efficiency = %+v, want geth and reth only", efficiency)
+	}
+	geth, reth := efficiency["geth"], efficiency["reth"]
+	if !approx(geth.RequestsPerCPUSecond, 100) || !approx(geth.RPSPerGB, 50) || !approx(geth.P99LatencyGB, 200) || !approx(geth.RPSPerWatt, 2) {
+		t.Errorf("geth = %+v", geth)
+	}
+	if !approx(reth.RequestsPerCPUSecond, 200) || !approx(reth.RPSPerGB, 100) || !approx(reth.P99LatencyGB, 100) || reth.RPSPerWatt != 0 {
+		t.Errorf("reth = %+v", reth)
+	}
+	// reth is best on every normalized figure, geth worst
+	if !approx(reth.Score, 100) || !approx(geth.Score, 0) {
+		t.Errorf("scores = geth %v, reth %v", geth.Score, reth.Score)
+	}
+	if best, worst := efficiencyExtremes(efficiency); best != "reth" || worst != "geth" {
+		t.Errorf("extremes = %s, %s", best, worst)
+	}
+}
+
+func TestCalculateEfficiencyDegenerate(t *testing.T) {
+	pa := NewPerformanceAnalyzer()
+
+	// Zero CPU or memory can't be normalized by, and no samples means the
+	// totals weren't measured
+	noSamples := resourceClient(6000, 100, 60, 2, 0)
+	noSamples.Resources.Samples = nil
+	if got := pa.calculateEfficiency(map[string]*types.ClientMetrics{
+		"zero-cpu":    resourceClient(6000, 100, 0, 2, 0),
+		"zero-memory": resourceClient(6000, 100, 60, 0, 0),
+		"no-samples":  noSamples,
+	}, time.Minute); got != nil {
+		t.Errorf("efficiency = %+v, want nil", got)
+	}
+
+	// A single client has nothing to be ranked against
+	got := pa.calculateEfficiency(map[string]*types.ClientMetrics{"geth": resourceClient(6000, 100, 60, 2, 0)}, 0)
+	if eff := got["geth"]; eff == nil || !approx(eff.RequestsPerCPUSecond, 100) || eff.RPSPerGB != 0 || eff.Score != 50 {
+		t.Errorf("without a duration = %+v", eff)
+	}
+}
+
+func TestApplyEfficiencyScores(t *testing.T) {
+	pa := NewPerformanceAnalyzer()
+	efficiency := map[string]*types.ClientEfficiency{"geth": {Score: 0}, "reth": {Score: 100}}
+
+	scores := map[string]float64{"geth": 80, "reth": 60}
+	pa.applyEfficiencyScores(scores, efficiency)
+	if !approx(scores["geth"], 0.8*80) || !approx(scores["reth"], 0.8*60+0.2*100) {
+		t.Errorf("blended scores = %v", scores)
+	}
+
+	// A client without efficiency leaves every score unchanged
+	scores = map[string]float64{"geth": 80, "reth": 60, "remote": 70}
+	pa.applyEfficiencyScores(scores, efficiency)
+	if scores["geth"] != 80 || scores["reth"] != 60 || scores["remote"] != 70 {
+		t.Errorf("scores with a client lacking efficiency = %v", scores)
+	}
+}
@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jsonrpc-bench/runner/internal/stats"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// minPairsForSignificance is the smallest sample the normal approximation of
+// the Wilcoxon signed-rank statistic is trusted at
+const minPairsForSignificance = 20
+
+// AnalyzePaired compares every pair of clients request by request. latencies
+// maps client -> request id -> latency in ms and methods maps request id ->
+// method; only ids served by both clients of a pair are used. It returns one
+// comparison over all methods per client pair followed by one per method.
+func (pa *PerformanceAnalyzer) AnalyzePaired(latencies map[string]map[string]float64, methods map[string]string) []types.PairedComparison {
+	clients := make([]string, 0, len(latencies))
+	for client := range latencies {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+
+	var comparisons []types.PairedComparison
+	for i := 0; i < len(clients); i++ {
+		for j := i + 1; j < len(clients); j++ {
+			a, b := clients[i], clients[j]
+
+			var all [][2]float64
+			byMethod := make(map[string][][2]float64)
+			for id, latencyA := range latencies[a] {
+				latencyB, ok := latencies[b][id]
+				if !ok {
+					continue
+				}
+				pair := [2]float64{latencyA, latencyB}
+				all = append(all, pair)
+				byMethod[methods[id]] = append(byMethod[methods[id]], pair)
+			}
+			if len(all) == 0 {
+				continue
+			}
+
+			comparisons = append(comparisons, comparePairs(a, b, "", all))
+			names := make([]string, 0, len(byMethod))
+			for method := range byMethod {
+				names = append(names, method)
+			}
+			sort.Strings(names)
+			for _, method := range names {
+				comparisons = append(comparisons, comparePairs(a, b, method, byMethod[method]))
+			}
+		}
+	}
+	return comparisons
+}
+
+// comparePairs summarizes latency pairs of (a, b) on identical requests
+func comparePairs(a, b, method string, pairs [][2]float64) types.PairedComparison {
+	result := types.PairedComparison{
+		ClientA: a,
+		ClientB: b,
+		Method:  method,
+		Pairs:   len(pairs),
+	}
+
+	ratios := make([]float64, 0, len(pairs))
+	diffs := make([]float64, 0, len(pairs))
+	logSum := 0.0
+	for _, p := range pairs {
+		switch {
+		case p[0] < p[1]:
+			result.AFaster++
+		case p[0] > p[1]:
+			result.BFaster++
+		default:
+			result.Ties++
+		}
+		if p[0] > 0 && p[1] > 0 {
+			ratio := p[0] / p[1]
+			ratios = append(ratios, ratio)
+			logSum += math.Log(ratio)
+		}
+		diffs = append(diffs, p[0]-p[1])
+	}
+
+	if len(ratios) > 0 {
+		sort.Float64s(ratios)
+		result.RatioP10 = stats.PercentileSorted(ratios, 10)
+		result.RatioP50 = stats.PercentileSorted(ratios, 50)
+		result.RatioP90 = stats.PercentileSorted(ratios, 90)
+		result.RatioP99 = stats.PercentileSorted(ratios, 99)
+		result.RatioGeoMean = math.Exp(logSum / float64(len(ratios)))
+	}
+
+	result.WilcoxonW, result.WilcoxonZ, result.PValue = wilcoxonSignedRank(diffs)
+
+	switch {
+	case result.AFaster > result.BFaster:
+		result.Winner = a
+	case result.BFaster > result.AFaster:
+		result.Winner = b
+	}
+	// Diffs are A-B, so z < 0 means the ranked differences favour A. The win
+	// count and the rank test can disagree (A wins more pairs by little, B
+	// wins fewer by a lot); only call it significant when they agree.
+	agrees := (result.Winner == a && result.WilcoxonZ < 0) || (result.Winner == b && result.WilcoxonZ > 0)
+	result.Significant = agrees && result.Pairs >= minPairsForSignificance && result.PValue < 0.05
+
+	return result
+}
+
+// wilcoxonSignedRank returns W+ (the rank sum of positive differences), its
+// z-score and the two-sided p-value. Zero differences are dropped and tied
+// absolute differences share their average rank.
+func wilcoxonSignedRank(diffs []float64) (w, z, p float64) {
+	nonZero := make([]float64, 0, len(diffs))
+	for _, d := range diffs {
+		if d != 0 {
+			nonZero = append(nonZero, d)
+		}
+	}
+	n := len(nonZero)
+	if n == 0 {
+		return 0, 0, 1
+	}
+
+	sort.Slice(nonZero, func(i, j int) bool {
+		return math.Abs(nonZero[i]) < math.Abs(nonZero[j])
+	})
+
+	tieCorrection := 0.0
+	for i := 0; i < n; {
+		j := i
+		for j < n && math.Abs(nonZero[j]) == math.Abs(nonZero[i]) {
+			j++
+		}
+		// Ranks i+1..j share their average
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if nonZero[k] > 0 {
+				w += rank
+			}
+		}
+		t := float64(j - i)
+		tieCorrection += t*t*t - t
+		i = j
+	}
+
+	nf := float64(n)
+	mean := nf * (nf + 1) / 4
+	variance := nf*(nf+1)*(2*nf+1)/24 - tieCorrection/48
+	if variance <= 0 {
+		return w, 0, 1
+	}
+	z = (w - mean) / math.Sqrt(variance)
+	p = math.Erfc(math.Abs(z) / math.Sqrt2)
+	return w, z, p
+}
@@ -0,0 +1,75 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestWilcoxonSignedRank(t *testing.T) {
+	tests := []struct {
+		name    string
+		diffs   []float64
+		w, z, p float64
+	}{
+		// Ranks 1..5, W+ = 1+2+3+5; mean 7.5, variance 13.75
+		{"known fixture", []float64{1, 2, 3, -4, 5}, 11, 0.9438798074485389, 0.3452310717718401},
+		// |1| ties at ranks 1-2 and |2| at 3-4: average ranks 1.5 and 3.5,
+		// variance 7.5 minus the tie correction 12/48
+		{"ties", []float64{1, -1, 2, 2}, 8.5, 1.299867367239363, 0.19364643126922065},
+		// Zero differences are dropped before ranking
+		{"zero diffs dropped", []float64{0, 0, 1, 2, 3, -4, 5, 0}, 11, 0.9438798074485389, 0.3452310717718401},
+		{"all zero", []float64{0, 0, 0}, 0, 0, 1},
+		{"empty", nil, 0, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, z, p := wilcoxonSignedRank(tt.diffs)
+			if !approx(w, tt.w) || !approx(z, tt.z) || !approx(p, tt.p) {
+				t.Errorf("got W=%v z=%v p=%v, want W=%v z=%v p=%v", w, z, p, tt.w, tt.z, tt.p)
+			}
+		})
+	}
+}
+
+func pairs(n int, a, b float64) [][2]float64 {
+	out := make([][2]float64, n)
+	for i := range out {
+		// Vary the values so the absolute differences are not all tied
+		out[i] = [2]float64{a + float64(i), b + float64(i)*1.01}
+	}
+	return out
+}
+
+func TestComparePairs(t *testing.T) {
+	// A is faster on every one of 25 payloads
+	r := comparePairs("geth", "reth", "", pairs(25, 10, 20))
+	if r.Winner != "geth" || r.AFaster != 25 || r.WilcoxonZ >= 0 || !r.Significant {
+		t.Errorf("clear win = %+v", r)
+	}
+
+	// The same direction on fewer than minPairsForSignificance pairs is not
+	// significant, however small p is
+	r = comparePairs("geth", "reth", "", pairs(10, 10, 20))
+	if r.Winner != "geth" || r.Significant {
+		t.Errorf("n<20 = %+v", r)
+	}
+
+	// A wins 55 pairs by about 1ms, B wins 45 by about 100ms: A has the win
+	// count but the ranked differences significantly favour B, so the win is
+	// not significant
+	mixed := append(pairs(55, 10, 11), pairs(45, 200, 100)...)
+	r = comparePairs("geth", "reth", "", mixed)
+	if r.Winner != "geth" || r.WilcoxonZ <= 0 || r.PValue >= 0.05 || r.Significant {
+		t.Errorf("disagreeing win count and rank test = %+v", r)
+	}
+
+	// Identical latencies are ties with no winner
+	r = comparePairs("geth", "reth", "eth_call", [][2]float64{{5, 5}, {7, 7}})
+	if r.Winner != "" || r.Ties != 2 || r.PValue != 1 || r.Significant {
+		t.Errorf("ties = %+v", r)
+	}
+}
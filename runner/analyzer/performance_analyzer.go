@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 
 	"github.com/jsonrpc-bench/runner/types"
 )
 
 // PerformanceAnalyzer analyzes benchmark results and provides insights
 type PerformanceAnalyzer struct {
-	weights PerformanceWeights
+	weights     PerformanceWeights
+	latencyMode types.LatencyMode
 }
 
 // PerformanceWeights defines weights for different metrics in scoring
@@ -19,6 +21,9 @@ type PerformanceWeights struct {
 	Throughput float64
 	ErrorRate  float64
 	Stability  float64
+	// Efficiency is the share of the final score taken from resource
+	// efficiency when every client has sampled resources
+	Efficiency float64
 }
 
 // NewPerformanceAnalyzer creates a new performance analyzer
@@ -29,17 +34,34 @@ func NewPerformanceAnalyzer() *PerformanceAnalyzer {
 			Throughput: 0.30,
 			ErrorRate:  0.25,
 			Stability:  0.10,
+			Efficiency: 0.20,
 		},
+		latencyMode: types.LatencyModeRaw,
 	}
 }
 
+// SetLatencyMode selects whether scoring and regression checks read raw or
+// coordinated-omission-corrected latency
+func (pa *PerformanceAnalyzer) SetLatencyMode(mode types.LatencyMode) {
+	pa.latencyMode = mode
+}
+
 // AnalyzeResults performs comprehensive analysis on benchmark results
 func (pa *PerformanceAnalyzer) AnalyzeResults(result *types.BenchmarkResult) {
+	view := result.WithLatencyMode(pa.latencyMode)
+
+	// Normalize performance by the resources each client used
+	result.Efficiency = pa.calculateEfficiency(view.ClientMetrics, parseRunDuration(result.Duration))
+
 	// Calculate performance scores
-	result.PerformanceScore = pa.calculatePerformanceScores(result.ClientMetrics)
+	result.PerformanceScore = pa.calculatePerformanceScores(view.ClientMetrics)
+	pa.applyEfficiencyScores(result.PerformanceScore, result.Efficiency)
 
 	// Perform comparison analysis
-	result.Comparison = pa.compareClients(result.ClientMetrics, result.PerformanceScore)
+	result.Comparison = pa.compareClients(view.ClientMetrics, result.PerformanceScore)
+
+	// Relate latency spikes to generator resource saturation
+	result.ResourceCorrelations = pa.correlateResources(result)
 
 	// Generate recommendations
 	result.Recommendations = pa.generateRecommendations(result)
@@ -295,11 +317,24 @@ func (pa *PerformanceAnalyzer) generateRecommendations(result *types.BenchmarkRe
 					name, client.ErrorRate))
 		}
 
+		// Explain failures by class so an error rate reads at a glance
+		if breakdown := errorClassBreakdown(client, 3); breakdown != "" {
+			recommendations = append(recommendations,
+				fmt.Sprintf("[ERRORS] %s: Failures by class: %s.", name, breakdown))
+		}
+
 		// High latency recommendations
-		if client.Latency.P95 > 1000 {
+		if p95 := client.Latency.WithLatencyMode(pa.latencyMode).P95; p95 > 1000 {
 			recommendations = append(recommendations,
 				fmt.Sprintf("[LATENCY] %s: High P95 latency (%.0fms). Investigate slow queries, database locks, or resource constraints.",
-					name, client.Latency.P95))
+					name, p95))
+		}
+
+		// Coordinated omission: requests left later than scheduled
+		if client.Latency.HasCorrected() && client.Latency.P99 > 0 && client.Latency.CorrectedP99 > 2*client.Latency.P99 {
+			recommendations = append(recommendations,
+				fmt.Sprintf("[GENERATOR] %s: Corrected P99 latency (%.0fms) is %.1fx the raw P99 (%.0fms). The load generator fell behind schedule; raw latency understates queueing. Add VUs or lower the rate.",
+					name, client.Latency.CorrectedP99, client.Latency.CorrectedP99/client.Latency.P99, client.Latency.P99))
 		}
 
 		// Connection efficiency
@@ -330,6 +365,45 @@ func (pa *PerformanceAnalyzer) generateRecommendations(result *types.BenchmarkRe
 		}
 	}
 
+	// Paired comparison across all methods: who wins on identical payloads
+	for _, paired := range result.PairedComparisons {
+		if paired.Method != "" || !paired.Significant {
+			continue
+		}
+		loser := paired.ClientB
+		ratio := paired.RatioP50
+		if paired.Winner == paired.ClientB {
+			loser = paired.ClientA
+			if ratio > 0 {
+				ratio = 1 / ratio
+			}
+		}
+		recommendations = append(recommendations,
+			fmt.Sprintf("[PAIRED] %s is faster than %s on %.1f%% of %d identical requests (median latency ratio %.2f, p=%.3g).",
+				paired.Winner, loser, paired.WinnerShare(), paired.Pairs, ratio, paired.PValue))
+	}
+
+	// Efficiency gaps that raw throughput and latency don't show
+	if best, worst := efficiencyExtremes(result.Efficiency); best != worst {
+		bestEff, worstEff := result.Efficiency[best], result.Efficiency[worst]
+		if worstEff.RequestsPerCPUSecond > 0 && bestEff.RequestsPerCPUSecond > 1.5*worstEff.RequestsPerCPUSecond {
+			recommendations = append(recommendations,
+				fmt.Sprintf("[EFFICIENCY] %s serves %.1fx the requests per CPU-second of %s (%.0f vs %.0f).",
+					best, bestEff.RequestsPerCPUSecond/worstEff.RequestsPerCPUSecond, worst,
+					bestEff.RequestsPerCPUSecond, worstEff.RequestsPerCPUSecond))
+		}
+	}
+
+	// Latency spikes that line up with a saturated load generator
+	for _, correlation := range result.ResourceCorrelations {
+		if !correlation.Flagged {
+			continue
+		}
+		recommendations = append(recommendations,
+			fmt.Sprintf("[GENERATOR] %s: %d of %d latency spikes coincide with generator %s saturation (r=%.2f). The load generator, not the client, may explain them.",
+				correlation.Client, correlation.SaturatedSpikes, correlation.Spikes, correlation.Resource, correlation.Pearson))
+	}
+
 	// System-level recommendations
 	if result.Environment.CPUCores < 4 {
 		recommendations = append(recommendations,
@@ -351,6 +425,12 @@ func (pa *PerformanceAnalyzer) generateRecommendations(result *types.BenchmarkRe
 func (pa *PerformanceAnalyzer) DetectRegression(current, baseline *types.BenchmarkResult) []string {
 	var regressions []string
 
+	// Only switch to corrected latency when both runs recorded it
+	if current.HasCorrectedLatency() && baseline.HasCorrectedLatency() {
+		current = current.WithLatencyMode(pa.latencyMode)
+		baseline = baseline.WithLatencyMode(pa.latencyMode)
+	}
+
 	for name, currentClient := range current.ClientMetrics {
 		if baselineClient, exists := baseline.ClientMetrics[name]; exists {
 			// Check latency regression
@@ -385,3 +465,38 @@ func (pa *PerformanceAnalyzer) DetectRegression(current, baseline *types.Benchma
 
 	return regressions
 }
+
+// errorClassBreakdown renders the client's most frequent error classes as
+// "class N (P%)" entries, largest first, where P is the share of all requests
+func errorClassBreakdown(client *types.ClientMetrics, top int) string {
+	if len(client.ErrorClasses) == 0 || client.TotalRequests == 0 {
+		return ""
+	}
+	type classCount struct {
+		class string
+		count int64
+	}
+	counts := make([]classCount, 0, len(client.ErrorClasses))
+	for class, count := range client.ErrorClasses {
+		if count > 0 {
+			counts = append(counts, classCount{class, count})
+		}
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].count != counts[j].count {
+			return counts[i].count > counts[j].count
+		}
+		return counts[i].class < counts[j].class
+	})
+
+	var parts []string
+	for i, c := range counts {
+		if i == top {
+			parts = append(parts, fmt.Sprintf("%d more class(es)", len(counts)-top))
+			break
+		}
+		parts = append(parts, fmt.Sprintf("%s %d (%.1f%%)", c.class, c.count,
+			float64(c.count)/float64(client.TotalRequests)*100))
+	}
+	return strings.Join(parts, ", ")
+}
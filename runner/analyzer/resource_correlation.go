@@ -0,0 +1,190 @@
+package analyzer
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+const (
+	// spikeFactor marks an interval as a latency spike when its mean latency
+	// exceeds this multiple of the run's median
+	spikeFactor = 2.0
+	// saturationPercent is the CPU and memory use treated as saturated
+	saturationPercent = 90.0
+	// networkSaturationShare treats network samples within this share of the
+	// run's peak as saturated; the link capacity is unknown
+	networkSaturationShare = 0.9
+	// minCorrelationSamples is the fewest aligned samples worth correlating
+	minCorrelationSamples = 5
+	// maxAlignmentGapMs is how far a system sample may sit from a latency
+	// interval and still be paired with it
+	maxAlignmentGapMs = 1500
+)
+
+// correlateResources relates every client's latency timeline to the
+// generator's CPU, memory and network series. It needs both the system series
+// and types.TimeSeriesLatencyAvg timelines; otherwise it returns nil.
+func (pa *PerformanceAnalyzer) correlateResources(result *types.BenchmarkResult) []types.ResourceCorrelation {
+	system := make([]types.SystemMetrics, 0, len(result.SystemMetrics))
+	for _, sample := range result.SystemMetrics {
+		if sample.Timestamp > 0 {
+			system = append(system, sample)
+		}
+	}
+	if len(system) == 0 {
+		return nil
+	}
+	sort.Slice(system, func(i, j int) bool { return system[i].Timestamp < system[j].Timestamp })
+
+	var peakNetwork float64
+	for _, sample := range system {
+		peakNetwork = math.Max(peakNetwork, networkBytes(sample))
+	}
+
+	names := make([]string, 0, len(result.ClientMetrics))
+	for name := range result.ClientMetrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var correlations []types.ResourceCorrelation
+	for _, name := range names {
+		points := result.ClientMetrics[name].TimeSeries[types.TimeSeriesLatencyAvg]
+
+		var latencies []float64
+		var aligned []types.SystemMetrics
+		for _, point := range points {
+			if point.Count == 0 {
+				continue
+			}
+			if sample, ok := nearestSample(system, point.Timestamp); ok {
+				latencies = append(latencies, point.Value)
+				aligned = append(aligned, sample)
+			}
+		}
+		if len(latencies) < minCorrelationSamples {
+			continue
+		}
+
+		threshold := spikeFactor * median(latencies)
+		resources := []struct {
+			name      string
+			value     func(types.SystemMetrics) float64
+			saturated func(types.SystemMetrics) bool
+		}{
+			{types.ResourceCPU,
+				func(s types.SystemMetrics) float64 { return s.HostCPUPercent },
+				func(s types.SystemMetrics) bool { return s.HostCPUPercent >= saturationPercent }},
+			{types.ResourceMemory,
+				func(s types.SystemMetrics) float64 { return s.MemoryPercent },
+				func(s types.SystemMetrics) bool { return s.MemoryPercent >= saturationPercent }},
+			{types.ResourceNetwork,
+				networkBytes,
+				func(s types.SystemMetrics) bool {
+					return peakNetwork > 0 && networkBytes(s) >= networkSaturationShare*peakNetwork
+				}},
+		}
+
+		for _, resource := range resources {
+			values := make([]float64, len(aligned))
+			for i, sample := range aligned {
+				values[i] = resource.value(sample)
+			}
+			correlation := types.ResourceCorrelation{
+				Client:   name,
+				Resource: resource.name,
+				Pearson:  pearson(latencies, values),
+				Samples:  len(latencies),
+			}
+			for i, latency := range latencies {
+				if latency <= threshold {
+					continue
+				}
+				correlation.Spikes++
+				if resource.saturated(aligned[i]) {
+					correlation.SaturatedSpikes++
+				}
+			}
+			correlation.Flagged = correlation.Spikes > 0 && correlation.SaturatedSpikes*2 > correlation.Spikes
+			// Network is judged against its own peak, which every run has, so
+			// also require latency to actually move with it
+			if resource.name == types.ResourceNetwork && correlation.Pearson < 0.5 {
+				correlation.Flagged = false
+			}
+			correlations = append(correlations, correlation)
+		}
+	}
+	return correlations
+}
+
+// nearestSample returns the system sample closest to timestamp (Unix ms) if it
+// lies within maxAlignmentGapMs; samples must be sorted by timestamp
+func nearestSample(samples []types.SystemMetrics, timestamp int64) (types.SystemMetrics, bool) {
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].Timestamp >= timestamp })
+	best := -1
+	var bestGap int64
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= len(samples) {
+			continue
+		}
+		gap := samples[j].Timestamp - timestamp
+		if gap < 0 {
+			gap = -gap
+		}
+		if best < 0 || gap < bestGap {
+			best, bestGap = j, gap
+		}
+	}
+	if best < 0 || bestGap > maxAlignmentGapMs {
+		return types.SystemMetrics{}, false
+	}
+	return samples[best], true
+}
+
+// networkBytes is the bytes sent and received during one sample interval
+func networkBytes(sample types.SystemMetrics) float64 {
+	return float64(sample.NetworkBytesSent + sample.NetworkBytesRecv)
+}
+
+// pearson returns the correlation coefficient of x and y, or 0 when either is
+// constant
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	if n == 0 {
+		return 0
+	}
+	var sumX, sumY float64
+	for i := range x {
+		sumX += x[i]
+		sumY += y[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// median returns the median of values without modifying them
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+func TestPearson(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []float64
+		want float64
+	}{
+		// cov 6, variances 10 and 6
+		{"known fixture", []float64{1, 2, 3, 4, 5}, []float64{2, 4, 5, 4, 5}, 6 / math.Sqrt(60)},
+		{"perfect", []float64{1, 2, 3}, []float64{10, 20, 30}, 1},
+		{"inverse", []float64{1, 2, 3}, []float64{30, 20, 10}, -1},
+		{"zero variance x", []float64{5, 5, 5}, []float64{1, 2, 3}, 0},
+		{"zero variance y", []float64{1, 2, 3}, []float64{7, 7, 7}, 0},
+		{"single point", []float64{1}, []float64{2}, 0},
+		{"empty", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pearson(tt.x, tt.y); !approx(got, tt.want) {
+				t.Errorf("pearson = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// correlationResult builds a run whose client latency timeline and generator
+// samples are one second apart, the samples shifted by offsetMs
+func correlationResult(latencies, cpu []float64, offsetMs int64) *types.BenchmarkResult {
+	const start = int64(1735732800000)
+	result := &types.BenchmarkResult{
+		ClientMetrics: map[string]*types.ClientMetrics{"geth": {TimeSeries: map[string][]types.TimeSeriesPoint{}}},
+	}
+	for i, latency := range latencies {
+		ts := start + int64(i)*1000
+		result.ClientMetrics["geth"].TimeSeries[types.TimeSeriesLatencyAvg] = append(
+			result.ClientMetrics["geth"].TimeSeries[types.TimeSeriesLatencyAvg],
+			types.TimeSeriesPoint{Timestamp: ts, Value: latency, Count: 100})
+		result.SystemMetrics = append(result.SystemMetrics, types.SystemMetrics{
+			Timestamp:        ts + offsetMs,
+			HostCPUPercent:   cpu[i],
+			MemoryPercent:    40,
+			NetworkBytesSent: 100,
+		})
+	}
+	return result
+}
+
+func TestCorrelateResources(t *testing.T) {
+	pa := NewPerformanceAnalyzer()
+	latencies := []float64{10, 10, 10, 10, 50, 50}
+	cpu := []float64{20, 20, 20, 20, 95, 95}
+
+	correlations := pa.correlateResources(correlationResult(latencies, cpu, 200))
+	if len(correlations) != 3 {
+		t.Fatalf("got %d correlations, want CPU, memory and network", len(correlations))
+	}
+	byResource := make(map[string]types.ResourceCorrelation)
+	for _, c := range correlations {
+		byResource[c.Resource] = c
+	}
+
+	// Both spikes coincide with saturated CPU
+	c := byResource[types.ResourceCPU]
+	if !approx(c.Pearson, 1) || c.Samples != 6 || c.Spikes != 2 || c.SaturatedSpikes != 2 || !c.Flagged {
+		t.Errorf("cpu = %+v", c)
+	}
+	// Constant memory has no variance and is never saturated
+	if c := byResource[types.ResourceMemory]; c.Pearson != 0 || c.SaturatedSpikes != 0 || c.Flagged {
+		t.Errorf("memory = %+v", c)
+	}
+	// Constant traffic sits at its own peak, but latency does not move with it
+	if c := byResource[types.ResourceNetwork]; c.Pearson != 0 || c.SaturatedSpikes != 2 || c.Flagged {
+		t.Errorf("network = %+v", c)
+	}
+}
+
+func TestCorrelateResourcesDegenerate(t *testing.T) {
+	pa := NewPerformanceAnalyzer()
+	latencies := []float64{10, 10, 10, 10, 50, 50}
+	cpu := []float64{20, 20, 20, 20, 95, 95}
+
+	if got := pa.correlateResources(correlationResult(latencies[:minCorrelationSamples-1], cpu, 0)); got != nil {
+		t.Errorf("fewer than %d samples: got %+v", minCorrelationSamples, got)
+	}
+	// Samples taken after the timeline ended pair with none of its points
+	late := int64(len(latencies))*1000 + maxAlignmentGapMs
+	if got := pa.correlateResources(correlationResult(latencies, cpu, late)); got != nil {
+		t.Errorf("samples too far from the timeline should not align: got %+v", got)
+	}
+	noSystem := correlationResult(latencies, cpu, 0)
+	noSystem.SystemMetrics = nil
+	if got := pa.correlateResources(noSystem); got != nil {
+		t.Errorf("without system samples: got %+v", got)
+	}
+}
@@ -880,6 +880,15 @@ func (h *apiHandlers) HandleDetectRegressions(w http.ResponseWriter, r *http.Req
 		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	latencyMode, err := types.ParseLatencyMode(req.LatencyMode)
+	if err != nil {
+		h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if latencyMode == types.LatencyModeCorrected && req.ComparisonMode == "baseline" {
+		h.writeErrorResponse(w, http.StatusBadRequest, "corrected latency mode is not supported for baseline comparison mode")
+		return
+	}
 	if req.BaselineName != "" {
 		if err := ValidateID("baseline_name", req.BaselineName); err != nil {
 			h.writeErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -935,6 +944,7 @@ func (h *apiHandlers) HandleDetectRegressions(w http.ResponseWriter, r *http.Req
 		ExcludeMethods:     req.ExcludeMethods,
 		MinConfidence:      req.MinConfidence,
 		IgnoreImprovements: req.IgnoreImprovements,
+		LatencyMode:        latencyMode,
 	}
 
 	report, err := h.regressionDetector.DetectRegressions(ctx, runID, options)
@@ -1384,6 +1394,7 @@ type RegressionDetectionRequest struct {
 	ExcludeMethods     []string                                `json:"exclude_methods,omitempty"`
 	MinConfidence      float64                                 `json:"min_confidence,omitempty"`
 	IgnoreImprovements bool                                    `json:"ignore_improvements"`
+	LatencyMode        string                                  `json:"latency_mode,omitempty"`
 }
 
 // AcknowledgeRegressionRequest represents a request to acknowledge a regression
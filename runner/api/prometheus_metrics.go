@@ -0,0 +1,282 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/jsonrpc-bench/runner/analysis"
+	"github.com/jsonrpc-bench/runner/internal/sanitize"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// metricsScrapeTimeout bounds the database queries behind one scrape
+const metricsScrapeTimeout = 10 * time.Second
+
+// regressionSeverities are always exported so alerts can compare against zero
+var regressionSeverities = []string{"critical", "major", "minor"}
+
+// latencyQuantiles maps stored latency metrics to the quantile label
+var latencyQuantiles = map[string]string{
+	types.MetricLatencyP50: "0.5",
+	types.MetricLatencyP95: "0.95",
+	types.MetricLatencyP99: "0.99",
+}
+
+// runMetricKey identifies one stored value of a run
+type runMetricKey struct {
+	client string
+	method string
+	metric string
+}
+
+// latestRun holds the stored metrics of the most recent run of a test
+type latestRun struct {
+	testName  string
+	runID     string
+	timestamp time.Time
+	values    map[runMetricKey]float64
+}
+
+// metricsSnapshot is everything one scrape exports
+type metricsSnapshot struct {
+	runs            []latestRun
+	openRegressions map[string]int
+	baselines       map[string][]*analysis.Baseline // by test name
+}
+
+// metricsCollector exports the latest stored runs as Prometheus gauges. It
+// loads a fresh snapshot on every scrape, so new runs show up without a
+// refresh loop.
+type metricsCollector struct {
+	load func(ctx context.Context) (*metricsSnapshot, error)
+	log  logrus.FieldLogger
+
+	runTimestamp    *prometheus.Desc
+	latency         *prometheus.Desc
+	errorRate       *prometheus.Desc
+	throughput      *prometheus.Desc
+	openRegressions *prometheus.Desc
+	baselineDelta   *prometheus.Desc
+}
+
+func newMetricsCollector(load func(ctx context.Context) (*metricsSnapshot, error), log logrus.FieldLogger) *metricsCollector {
+	return &metricsCollector{
+		load: load,
+		log:  log,
+		runTimestamp: prometheus.NewDesc("jsonrpc_bench_latest_run_timestamp_seconds",
+			"Start time of the latest run of a test.", []string{"test", "run_id"}, nil),
+		latency: prometheus.NewDesc("jsonrpc_bench_latency_seconds",
+			"Request latency quantiles of the latest run; method \"all\" is the client aggregate.",
+			[]string{"test", "client", "method", "quantile"}, nil),
+		errorRate: prometheus.NewDesc("jsonrpc_bench_error_rate_percent",
+			"Failed requests as a percentage of all requests in the latest run.",
+			[]string{"test", "client", "method"}, nil),
+		throughput: prometheus.NewDesc("jsonrpc_bench_requests_per_second",
+			"Throughput of the latest run.", []string{"test", "client", "method"}, nil),
+		openRegressions: prometheus.NewDesc("jsonrpc_bench_open_regressions",
+			"Unacknowledged regressions by severity.", []string{"severity"}, nil),
+		baselineDelta: prometheus.NewDesc("jsonrpc_bench_baseline_delta_percent",
+			"Percent change of the latest run against each active baseline of its test.",
+			[]string{"test", "baseline", "client", "metric"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.runTimestamp
+	ch <- c.latency
+	ch <- c.errorRate
+	ch <- c.throughput
+	ch <- c.openRegressions
+	ch <- c.baselineDelta
+}
+
+// Collect implements prometheus.Collector. A failed load is logged and
+// whatever was loaded before the failure is still exported.
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsScrapeTimeout)
+	defer cancel()
+
+	snapshot, err := c.load(ctx)
+	if err != nil {
+		c.log.WithError(sanitize.LogError(err)).Warn("Failed to load metrics for scrape")
+	}
+	if snapshot == nil {
+		return
+	}
+
+	for _, run := range snapshot.runs {
+		ch <- prometheus.MustNewConstMetric(c.runTimestamp, prometheus.GaugeValue,
+			float64(run.timestamp.Unix()), run.testName, run.runID)
+
+		for key, value := range run.values {
+			switch key.metric {
+			case types.MetricErrorRate:
+				ch <- prometheus.MustNewConstMetric(c.errorRate, prometheus.GaugeValue, value, run.testName, key.client, key.method)
+			case types.MetricThroughput:
+				ch <- prometheus.MustNewConstMetric(c.throughput, prometheus.GaugeValue, value, run.testName, key.client, key.method)
+			default:
+				if quantile, ok := latencyQuantiles[key.metric]; ok {
+					ch <- prometheus.MustNewConstMetric(c.latency, prometheus.GaugeValue, value/1000, run.testName, key.client, key.method, quantile)
+				}
+			}
+		}
+
+		for _, baseline := range snapshot.baselines[run.testName] {
+			for key, delta := range baselineDeltas(run, baseline.BaselineMetrics) {
+				ch <- prometheus.MustNewConstMetric(c.baselineDelta, prometheus.GaugeValue, delta,
+					run.testName, baseline.Name, key.client, key.metric)
+			}
+		}
+	}
+
+	for _, severity := range regressionSeverities {
+		ch <- prometheus.MustNewConstMetric(c.openRegressions, prometheus.GaugeValue,
+			float64(snapshot.openRegressions[severity]), severity)
+	}
+	for severity, count := range snapshot.openRegressions {
+		if !isKnownSeverity(severity) {
+			ch <- prometheus.MustNewConstMetric(c.openRegressions, prometheus.GaugeValue, float64(count), severity)
+		}
+	}
+}
+
+// baselineDeltas compares the client aggregates of a run with a baseline.
+// Metrics the baseline recorded as zero have no percent change and are
+// skipped.
+func baselineDeltas(run latestRun, baseline analysis.BaselineMetrics) map[runMetricKey]float64 {
+	deltas := make(map[runMetricKey]float64)
+	for client, base := range baseline.ClientMetrics {
+		for _, pair := range []struct {
+			metric   string
+			baseline float64
+		}{
+			{types.MetricLatencyAvg, base.AvgLatency},
+			{types.MetricLatencyP95, base.P95Latency},
+			{types.MetricLatencyP99, base.P99Latency},
+			{types.MetricErrorRate, base.ErrorRate},
+			{types.MetricThroughput, base.Throughput},
+		} {
+			current, ok := run.values[runMetricKey{client: client, method: "all", metric: pair.metric}]
+			if !ok || pair.baseline == 0 {
+				continue
+			}
+			deltas[runMetricKey{client: client, method: "all", metric: pair.metric}] = (current - pair.baseline) / pair.baseline * 100
+		}
+	}
+	return deltas
+}
+
+func isKnownSeverity(severity string) bool {
+	for _, known := range regressionSeverities {
+		if severity == known {
+			return true
+		}
+	}
+	return false
+}
+
+// metricsHandler serves the collector from a dedicated registry, so the
+// endpoint only carries benchmark data
+func (s *server) metricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(newMetricsCollector(s.loadMetricsSnapshot, s.log))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// loadMetricsSnapshot reads the latest run of every test, open regressions
+// and active baselines
+func (s *server) loadMetricsSnapshot(ctx context.Context) (*metricsSnapshot, error) {
+	snapshot := &metricsSnapshot{
+		openRegressions: make(map[string]int),
+		baselines:       make(map[string][]*analysis.Baseline),
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (test_name) id, test_name, timestamp
+		FROM benchmark_runs
+		WHERE test_name IS NOT NULL AND test_name != ''
+		ORDER BY test_name, timestamp DESC`)
+	if err != nil {
+		return snapshot, fmt.Errorf("query latest runs: %w", err)
+	}
+	for rows.Next() {
+		var run latestRun
+		if err := rows.Scan(&run.runID, &run.testName, &run.timestamp); err != nil {
+			rows.Close()
+			return snapshot, fmt.Errorf("scan latest run: %w", err)
+		}
+		snapshot.runs = append(snapshot.runs, run)
+	}
+	rows.Close()
+
+	for i := range snapshot.runs {
+		values, err := s.loadRunMetrics(ctx, snapshot.runs[i].runID)
+		if err != nil {
+			return snapshot, err
+		}
+		snapshot.runs[i].values = values
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT severity, COUNT(*)
+		FROM regressions
+		WHERE acknowledged_at IS NULL
+		GROUP BY severity`)
+	if err != nil {
+		return snapshot, fmt.Errorf("query open regressions: %w", err)
+	}
+	for rows.Next() {
+		var severity string
+		var count int
+		if err := rows.Scan(&severity, &count); err != nil {
+			rows.Close()
+			return snapshot, fmt.Errorf("scan open regressions: %w", err)
+		}
+		snapshot.openRegressions[severity] = count
+	}
+	rows.Close()
+
+	if s.baselineManager != nil {
+		baselines, err := s.baselineManager.ListBaselines(ctx, "")
+		if err != nil {
+			return snapshot, fmt.Errorf("list baselines: %w", err)
+		}
+		for _, baseline := range baselines {
+			snapshot.baselines[baseline.TestName] = append(snapshot.baselines[baseline.TestName], baseline)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// loadRunMetrics reads the exported metrics of a run from benchmark_metrics
+func (s *server) loadRunMetrics(ctx context.Context, runID string) (map[runMetricKey]float64, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT client, method, metric_name, value
+		FROM benchmark_metrics
+		WHERE run_id = $1 AND metric_name IN ($2, $3, $4, $5, $6, $7)`,
+		runID, types.MetricLatencyAvg, types.MetricLatencyP50, types.MetricLatencyP95,
+		types.MetricLatencyP99, types.MetricErrorRate, types.MetricThroughput)
+	if err != nil {
+		return nil, fmt.Errorf("query metrics of run %s: %w", runID, err)
+	}
+	defer rows.Close()
+
+	values := make(map[runMetricKey]float64)
+	for rows.Next() {
+		var key runMetricKey
+		var value float64
+		if err := rows.Scan(&key.client, &key.method, &key.metric, &value); err != nil {
+			return nil, fmt.Errorf("scan metrics of run %s: %w", runID, err)
+		}
+		values[key] = value
+	}
+	return values, rows.Err()
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+
+	"github.com/jsonrpc-bench/runner/analysis"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+func scrape(t *testing.T, collector prometheus.Collector) string {
+	t.Helper()
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector)
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMetricsCollector_ExportsLatestRunRegressionsAndBaselines(t *testing.T) {
+	snapshot := &metricsSnapshot{
+		runs: []latestRun{{
+			testName:  "mixed",
+			runID:     "run-2",
+			timestamp: time.Unix(1700000000, 0),
+			values: map[runMetricKey]float64{
+				{"geth", "all", types.MetricLatencyP99}:            250,
+				{"geth", "all", types.MetricErrorRate}:             1.5,
+				{"geth", "all", types.MetricThroughput}:            120,
+				{"geth", "eth_call", types.MetricLatencyP50}:       20,
+				{"geth", "all", types.MetricLatencyAvg}:            40,
+				{"nethermind", "all", types.MetricLatencyP99}:      100,
+				{"nethermind", "all", types.MetricThroughput}:      90,
+				{"nethermind", "eth_call", types.MetricThroughput}: 30,
+			},
+		}},
+		openRegressions: map[string]int{"critical": 2},
+		baselines: map[string][]*analysis.Baseline{
+			"mixed": {{
+				Name: "release",
+				BaselineMetrics: analysis.BaselineMetrics{
+					ClientMetrics: map[string]analysis.ClientBaseline{
+						"geth":       {P99Latency: 200, AvgLatency: 50, ErrorRate: 0},
+						"nethermind": {Throughput: 100},
+					},
+				},
+			}},
+		},
+	}
+	collector := newMetricsCollector(func(ctx context.Context) (*metricsSnapshot, error) {
+		return snapshot, nil
+	}, logrus.New())
+
+	body := scrape(t, collector)
+	for _, want := range []string{
+		`jsonrpc_bench_latest_run_timestamp_seconds{run_id="run-2",test="mixed"} 1.7e+09`,
+		`jsonrpc_bench_latency_seconds{client="geth",method="all",quantile="0.99",test="mixed"} 0.25`,
+		`jsonrpc_bench_latency_seconds{client="geth",method="eth_call",quantile="0.5",test="mixed"} 0.02`,
+		`jsonrpc_bench_error_rate_percent{client="geth",method="all",test="mixed"} 1.5`,
+		`jsonrpc_bench_requests_per_second{client="nethermind",method="eth_call",test="mixed"} 30`,
+		`jsonrpc_bench_open_regressions{severity="critical"} 2`,
+		`jsonrpc_bench_open_regressions{severity="minor"} 0`,
+		`jsonrpc_bench_baseline_delta_percent{baseline="release",client="geth",metric="latency_p99",test="mixed"} 25`,
+		`jsonrpc_bench_baseline_delta_percent{baseline="release",client="geth",metric="latency_avg",test="mixed"} -20`,
+		`jsonrpc_bench_baseline_delta_percent{baseline="release",client="nethermind",metric="throughput",test="mixed"} -10`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %s in:\n%s", want, body)
+		}
+	}
+	// A zero baseline has no percent change
+	if strings.Contains(body, `metric="error_rate"`) {
+		t.Errorf("unexpected error_rate delta against a zero baseline:\n%s", body)
+	}
+}
+
+func TestMetricsCollector_ExportsPartialSnapshotOnError(t *testing.T) {
+	collector := newMetricsCollector(func(ctx context.Context) (*metricsSnapshot, error) {
+		return &metricsSnapshot{openRegressions: map[string]int{"major": 1}}, errors.New("baselines unavailable")
+	}, logrus.New())
+
+	body := scrape(t, collector)
+	if !strings.Contains(body, `jsonrpc_bench_open_regressions{severity="major"} 1`) {
+		t.Fatalf("expected regressions loaded before the error, got:\n%s", body)
+	}
+}
@@ -191,6 +191,9 @@ func (s *server) setupRoutes() *mux.Router {
 	// Health check endpoint
 	router.HandleFunc("/health", s.handleHealth).Methods("GET")
 
+	// Prometheus scrape endpoint
+	router.Handle("/metrics", s.metricsHandler()).Methods("GET")
+
 	return router
 }
 
@@ -816,6 +819,11 @@ func (s *server) handleDetectRegressions(w http.ResponseWriter, r *http.Request)
 		s.writeErrorResponse(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	latencyMode, err := types.ParseLatencyMode(req.LatencyMode)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if req.BaselineName != "" {
 		if err := ValidateID("baseline_name", req.BaselineName); err != nil {
 			s.writeErrorResponse(w, http.StatusBadRequest, err.Error())
@@ -842,6 +850,7 @@ func (s *server) handleDetectRegressions(w http.ResponseWriter, r *http.Request)
 		EnableStatistical:  true,
 		MinConfidence:      0.95,
 		IgnoreImprovements: false,
+		LatencyMode:        latencyMode,
 	}
 
 	report, err := s.regressionDetector.DetectRegressions(ctx, runID, options)
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -30,6 +31,17 @@ var (
 	benchmarkEnableHistoric    bool
 	benchmarkStorageConfigPath string
 	benchmarkHTMLReport        bool
+	benchmarkLatencyMode       string
+	benchmarkSeed              int64
+	benchmarkCaptureRequests   bool
+	benchmarkSlowestN          int
+	benchmarkOTLPEndpoint      string
+	benchmarkOTLPProtocol      string
+	benchmarkOTLPSpans         int
+	benchmarkRemoteWriteURL    string
+	benchmarkRemoteWriteUser   string
+	benchmarkRemoteWritePass   string
+	benchmarkColumnar          string
 )
 
 var benchmarkCmd = &cobra.Command{
@@ -48,10 +60,23 @@ func init() {
 	benchmarkCmd.Flags().BoolVar(&benchmarkEnableHistoric, "historic", false, "Persist this run to historic storage")
 	benchmarkCmd.Flags().StringVar(&benchmarkStorageConfigPath, "storage-config", "", "Path to storage configuration file (required with --historic)")
 	benchmarkCmd.Flags().BoolVar(&benchmarkHTMLReport, "html-report", false, "Generate the HTML benchmark report in addition to JSON/CSV")
+	benchmarkCmd.Flags().BoolVar(&benchmarkCaptureRequests, "capture-requests", false, "Record every request's samples to a k6 JSON stream for per-request analysis (large; off by default)")
+	benchmarkCmd.Flags().IntVar(&benchmarkSlowestN, "slowest", 10, "With --capture-requests, number of slowest requests kept per client and method")
+	benchmarkCmd.Flags().StringVar(&benchmarkOTLPEndpoint, "otlp-endpoint", "", "OpenTelemetry collector URL to export results to over OTLP, e.g. http://localhost:4317 (optional)")
+	benchmarkCmd.Flags().StringVar(&benchmarkOTLPProtocol, "otlp-protocol", exporter.OTLPProtocolGRPC, "OTLP transport: grpc or http")
+	benchmarkCmd.Flags().IntVar(&benchmarkOTLPSpans, "otlp-spans", 0, "With --capture-requests and --otlp-endpoint, number of requests sampled as spans")
+	benchmarkCmd.Flags().StringVar(&benchmarkRemoteWriteURL, "remote-write", "", "Prometheus remote-write URL the final run summary is pushed to, e.g. http://mimir:9009/api/v1/push (optional; independent of --prometheus)")
+	benchmarkCmd.Flags().StringVar(&benchmarkRemoteWriteUser, "remote-write-user", "", "Basic-auth username for --remote-write (optional)")
+	benchmarkCmd.Flags().StringVar(&benchmarkRemoteWritePass, "remote-write-pass", "", "Basic-auth password for --remote-write (optional)")
+	benchmarkCmd.Flags().StringVar(&benchmarkColumnar, "columnar", "", "Also export results as columnar tables: parquet, arrow or parquet,arrow (optional)")
+	benchmarkCmd.Flags().Int64Var(&benchmarkSeed, "seed", 0, "Seed for drawing the generated requests (overrides the config's seed; 0 = config seed, else random). Recorded in manifest.json")
+	benchmarkCmd.Flags().StringVar(&benchmarkLatencyMode, "latency-mode", string(types.LatencyModeRaw), "Latency used for scoring and the HTML report: raw (from send time) or corrected (from scheduled start, constant-arrival-rate only)")
 }
 
 func runBenchmark(cmd *cobra.Command, args []string) error {
 	configureLogger()
+	// Files of the output directory modified from here on belong to this run
+	runStart := time.Now()
 
 	if benchmarkConfigPath == "" {
 		return fmt.Errorf("--config is required")
@@ -59,6 +84,18 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 	if benchmarkEnableHistoric && benchmarkStorageConfigPath == "" {
 		return fmt.Errorf("--storage-config is required when --historic is set")
 	}
+	latencyMode, err := types.ParseLatencyMode(benchmarkLatencyMode)
+	if err != nil {
+		return fmt.Errorf("--latency-mode: %w", err)
+	}
+	if benchmarkOTLPSpans > 0 && (!benchmarkCaptureRequests || benchmarkOTLPEndpoint == "") {
+		return fmt.Errorf("--otlp-spans requires --capture-requests and --otlp-endpoint")
+	}
+
+	columnarFormats, err := exporter.ParseColumnarFormats(benchmarkColumnar)
+	if err != nil {
+		return fmt.Errorf("--columnar: %w", err)
+	}
 
 	registry, err := loadClientRegistry(benchmarkClientsPath)
 	if err != nil {
@@ -69,6 +106,17 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	if benchmarkSeed != 0 {
+		cfg.Seed = benchmarkSeed
+	}
+
+	var otlpExporter *exporter.OTLPExporter
+	if benchmarkOTLPEndpoint != "" {
+		otlpExporter, err = exporter.NewOTLPExporter(benchmarkOTLPEndpoint, benchmarkOTLPProtocol, cfg.TestName)
+		if err != nil {
+			return fmt.Errorf("--otlp-protocol: %w", err)
+		}
+	}
 
 	cfg.Outputs = &config.Outputs{}
 	if benchmarkPrometheusURL != "" {
@@ -91,6 +139,14 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	if benchmarkCaptureRequests {
+		streamPath, err := filepath.Abs(filepath.Join(outputDir, generator.K6RequestStreamFilename))
+		if err != nil {
+			return fmt.Errorf("failed to resolve request stream path: %w", err)
+		}
+		cfg.Outputs.RequestStream = &config.RequestStream{Path: streamPath}
+	}
+
 	var historic *storage.HistoricStorage
 	if benchmarkEnableHistoric {
 		h, db, err := openHistoricStorage(benchmarkStorageConfigPath)
@@ -115,10 +171,25 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		defer systemCollector.Stop()
 	}
 
+	clientResources := metrics.NewClientResourceCollector(cfg.ResolvedClients, 1*time.Second, logger)
+	if clientResources != nil {
+		clientResources.Start()
+		defer clientResources.Stop()
+	}
+
+	clientVersions := metrics.FetchClientVersions(cfg.ResolvedClients, 5*time.Second, logger)
+
 	logger.Info("Running benchmark")
 	startTime := time.Now()
 	runErr := k6Cmd.Run()
 	endTime := time.Now()
+	if systemCollector != nil {
+		// Keep the resource series to the run itself
+		systemCollector.Stop()
+	}
+	if clientResources != nil {
+		clientResources.Stop()
+	}
 	testDuration := endTime.Sub(startTime)
 	if runErr != nil {
 		logger.WithError(runErr).Warn("K6 command execution completed with errors")
@@ -141,9 +212,15 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		logger.WithError(err).Warn("Failed to collect benchmark clients metrics")
 	}
 
+	metrics.ApplyBandwidth(clientsMetrics, testDuration)
+	metrics.ApplyClientVersions(clientsMetrics, clientVersions)
+	if clientResources != nil {
+		metrics.ApplyClientResources(clientsMetrics, clientResources.GetResources(), testDuration)
+	}
 	logP99Validation(clientsMetrics)
 
 	benchmarkResults := &types.BenchmarkResult{
+		SchemaVersion: types.ResultSchemaVersion,
 		Summary:       k6Summary,
 		ClientMetrics: clientsMetrics,
 		Timestamp:     time.Now().Format(time.DateTime),
@@ -153,34 +230,81 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		ResponsesDir:  outputDir,
 	}
 
+	var capture *metrics.RequestCapture
+	if cfg.Outputs.RequestStream != nil {
+		requestsPath := cfg.CallsFile
+		if requestsPath == "" {
+			requestsPath = filepath.Join(outputDir, generator.K6RequestsFilename)
+		}
+		capture, err = metrics.ReadRequestStream(cfg.Outputs.RequestStream.Path, requestsPath, benchmarkSlowestN, benchmarkOTLPSpans)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to read request stream")
+			capture = nil
+		} else {
+			benchmarkResults.SlowestRequests = capture.Slowest
+			if err := writeSlowestRequests(filepath.Join(outputDir, slowestRequestsFilename), capture.Slowest); err != nil {
+				logger.WithError(err).Warn("Failed to write slowest requests")
+			}
+			if err := metrics.WriteRequestLatenciesCSV(filepath.Join(outputDir, requestLatenciesFilename), capture); err != nil {
+				logger.WithError(err).Warn("Failed to write request latencies")
+			}
+		}
+	}
+
 	if systemCollector != nil {
 		avgMetrics := systemCollector.GetAverageMetrics()
 		for _, client := range benchmarkResults.ClientMetrics {
 			client.SystemMetrics = []types.SystemMetrics{avgMetrics}
 		}
+		benchmarkResults.SystemMetrics = systemCollector.GetMetrics()
+	}
+
+	// Latency timelines: exact from the request stream when captured,
+	// otherwise reconstructed from Prometheus
+	if capture != nil {
+		metrics.ApplyLatencyTimeline(benchmarkResults.ClientMetrics, capture.Timeline)
+	} else if cfg.Outputs.PrometheusRW != nil {
+		timeline, err := metrics.CollectLatencyTimeline(cfg, startTime, endTime, logger)
+		if err != nil {
+			logger.WithError(err).Warn("Failed to collect latency timeline")
+		} else {
+			metrics.ApplyLatencyTimeline(benchmarkResults.ClientMetrics, timeline)
+		}
 	}
 
 	benchmarkResults.Environment = metrics.GetEnvironmentInfo()
+	benchmarkResults.Environment.K6Version = generator.K6Version()
+
+	if latencyMode == types.LatencyModeCorrected && !benchmarkResults.HasCorrectedLatency() {
+		logger.Warn("Corrected latency was not recorded (it requires an rps-based run); reporting raw latency")
+	}
 
 	performanceAnalyzer := analyzer.NewPerformanceAnalyzer()
+	performanceAnalyzer.SetLatencyMode(latencyMode)
+	if capture != nil {
+		benchmarkResults.PairedComparisons = performanceAnalyzer.AnalyzePaired(capture.Latencies, capture.Methods)
+	}
 	performanceAnalyzer.AnalyzeResults(benchmarkResults)
 
+	runID := localRunID(startTime, benchmarkResults.Environment.GitCommit)
 	if historic != nil {
 		savedRun, err := historic.SaveRun(benchmarkResults, cfg)
 		if err != nil {
 			logger.WithError(err).Error("Failed to save historic run")
 		} else {
+			runID = savedRun.ID
 			logger.WithField("run_id", savedRun.ID).Info("Historic run saved successfully")
 		}
 	}
 
 	if benchmarkHTMLReport {
 		reportPath := filepath.Join(outputDir, "report.html")
-		if err := generator.GenerateUltimateHTMLReport(cfg, benchmarkResults, reportPath); err != nil {
+		reportResults := benchmarkResults.WithLatencyMode(latencyMode)
+		if err := generator.GenerateUltimateHTMLReport(cfg, reportResults, reportPath); err != nil {
 			logger.Warnf("Ultimate report generation failed, falling back to enhanced report: %v", err)
-			if err := generator.GenerateEnhancedHTMLReport(cfg, benchmarkResults, reportPath); err != nil {
+			if err := generator.GenerateEnhancedHTMLReport(cfg, reportResults, reportPath); err != nil {
 				logger.Warnf("Enhanced report generation failed, falling back to basic report: %v", err)
-				if err := generator.GenerateHTMLReport(cfg, benchmarkResults, reportPath); err != nil {
+				if err := generator.GenerateHTMLReport(cfg, reportResults, reportPath); err != nil {
 					return fmt.Errorf("failed to generate HTML report: %w", err)
 				}
 			}
@@ -194,11 +318,83 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 	} else {
 		logger.Info("Exported data to CSV and JSON formats")
 	}
+	if len(columnarFormats) > 0 {
+		run := exporter.ColumnarRun{
+			RunID:     runID,
+			TestName:  cfg.TestName,
+			GitCommit: benchmarkResults.Environment.GitCommit,
+			Timestamp: startTime,
+		}
+		var latencies map[string]map[string]float64
+		var methods map[string]string
+		if capture != nil {
+			latencies, methods = capture.Latencies, capture.Methods
+		}
+		if err := dataExporter.ExportColumnar(run, benchmarkResults, latencies, methods, columnarFormats); err != nil {
+			logger.WithError(err).Warn("Failed to export columnar data")
+		} else {
+			logger.WithField("formats", benchmarkColumnar).Info("Exported columnar data")
+		}
+	}
+
+	if otlpExporter != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := otlpExporter.ExportMetrics(ctx, benchmarkResults); err != nil {
+			logger.WithError(err).Warn("Failed to export OTLP metrics")
+		} else {
+			logger.WithField("endpoint", benchmarkOTLPEndpoint).Info("Exported metrics over OTLP")
+		}
+		if capture != nil && len(capture.Sampled) > 0 {
+			if err := otlpExporter.ExportSpans(ctx, benchmarkResults, capture.Sampled); err != nil {
+				logger.WithError(err).Warn("Failed to export OTLP spans")
+			} else {
+				logger.WithField("spans", len(capture.Sampled)).Info("Exported request spans over OTLP")
+			}
+		}
+	}
+
+	if benchmarkRemoteWriteURL != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		rw := exporter.NewRemoteWriteExporter(benchmarkRemoteWriteURL, benchmarkRemoteWriteUser, benchmarkRemoteWritePass)
+		labels := exporter.RemoteWriteLabels{
+			TestID:    cfg.TestName,
+			RunID:     runID,
+			GitCommit: benchmarkResults.Environment.GitCommit,
+		}
+		if err := rw.Export(ctx, benchmarkResults, labels); err != nil {
+			logger.WithError(err).Warn("Failed to push run summary over remote-write")
+		} else {
+			logger.WithField("run_id", runID).Info("Pushed run summary over remote-write")
+		}
+	}
+
+	// The manifest covers every file this run wrote to the output directory,
+	// which other runs may share, so it comes last
+	manifest := storage.NewBenchmarkManifest(types.ManifestKindBenchmark, cfg, benchmarkResults)
+	manifest.RunID = runID
+	if err := storage.WriteScopedManifest(outputDir, manifest, storage.ModifiedSince(runStart)); err != nil {
+		logger.WithError(err).Warn("Failed to write run manifest")
+	} else {
+		logger.Infof("Wrote run manifest to %s", filepath.Join(outputDir, types.ManifestFilename))
+	}
 
 	logger.Info("Benchmark completed")
 	return nil
 }
 
+// localRunID names a run that wasn't saved to historic storage, in the
+// same YYYYMMDD-HHMMSS-COMMIT form historic storage uses
+func localRunID(start time.Time, commit string) string {
+	if commit == "" {
+		commit = "unknown"
+	} else if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return fmt.Sprintf("%s-%s", start.Format("20060102-150405"), commit)
+}
+
 func logP99Validation(clientsMetrics map[string]*types.ClientMetrics) {
 	totalMethods := 0
 	methodsWithP99 := 0
@@ -228,3 +424,19 @@ func logP99Validation(clientsMetrics map[string]*types.ClientMetrics) {
 		}
 	}
 }
+
+// slowestRequestsFilename holds the captured slowest requests next to the
+// other run outputs; `runner slowest` turns it into a replayable corpus
+const slowestRequestsFilename = "slowest_requests.json"
+
+// requestLatenciesFilename holds every captured request's latency per client,
+// keyed by request id
+const requestLatenciesFilename = "request_latencies.csv"
+
+func writeSlowestRequests(path string, requests []types.SlowRequest) error {
+	data, err := json.MarshalIndent(requests, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal slowest requests: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/jsonrpc-bench/runner/comparator"
+)
+
+var (
+	cacheInvalidateChain  string
+	cacheInvalidateClient string
+	cacheInvalidateMethod string
+	cacheInvalidateBlock  string
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Maintain the compare response cache",
+}
+
+var cacheInvalidateCmd = &cobra.Command{
+	Use:   "invalidate <dir>",
+	Short: "Remove cached responses from a compare --response-cache directory",
+	Long: `Removes the cached responses under dir, narrowed by chain ID, client,
+JSON-RPC method and pinned block. Without filters the whole cache is emptied. Use it after a
+reference node was resynced or upgraded, or its responses were found wrong.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCacheInvalidate,
+}
+
+func init() {
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidateChain, "chain", "", "Only remove responses of this hex chain ID")
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidateClient, "client", "", "Only remove responses of this client")
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidateMethod, "method", "", "Only remove responses of this JSON-RPC method")
+	cacheInvalidateCmd.Flags().StringVar(&cacheInvalidateBlock, "block", "", "Only remove responses pinned to this hex block number")
+	cacheCmd.AddCommand(cacheInvalidateCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheInvalidate(cmd *cobra.Command, args []string) error {
+	configureLogger()
+
+	removed, err := comparator.InvalidateResponseCache(args[0], cacheInvalidateChain, cacheInvalidateClient, cacheInvalidateMethod, cacheInvalidateBlock)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Removed %d cached response(s) from %s", removed, args[0])
+	return nil
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -9,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/jsonrpc-bench/runner/comparator"
+	"github.com/jsonrpc-bench/runner/metrics"
+	"github.com/jsonrpc-bench/runner/storage"
 	"github.com/jsonrpc-bench/runner/types"
 )
 
@@ -34,6 +38,12 @@ var (
 	compareFromJSONL        string
 	compareSample           int
 	compareSampleSeed       int64
+	compareLedgerPath       string
+	compareRecordGolden     string
+	compareAgainstGolden    string
+	compareResume           string
+	compareSlowFactor       float64
+	compareResponseCache    string
 )
 
 var compareCmd = &cobra.Command{
@@ -64,6 +74,12 @@ func init() {
 	compareCmd.Flags().StringVar(&compareFromJSONL, "from-jsonl", "", "Build the config from a corpus directory (recurses; reads *.jsonl and *.json arrays) instead of --config")
 	compareCmd.Flags().IntVar(&compareSample, "sample", 0, "With --from-jsonl, sample at most N calls per method (0 = all)")
 	compareCmd.Flags().Int64Var(&compareSampleSeed, "sample-seed", 42, "Deterministic seed for --sample")
+	compareCmd.Flags().StringVar(&compareLedgerPath, "ledger", "", "Path to a known-differences ledger; accepted findings no longer trip --fail-on-diff")
+	compareCmd.Flags().StringVar(&compareRecordGolden, "record-golden", "", "Store the first client's responses as golden snapshots in this directory")
+	compareCmd.Flags().StringVar(&compareAgainstGolden, "against-golden", "", "Compare the clients to the golden snapshots in this directory instead of to each other")
+	compareCmd.Flags().Float64Var(&compareSlowFactor, "slow-factor", comparator.DefaultSlowFactor, "Flag calls a client answers more than this many times slower than the other clients")
+	compareCmd.Flags().StringVar(&compareResponseCache, "response-cache", "", "Cache the first client's block-pinned responses in this directory across runs (see runner cache invalidate)")
+	compareCmd.Flags().StringVar(&compareResume, "resume", "", "Resume an interrupted run in this output directory, skipping the calls already in its comparison-results.jsonl")
 
 	_ = compareCmd.MarkFlagRequired("clients")
 	_ = compareCmd.MarkFlagRequired("client-refs")
@@ -94,6 +110,9 @@ func runCompare(cmd *cobra.Command, args []string) error {
 	if (compareConfigPath == "") == (compareFromJSONL == "") {
 		return fmt.Errorf("exactly one of --config or --from-jsonl is required")
 	}
+	if compareRecordGolden != "" && compareAgainstGolden != "" {
+		return fmt.Errorf("--record-golden and --against-golden cannot be combined")
+	}
 
 	// Load the optional --rules file first so its block_override can inform
 	// corpus loading (e.g. keeping pinnable methods like eth_feeHistory).
@@ -138,6 +157,18 @@ func runCompare(cmd *cobra.Command, args []string) error {
 	cfg.MaxRetries = compareMaxRetries
 	cfg.RetryBaseDelayMs = int(compareRetryBaseDelay.Milliseconds())
 	cfg.SkipAboveHead = compareSkipAboveHead
+	cfg.AgainstGolden = compareAgainstGolden
+	cfg.SlowFactor = compareSlowFactor
+	cfg.ResponseCache = compareResponseCache
+
+	// Results are streamed as they complete; --resume continues the stream of
+	// an interrupted run in its own output directory
+	if compareResume != "" {
+		outputDir = compareResume
+		cfg.OutputDir = outputDir
+	}
+	cfg.StreamPath = filepath.Join(outputDir, "comparison-results.jsonl")
+	cfg.Resume = compareResume != ""
 
 	// Layer the --rules file on top of any rules from --config, then apply the
 	// block-override precedence (rules file over config, flag over everything).
@@ -151,18 +182,46 @@ func runCompare(cmd *cobra.Command, args []string) error {
 
 	applyDiffOnlyDefaults(cfg, compareKeepBodies)
 
+	var ledger *comparator.Ledger
+	if compareLedgerPath != "" {
+		ledger, err = comparator.LoadLedger(compareLedgerPath)
+		if err != nil {
+			return fmt.Errorf("failed to load ledger: %w", err)
+		}
+	}
+
 	comp, err := comparator.NewComparator(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create comparator: %w", err)
 	}
+	defer comp.Close()
+	if comp.Resumed() > 0 {
+		logger.Infof("Resuming %s: %d call(s) already compared", outputDir, comp.Resumed())
+	}
 
-	results, err := comp.Run()
-	if err != nil {
+	if _, err := comp.Run(); err != nil {
 		return fmt.Errorf("comparison failed: %w", err)
 	}
-	logger.Infof("Completed comparison of %d calls", len(results))
+	logger.Infof("Completed comparison of %d calls", comp.Completed())
+	if stats := comp.CacheStats(); stats != nil {
+		logger.Infof("Response cache for %s: %d hit(s), %d miss(es), %d call(s) not block-pinned", stats.Client, stats.Hits, stats.Misses, stats.Uncacheable)
+	}
 
-	return finishComparison(comp, compareFailOnDiff, compareFailOnEnv)
+	if ledger != nil {
+		if _, err := comp.ApplyLedger(ledger, time.Now()); err != nil {
+			return fmt.Errorf("failed to apply ledger: %w", err)
+		}
+	}
+
+	if compareRecordGolden != "" {
+		written, err := comp.RecordGolden(compareRecordGolden)
+		if err != nil {
+			return fmt.Errorf("failed to record golden snapshots: %w", err)
+		}
+		logger.Infof("Recorded %d golden snapshot(s) in %s", written, compareRecordGolden)
+	}
+
+	return finishComparison(comp, clients, compareFailOnDiff, compareFailOnEnv)
 }
 
 // applyDiffOnlyDefaults makes --diff-only the obvious "small report" switch: if
@@ -175,10 +234,10 @@ func applyDiffOnlyDefaults(cfg *comparator.ComparisonConfig, keepBodies bool) {
 	}
 }
 
-// finishComparison writes the results, provenance sidecar, and HTML report,
-// prints the outcome summary, and returns a non-zero (error) result when
-// failOnDiff is set and post-filter differences remain.
-func finishComparison(comp *comparator.Comparator, failOnDiff, failOnEnv bool) error {
+// finishComparison writes the results, provenance sidecar, HTML report, repro
+// bundles and run manifest, prints the outcome summary, and returns a non-zero (error)
+// result when failOnDiff is set and post-filter differences remain.
+func finishComparison(comp *comparator.Comparator, clients []*types.ClientConfig, failOnDiff, failOnEnv bool) error {
 	jsonPath := filepath.Join(outputDir, "comparison-results.json")
 	if err := comp.SaveResults(jsonPath); err != nil {
 		return fmt.Errorf("failed to save comparison results: %w", err)
@@ -190,13 +249,52 @@ func finishComparison(comp *comparator.Comparator, failOnDiff, failOnEnv bool) e
 		return fmt.Errorf("failed to save comparison provenance: %w", err)
 	}
 
+	findingsPath := filepath.Join(outputDir, "comparison-findings.json")
+	if err := comp.SaveFindings(findingsPath); err != nil {
+		return fmt.Errorf("failed to save comparison findings: %w", err)
+	}
+
+	if report := comp.LedgerReport(); report != nil {
+		ledgerPath := filepath.Join(outputDir, "comparison-ledger.json")
+		if err := comp.SaveLedgerReport(ledgerPath); err != nil {
+			return fmt.Errorf("failed to save ledger report: %w", err)
+		}
+		printLedgerReport(report)
+	}
+
 	htmlPath := filepath.Join(outputDir, "comparison-report.html")
 	if err := comp.GenerateHTMLReport(htmlPath); err != nil {
 		return fmt.Errorf("failed to generate comparison HTML report: %w", err)
 	}
 	logger.Infof("Comparison HTML report generated at %s", htmlPath)
 
+	versions := metrics.FetchClientVersions(clients, 5*time.Second, logger)
+	reproPath := filepath.Join(outputDir, comparator.ReproDir)
+	bundles, err := comp.WriteReproBundles(reproPath, versions)
+	if err != nil {
+		return fmt.Errorf("failed to write repro bundles: %w", err)
+	}
+	if bundles > 0 {
+		logger.Infof("Wrote %d repro bundle(s) to %s (replay one with runner repro <dir>)", bundles, reproPath)
+	}
+
+	manifest := comp.Manifest()
+	manifest.Environment = metrics.GetEnvironmentInfo()
+	manifest.RunnerCommit = manifest.Environment.GitCommit
+	manifest.Hostname, _ = os.Hostname()
+	if len(versions) > 0 {
+		manifest.ClientVersions = versions
+	}
+	// The output directory may hold other runs' files; the manifest covers
+	// only the comparison's own artifacts
+	if err := storage.WriteScopedManifest(outputDir, manifest, isComparisonArtifact); err != nil {
+		logger.WithError(err).Warn("Failed to write run manifest")
+	} else {
+		logger.Infof("Wrote run manifest to %s", filepath.Join(outputDir, types.ManifestFilename))
+	}
+
 	printComparisonSummary(comp.Summarize())
+	printTopFindings(comp.Findings())
 
 	realFail := failOnDiff && comp.HasRealDifferences()
 	envFail := failOnEnv && comp.HasEnvDifferences()
@@ -211,11 +309,46 @@ func finishComparison(comp *comparator.Comparator, failOnDiff, failOnEnv bool) e
 
 // printComparisonSummary logs a one-screen tally of the run's outcomes.
 func printComparisonSummary(s comparator.Summary) {
-	logger.Infof("Summary: %d calls — %d identical, %d differ (real), %d differ (env/expected), %d transport-error, %d schema-error, %d skipped",
-		s.Total, s.Identical, s.Differ, s.DifferEnv, s.TransportError, s.SchemaError, s.Skipped)
+	logger.Infof("Summary: %d calls — %d identical, %d differ (real), %d differ (env/expected), %d differ (known), %d transport-error, %d schema-error, %d skipped",
+		s.Total, s.Identical, s.Differ, s.DifferEnv, s.Known, s.TransportError, s.SchemaError, s.Skipped)
 	for class, n := range s.EnvError {
 		logger.Infof("  env/capability errors [%s]: %d", class, n)
 	}
+	for client, byMethod := range s.MajorityDisagreements {
+		total := 0
+		for _, n := range byMethod {
+			total += n
+		}
+		logger.Infof("  %s disagrees with the majority on %d call(s) across %d method(s)", client, total, len(byMethod))
+	}
+	for client, n := range s.Slow {
+		logger.Infof("  %s was flagged slow on %d call(s)", client, n)
+	}
+}
+
+// printLedgerReport logs how the run's differences relate to the ledger and
+// the entries that need attention.
+func printLedgerReport(r *comparator.LedgerReport) {
+	logger.Infof("Ledger: %d known, %d changed, %d new differing call(s)", r.Known, r.Changed, r.New)
+	for _, e := range r.Expired {
+		logger.Warnf("  expired on %s: %s %s %s on %s (owner %s, %s)", e.Expires, e.Method, e.Type, e.Path, e.Client, e.Owner, e.Link)
+	}
+	for _, e := range r.Stale {
+		logger.Warnf("  no longer reproduces: %s %s %s on %s (owner %s, %s)", e.Method, e.Type, e.Path, e.Client, e.Owner, e.Link)
+	}
+}
+
+// printTopFindings logs the findings that affect the most calls.
+func printTopFindings(findings []comparator.Finding) {
+	const top = 5
+	if len(findings) == 0 {
+		return
+	}
+	logger.Infof("Findings: %d root-cause group(s), top %d:", len(findings), min(top, len(findings)))
+	for _, f := range findings[:min(top, len(findings))] {
+		logger.Infof("  %d call(s) %s %s (%s vs %s) in %s",
+			f.Calls, f.DiffType, f.Signature, f.Reference, f.Client, strings.Join(f.Methods, ", "))
+	}
 }
 
 func splitCSV(s string) []string {
@@ -229,3 +362,20 @@ func splitCSV(s string) []string {
 	}
 	return out
 }
+
+// comparisonArtifacts are the files a comparison writes to its output
+// directory, besides the repro bundles
+var comparisonArtifacts = map[string]bool{
+	"comparison-results.json":    true,
+	"comparison-results.jsonl":   true,
+	comparator.CheckpointFile:    true,
+	"comparison-provenance.json": true,
+	"comparison-findings.json":   true,
+	"comparison-ledger.json":     true,
+	"comparison-report.html":     true,
+}
+
+// isComparisonArtifact selects the comparison's files for its run manifest
+func isComparisonArtifact(rel string, _ fs.FileInfo) bool {
+	return comparisonArtifacts[rel] || strings.HasPrefix(rel, comparator.ReproDir+"/")
+}
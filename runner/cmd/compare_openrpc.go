@@ -127,7 +127,7 @@ func runCompareOpenRPC(cmd *cobra.Command, args []string) error {
 	}
 	logger.Infof("Completed comparison of %d methods", len(results))
 
-	return finishComparison(comp, openrpcFailOnDiff, openrpcFailOnEnv)
+	return finishComparison(comp, clients, openrpcFailOnDiff, openrpcFailOnEnv)
 }
 
 func applyMethodFilter(cfg *comparator.ComparisonConfig, methodsToInclude []string) {
@@ -279,17 +279,17 @@ func extractClientMetricsFromDB(db *sql.DB, runID string) (map[string]*types.Cli
 
 	err := db.QueryRow(query, runID).Scan(&metadataJSON)
 	if err == nil && len(metadataJSON) > 0 {
-		// Try to extract client metrics from metadata
-		var metadata map[string]interface{}
-		if err := json.Unmarshal(metadataJSON, &metadata); err == nil {
-			if fullResults, ok := metadata["full_results"]; ok {
-				// Marshal back to JSON to parse as BenchmarkResult
-				fullResultsBytes, _ := json.Marshal(fullResults)
-				var benchmarkResult types.BenchmarkResult
-				if err := json.Unmarshal(fullResultsBytes, &benchmarkResult); err == nil {
-					return benchmarkResult.ClientMetrics, nil
-				}
+		// Try to extract client metrics from metadata; BenchmarkResult
+		// upgrades full results written by older versions as it decodes
+		var metadata struct {
+			FullResults json.RawMessage `json:"full_results"`
+		}
+		if err := json.Unmarshal(metadataJSON, &metadata); err == nil && len(metadata.FullResults) > 0 {
+			var benchmarkResult types.BenchmarkResult
+			if err := json.Unmarshal(metadata.FullResults, &benchmarkResult); err != nil {
+				return nil, fmt.Errorf("failed to parse full_results: %w", err)
 			}
+			return benchmarkResult.ClientMetrics, nil
 		}
 	}
 
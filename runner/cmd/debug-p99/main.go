@@ -112,8 +112,13 @@ func debugP99ForRun(runID string, cfg *config.StorageConfig, log logrus.FieldLog
 	} else {
 		fmt.Printf("K6 results file: %s\n", k6File)
 
-		// Parse and display relevant K6 metrics
-		if k6Data != nil {
+		// The result path usually points at the runner's own results.json;
+		// anything else is treated as a k6 summary
+		if _, ok := k6Data["client_metrics"]; ok {
+			if err := displayRunnerResultP99(k6File); err != nil {
+				log.WithError(err).Warn("Failed to decode runner results")
+			}
+		} else if k6Data != nil {
 			displayK6Metrics(k6Data)
 		}
 	}
@@ -324,6 +329,32 @@ func findK6ResultsFile(resultPath, historicPath string) (string, map[string]inte
 	return "", nil, fmt.Errorf("K6 results file not found in any expected location")
 }
 
+// displayRunnerResultP99 prints the per-client p99 latencies of a runner
+// results.json. BenchmarkResult upgrades files written by older versions as
+// it decodes.
+func displayRunnerResultP99(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var result types.BenchmarkResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	fmt.Println("Runner Results Summary:")
+	for clientName, metrics := range result.ClientMetrics {
+		if metrics == nil {
+			continue
+		}
+		fmt.Printf("  %s - p99: %.2f ms\n", clientName, metrics.Latency.P99)
+		for methodName, method := range metrics.Methods {
+			fmt.Printf("    %s - p99: %.2f ms\n", methodName, method.P99)
+		}
+	}
+	return nil
+}
+
 func displayK6Metrics(data map[string]interface{}) {
 	fmt.Println("K6 Metrics Summary:")
 
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsonrpc-bench/runner/exporter"
+	"github.com/jsonrpc-bench/runner/metrics"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+var (
+	exportStorageConfigPath string
+	exportTestName          string
+	exportGitBranch         string
+	exportSince             string
+	exportUntil             string
+	exportLimit             int
+	exportFormat            string
+	exportOut               string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export historic runs as one Parquet or Arrow dataset",
+	Long: `Reads the historic runs matching the filters and writes them as one dataset
+of four tables:
+
+  runs          one row per run with its metadata
+  metrics       per client and method; method "all" is the client aggregate
+  time_series   latency timelines, client resources and generator samples
+  requests      per-request latencies of runs that captured requests`,
+	RunE: runExport,
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportStorageConfigPath, "storage-config", "", "Path to storage configuration file")
+	exportCmd.Flags().StringVar(&exportTestName, "test", "", "Only export runs of this test")
+	exportCmd.Flags().StringVar(&exportGitBranch, "branch", "", "Only export runs from this git branch")
+	exportCmd.Flags().StringVar(&exportSince, "since", "", "Only export runs at or after this time (RFC 3339 or YYYY-MM-DD)")
+	exportCmd.Flags().StringVar(&exportUntil, "until", "", "Only export runs at or before this time (RFC 3339, or YYYY-MM-DD for the end of that day)")
+	exportCmd.Flags().IntVar(&exportLimit, "limit", 0, "Export at most the N most recent matching runs (0 = all)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", exporter.ColumnarParquet, "Dataset format: parquet, arrow or parquet,arrow")
+	exportCmd.Flags().StringVar(&exportOut, "out", "", "Destination directory (defaults to <output>/dataset)")
+	_ = exportCmd.MarkFlagRequired("storage-config")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	configureLogger()
+
+	formats, err := exporter.ParseColumnarFormats(exportFormat)
+	if err != nil {
+		return fmt.Errorf("--format: %w", err)
+	}
+	if len(formats) == 0 {
+		return fmt.Errorf("--format: no format given")
+	}
+	filter := types.RunFilter{TestName: exportTestName, GitBranch: exportGitBranch, Limit: exportLimit}
+	if filter.Since, err = parseExportTime(exportSince, false); err != nil {
+		return fmt.Errorf("--since: %w", err)
+	}
+	if filter.Until, err = parseExportTime(exportUntil, true); err != nil {
+		return fmt.Errorf("--until: %w", err)
+	}
+	out := exportOut
+	if out == "" {
+		out = filepath.Join(outputDir, "dataset")
+	}
+
+	historic, db, err := openHistoricStorage(exportStorageConfigPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	runs, err := historic.ListHistoricRuns(context.Background(), filter)
+	if err != nil {
+		return fmt.Errorf("failed to list historic runs: %w", err)
+	}
+	if len(runs) == 0 {
+		logger.Info("No historic runs match the filters; nothing to export")
+		return nil
+	}
+
+	dataset := exporter.NewColumnarDataset()
+	defer dataset.Release()
+	// Runs are listed newest first; the dataset is in chronological order
+	exported := 0
+	for i := len(runs) - 1; i >= 0; i-- {
+		run := runs[i]
+		result, err := historic.LoadRun(run.ID)
+		if err != nil {
+			logger.WithError(err).WithField("run_id", run.ID).Warn("Skipping run that could not be loaded")
+			continue
+		}
+
+		var latencies map[string]map[string]float64
+		var methods map[string]string
+		if full, err := historic.GetHistoricRun(context.Background(), run.ID); err == nil && full.ResultPath != "" {
+			path := filepath.Join(full.ResultPath, requestLatenciesFilename)
+			if _, err := os.Stat(path); err == nil {
+				capture, err := metrics.ReadRequestLatenciesCSV(path)
+				if err != nil {
+					logger.WithError(err).WithField("run_id", run.ID).Warn("Failed to read request latencies")
+				} else {
+					latencies, methods = capture.Latencies, capture.Methods
+				}
+			}
+		}
+
+		dataset.AddRun(exporter.ColumnarRun{
+			RunID:     run.ID,
+			TestName:  run.TestName,
+			GitCommit: run.GitCommit,
+			GitBranch: run.GitBranch,
+			Timestamp: run.Timestamp,
+		}, result, latencies, methods)
+		exported++
+	}
+
+	if err := dataset.Write(out, formats...); err != nil {
+		return err
+	}
+	logger.WithField("runs", exported).Infof("Exported dataset to %s", out)
+	return nil
+}
+
+// parseExportTime accepts RFC 3339 or a plain date; empty means no bound. A
+// plain date is its first instant, or its last with endOfDay, so an inclusive
+// upper bound covers the whole day.
+func parseExportTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil || !endOfDay {
+		return t, err
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+}
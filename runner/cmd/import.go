@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/jsonrpc-bench/runner/config"
+	"github.com/jsonrpc-bench/runner/metrics"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+var (
+	importStorageConfigPath string
+	importFrom              string
+	importFormat            string
+	importTestName          string
+	importDescription       string
+	importClients           []string
+	importTags              []string
+	importMethod            string
+	importAt                string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import an externally produced k6 or flood run into historic storage",
+	Long: `Parses results of a load test run outside the runner and saves them as a
+historic run, so they show up in trends, baselines and regression checks.
+
+Formats:
+
+  k6-summary   k6 --summary-export or handleSummary JSON
+  k6-stream    k6 --out json output (gzipped when the name ends in .gz)
+  flood        flood results.json
+
+Sources are k6 scenarios or flood node names. --clients maps them to client
+names as source=client (or just the name to keep it); unmapped sources are
+dropped. Without --clients every source is imported under its own name.`,
+	RunE: runImport,
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importStorageConfigPath, "storage-config", "", "Path to storage configuration file")
+	importCmd.Flags().StringVar(&importFrom, "from", "", "Path to the file to import")
+	importCmd.Flags().StringVar(&importFormat, "format", metrics.ImportFormatK6Summary, "Input format: "+strings.Join(metrics.ImportFormats(), ", "))
+	importCmd.Flags().StringVar(&importTestName, "test", "", "Test name the run is stored under")
+	importCmd.Flags().StringVar(&importDescription, "description", "", "Description of the run")
+	importCmd.Flags().StringSliceVar(&importClients, "clients", nil, "Clients to import, as source=client or client (default: every source under its own name)")
+	importCmd.Flags().StringSliceVar(&importTags, "tags", nil, "Tags stored with the run")
+	importCmd.Flags().StringVar(&importMethod, "method", metrics.ImportedMethod, "Method name for requests the source does not tag with one")
+	importCmd.Flags().StringVar(&importAt, "at", "", "Time the run happened (RFC 3339 or YYYY-MM-DD; defaults to the time found in the input, else now)")
+	_ = importCmd.MarkFlagRequired("storage-config")
+	_ = importCmd.MarkFlagRequired("from")
+	_ = importCmd.MarkFlagRequired("test")
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	configureLogger()
+
+	clients, err := parseImportClients(importClients)
+	if err != nil {
+		return fmt.Errorf("--clients: %w", err)
+	}
+	at, err := parseExportTime(importAt, false)
+	if err != nil {
+		return fmt.Errorf("--at: %w", err)
+	}
+
+	result, err := metrics.ImportResult(importFormat, importFrom, metrics.ImportOptions{Clients: clients, Method: importMethod})
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", importFrom, err)
+	}
+	if at.IsZero() {
+		at = importedRunTime(result)
+	}
+
+	cfg := &config.Config{
+		TestName:    importTestName,
+		Description: importDescription,
+		Tags:        importTags,
+	}
+	for name := range result.ClientMetrics {
+		cfg.ClientRefs = append(cfg.ClientRefs, name)
+		cfg.ResolvedClients = append(cfg.ResolvedClients, &types.ClientConfig{Name: name})
+	}
+
+	historic, db, err := openHistoricStorage(importStorageConfigPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	run, err := historic.SaveImportedRun(result, cfg, at)
+	if err != nil {
+		return fmt.Errorf("failed to save imported run: %w", err)
+	}
+	logger.WithFields(logrus.Fields{
+		"run_id":  run.ID,
+		"clients": len(result.ClientMetrics),
+	}).Infof("Imported %s as test %s", importFrom, importTestName)
+	return nil
+}
+
+// parseImportClients turns source=client (or client) entries into the source
+// to client map of metrics.ImportOptions
+func parseImportClients(entries []string) (map[string]string, error) {
+	clients := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		source, name, ok := strings.Cut(entry, "=")
+		if !ok {
+			name = source
+		}
+		source, name = strings.TrimSpace(source), strings.TrimSpace(name)
+		if source == "" || name == "" {
+			return nil, fmt.Errorf("invalid entry %q, want source=client or client", entry)
+		}
+		clients[source] = name
+	}
+	return clients, nil
+}
+
+// importedRunTime is when the imported run started, falling back to its end
+// and then to now
+func importedRunTime(result *types.BenchmarkResult) time.Time {
+	for _, value := range []string{result.StartTime, result.EndTime} {
+		if t, err := time.ParseInLocation(time.DateTime, value, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
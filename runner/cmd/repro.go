@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsonrpc-bench/runner/comparator"
+)
+
+var (
+	reproClientsPath string
+	reproTimeout     int
+	reproFailOnDiff  bool
+)
+
+var reproCmd = &cobra.Command{
+	Use:   "repro <dir>",
+	Short: "Replay a compare repro bundle and report whether it still diverges",
+	Long: `Sends the exact request recorded in a repro bundle (written by runner compare
+under <output>/repro/) to the same clients again, compares the responses with
+the rules the bundle recorded, and reports whether the clients still diverge.
+Bundles store client URLs without credentials; --clients supplies the full
+URLs from clients.yaml.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRepro,
+}
+
+func init() {
+	reproCmd.Flags().StringVar(&reproClientsPath, "clients", "", "Path to clients.yaml; its URLs replace the redacted ones recorded in the bundle")
+	reproCmd.Flags().IntVar(&reproTimeout, "timeout", 30, "Per-request timeout in seconds")
+	reproCmd.Flags().BoolVar(&reproFailOnDiff, "fail-on-diff", false, "Exit non-zero when the call still diverges")
+	rootCmd.AddCommand(reproCmd)
+}
+
+func runRepro(cmd *cobra.Command, args []string) error {
+	configureLogger()
+
+	registry, err := loadClientRegistry(reproClientsPath)
+	if err != nil {
+		return err
+	}
+	urls := make(map[string]string)
+	for name, client := range registry.GetAll() {
+		urls[name] = client.URL
+	}
+
+	outcome, err := comparator.ReplayRepro(args[0], reproTimeout, urls)
+	if err != nil {
+		return err
+	}
+	bundle := outcome.Bundle
+	logger.Infof("Replayed %s (%s) against %d client(s), reference %s", bundle.Method, bundle.Request.Method, len(bundle.Clients), bundle.Reference)
+
+	for client, msg := range outcome.TransportErrors {
+		logger.Warnf("  %s did not answer: %s", client, msg)
+	}
+	if !outcome.Diverges() {
+		logger.Infof("No longer diverges: every client that answered agrees with %s", bundle.Reference)
+		return nil
+	}
+
+	clients := make([]string, 0, len(outcome.Differences))
+	for client := range outcome.Differences {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+	for _, client := range clients {
+		_, recorded := bundle.Differences[client]
+		status := "still diverges"
+		if !recorded {
+			status = "newly diverges"
+		}
+		logger.Infof("  %s %s from %s", client, status, bundle.Reference)
+	}
+	if reproFailOnDiff {
+		return fmt.Errorf("%s still diverges (--fail-on-diff)", bundle.Method)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsonrpc-bench/runner/config"
+	"github.com/jsonrpc-bench/runner/storage"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// resultsFilename is the name benchmark results are stored under, both in
+// historic run directories and in exports
+const resultsFilename = "results.json"
+
+var (
+	resultsStorageConfigPath string
+	resultsDryRun            bool
+)
+
+var resultsCmd = &cobra.Command{
+	Use:   "results",
+	Short: "Maintain stored benchmark result files",
+}
+
+var resultsUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [path...]",
+	Short: "Rewrite stored results.json files in the current schema version",
+	Long: `Finds every results.json under the given files and directories, or under
+the historic_path of --storage-config, and rewrites the ones written with an
+older schema version in place. Results are upgraded on load either way; this
+makes the stored files match what is loaded.`,
+	RunE: runResultsUpgrade,
+}
+
+func init() {
+	resultsUpgradeCmd.Flags().StringVar(&resultsStorageConfigPath, "storage-config", "", "Path to storage configuration file whose historic_path is upgraded")
+	resultsUpgradeCmd.Flags().BoolVar(&resultsDryRun, "dry-run", false, "Report the files that would be upgraded without rewriting them")
+	resultsCmd.AddCommand(resultsUpgradeCmd)
+	rootCmd.AddCommand(resultsCmd)
+}
+
+func runResultsUpgrade(cmd *cobra.Command, args []string) error {
+	configureLogger()
+
+	roots := args
+	if resultsStorageConfigPath != "" {
+		storageCfg, err := config.LoadStorageConfig(resultsStorageConfigPath, logger)
+		if err != nil {
+			return fmt.Errorf("failed to load storage configuration: %w", err)
+		}
+		roots = append(roots, storageCfg.HistoricPath)
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("give the paths to upgrade or --storage-config")
+	}
+
+	verb := "Upgraded"
+	if resultsDryRun {
+		verb = "Would upgrade"
+	}
+	var checked, upgraded, failed int
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || (path != root && d.Name() != resultsFilename) {
+				return nil
+			}
+			checked++
+			from, err := upgradeResultFile(path, resultsDryRun)
+			if err != nil {
+				failed++
+				logger.WithError(err).WithField("path", path).Warn("Failed to upgrade results")
+				return nil
+			}
+			if from < types.ResultSchemaVersion {
+				upgraded++
+				logger.WithField("path", path).Infof("%s results from schema version %d", verb, from)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to walk %s: %w", root, err)
+		}
+	}
+
+	logger.Infof("%s %d of %d result file(s) to schema version %d", verb, upgraded, checked, types.ResultSchemaVersion)
+	if failed > 0 {
+		return fmt.Errorf("%d result file(s) could not be upgraded", failed)
+	}
+	return nil
+}
+
+// upgradeResultFile rewrites one results file in the current schema version
+// and returns the version it had. Files that are not benchmark results, such
+// as other tools' results.json, are left alone.
+func upgradeResultFile(path string, dryRun bool) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return 0, fmt.Errorf("not a JSON object: %w", err)
+	}
+	if _, ok := fields["client_metrics"]; !ok {
+		return types.ResultSchemaVersion, nil
+	}
+
+	upgraded, from, err := types.UpgradeResultJSON(data)
+	if err != nil || from == types.ResultSchemaVersion || dryRun {
+		return from, err
+	}
+
+	// Keep indented files indented
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{\n")) {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, upgraded, "", "  "); err != nil {
+			return from, err
+		}
+		upgraded = indented.Bytes()
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return from, err
+	}
+	// A run directory's manifest is updated for the rewritten file
+	return from, storage.RewriteArtifact(filepath.Dir(path), filepath.Base(path), func() error {
+		tmp := path + ".upgrade"
+		if err := os.WriteFile(tmp, upgraded, info.Mode().Perm()); err != nil {
+			return err
+		}
+		return os.Rename(tmp, path)
+	})
+}
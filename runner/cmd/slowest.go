@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/jsonrpc-bench/runner/comparator"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+var (
+	slowestFrom        string
+	slowestOut         string
+	slowestClient      string
+	slowestMethod      string
+	slowestTop         int
+	slowestClientsPath string
+)
+
+var slowestCmd = &cobra.Command{
+	Use:   "slowest",
+	Short: "Turn captured slowest requests into a replayable corpus and curl commands",
+	Long: `Reads the slowest requests captured by "benchmark --capture-requests" and writes:
+
+  corpus.jsonl   {method, params} lines for "compare --from-jsonl"
+  requests.csv   k6 requests file usable as calls_file in a benchmark config
+  curl.sh        one curl command per request`,
+	RunE: runSlowest,
+}
+
+func init() {
+	slowestCmd.Flags().StringVar(&slowestFrom, "from", "", "Run directory, slowest_requests.json or results JSON holding captured requests")
+	slowestCmd.Flags().StringVar(&slowestOut, "out", "", "Destination directory (defaults to <output>/slowest)")
+	slowestCmd.Flags().StringVar(&slowestClient, "client", "", "Only export requests of this client")
+	slowestCmd.Flags().StringVar(&slowestMethod, "method", "", "Only export requests of this method (call name)")
+	slowestCmd.Flags().IntVar(&slowestTop, "top", 0, "Keep at most N requests per client and method (0 = all captured)")
+	slowestCmd.Flags().StringVar(&slowestClientsPath, "clients", "", "Path to clients configuration file used to fill curl URLs (optional; defaults to $RPC_URL)")
+	rootCmd.AddCommand(slowestCmd)
+}
+
+func runSlowest(cmd *cobra.Command, args []string) error {
+	configureLogger()
+
+	if slowestFrom == "" {
+		return fmt.Errorf("--from is required")
+	}
+
+	requests, err := loadSlowestRequests(slowestFrom)
+	if err != nil {
+		return err
+	}
+	requests = filterSlowestRequests(requests, slowestClient, slowestMethod, slowestTop)
+	if len(requests) == 0 {
+		return fmt.Errorf("no captured requests match the given filters")
+	}
+
+	registry, err := loadClientRegistry(slowestClientsPath)
+	if err != nil {
+		return err
+	}
+
+	out := slowestOut
+	if out == "" {
+		out = filepath.Join(outputDir, "slowest")
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := writeSlowestCorpus(filepath.Join(out, "corpus.jsonl"), requests); err != nil {
+		return err
+	}
+	if err := writeSlowestRequestsCSV(filepath.Join(out, "requests.csv"), requests); err != nil {
+		return err
+	}
+	// Credentials in client URLs are not written into the script
+	urlFor := func(client string) (string, bool) {
+		if c, ok := registry.Get(client); ok && c.URL != "" {
+			if redacted, ok := comparator.RedactURL(c.URL); redacted != "" {
+				return redacted, ok
+			}
+		}
+		return "$RPC_URL", false
+	}
+	if err := writeSlowestCurl(filepath.Join(out, "curl.sh"), requests, urlFor); err != nil {
+		return err
+	}
+
+	logger.WithField("path", out).WithField("requests", len(requests)).Info("Exported slowest requests")
+	fmt.Println(out)
+	return nil
+}
+
+// loadSlowestRequests accepts a run directory, a slowest_requests.json file or
+// a benchmark results JSON carrying slowest_requests
+func loadSlowestRequests(path string) ([]types.SlowRequest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if info.IsDir() {
+		path = filepath.Join(path, slowestRequestsFilename)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read captured requests: %w", err)
+	}
+
+	var requests []types.SlowRequest
+	if err := json.Unmarshal(data, &requests); err == nil {
+		return requests, nil
+	}
+	var result types.BenchmarkResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse captured requests from %s: %w", path, err)
+	}
+	return result.SlowestRequests, nil
+}
+
+// filterSlowestRequests keeps requests matching client and method (empty
+// matches all) and at most top per client and method. Input order is kept,
+// which is slowest-first within each client and method.
+func filterSlowestRequests(requests []types.SlowRequest, client, method string, top int) []types.SlowRequest {
+	kept := make([]types.SlowRequest, 0, len(requests))
+	perGroup := make(map[[2]string]int)
+	for _, req := range requests {
+		if client != "" && req.Client != client {
+			continue
+		}
+		if method != "" && req.Method != method {
+			continue
+		}
+		key := [2]string{req.Client, req.Method}
+		if top > 0 && perGroup[key] >= top {
+			continue
+		}
+		perGroup[key]++
+		kept = append(kept, req)
+	}
+	return kept
+}
+
+// writeSlowestCorpus writes each distinct payload once: the same request is
+// captured once per client. Methods compare --from-jsonl drops are left out
+// and named in a warning; requests.csv still holds them for a benchmark.
+func writeSlowestCorpus(path string, requests []types.SlowRequest) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create corpus file: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	seen := make(map[string]bool)
+	dropped := make(map[string]bool)
+	needOverride := make(map[string]bool)
+	for _, req := range requests {
+		var payload struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.Unmarshal(req.Payload, &payload); err != nil || payload.Method == "" {
+			logger.WithField("request_id", req.RequestID).Warn("Skipping captured request without a usable payload")
+			continue
+		}
+		if len(payload.Params) == 0 {
+			payload.Params = json.RawMessage("[]")
+		}
+		always, withoutOverride := comparator.CorpusDrops(payload.Method)
+		if always {
+			dropped[payload.Method] = true
+			continue
+		}
+		if withoutOverride {
+			needOverride[payload.Method] = true
+		}
+		entry, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode corpus entry: %w", err)
+		}
+		if seen[string(entry)] {
+			continue
+		}
+		seen[string(entry)] = true
+		if err := enc.Encode(payload); err != nil {
+			return fmt.Errorf("failed to write corpus entry: %w", err)
+		}
+	}
+	if len(dropped) > 0 {
+		logger.Warnf("compare --from-jsonl does not replay %s; left out of corpus.jsonl, replay them with requests.csv as a benchmark calls_file", sortedKeys(dropped))
+	}
+	if len(needOverride) > 0 {
+		logger.Warnf("compare --from-jsonl only replays %s with --block-override", sortedKeys(needOverride))
+	}
+	return w.Flush()
+}
+
+// sortedKeys joins the keys of set in order
+func sortedKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ", ")
+}
+
+func writeSlowestRequestsCSV(path string, requests []types.SlowRequest) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create requests file: %w", err)
+	}
+	defer f.Close()
+
+	// Requests captured from several clients may repeat a request id, so ids
+	// are renumbered; the k6 script only uses them as opaque labels
+	writer := csv.NewWriter(f)
+	for i, req := range requests {
+		if len(req.Payload) == 0 {
+			continue
+		}
+		rpcMethod := req.RPCMethod
+		if rpcMethod == "" {
+			rpcMethod = req.Method
+		}
+		if err := writer.Write([]string{strconv.Itoa(i + 1), req.Method, rpcMethod, string(req.Payload)}); err != nil {
+			return fmt.Errorf("failed to write requests file: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeSlowestCurl(path string, requests []types.SlowRequest, urlFor func(client string) (url string, redacted bool)) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create curl script: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintln(w, "#!/bin/sh")
+	for _, req := range requests {
+		if len(req.Payload) == 0 {
+			continue
+		}
+		url, redacted := urlFor(req.Client)
+		fmt.Fprintf(w, "\n# %s %s id=%s %.2fms status=%d\n", req.Client, req.Method, req.RequestID, req.LatencyMs, req.Status)
+		if redacted {
+			fmt.Fprintln(w, "# Credentials were removed from this URL; use the one in clients.yaml")
+		}
+		fmt.Fprintln(w, comparator.FormatCurlCommand(url, req.Payload))
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write curl script: %w", err)
+	}
+	return os.Chmod(path, 0o755)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/jsonrpc-bench/runner/storage"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <dir>",
+	Short: "Check a run directory against its manifest.json",
+	Long: `Re-hashes every file of a benchmark, compare or historic run directory and
+compares it with the SHA-256 hashes its manifest.json recorded. Missing,
+modified and added files fail the check.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	configureLogger()
+
+	dir := args[0]
+	manifest, verification, err := storage.VerifyManifest(dir)
+	if err != nil {
+		return err
+	}
+
+	logger.WithFields(logrus.Fields{
+		"kind":            manifest.Kind,
+		"run_id":          manifest.RunID,
+		"created_at":      manifest.CreatedAt,
+		"config_hash":     manifest.ConfigHash,
+		"runner_commit":   manifest.RunnerCommit,
+		"engine_version":  manifest.EngineVersion,
+		"client_versions": manifest.ClientVersions,
+	}).Info("Read run manifest")
+
+	for _, path := range verification.Missing {
+		logger.WithField("path", path).Error("Artifact is missing")
+	}
+	for _, path := range verification.Modified {
+		logger.WithField("path", path).Error("Artifact was modified")
+	}
+	for _, path := range verification.Unlisted {
+		logger.WithField("path", path).Error("File is not in the manifest")
+	}
+	if !verification.OK() {
+		return fmt.Errorf("%s does not match its manifest: %d missing, %d modified, %d unlisted",
+			dir, len(verification.Missing), len(verification.Modified), len(verification.Unlisted))
+	}
+	logger.Infof("All %d artifacts of %s match the manifest", verification.Checked, dir)
+	return nil
+}
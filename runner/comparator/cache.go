@@ -0,0 +1,274 @@
+package comparator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// CachedResponse is a reference client's response stored for a block-pinned
+// call
+type CachedResponse struct {
+	ChainID  string                 `json:"chain_id"`
+	Client   string                 `json:"client"`
+	Method   string                 `json:"method"`
+	Params   []interface{}          `json:"params"`
+	Block    string                 `json:"block"`
+	CachedAt time.Time              `json:"cached_at"`
+	Response map[string]interface{} `json:"response"`
+}
+
+// CacheStats counts how the response cache served the run. Uncacheable calls
+// were not fully pinned to a block and always went to the client.
+type CacheStats struct {
+	Dir         string `json:"dir"`
+	ChainID     string `json:"chain_id"`
+	Client      string `json:"client"`
+	Hits        int64  `json:"hits"`
+	Misses      int64  `json:"misses"`
+	Uncacheable int64  `json:"uncacheable"`
+}
+
+// responseCache answers the reference client's block-pinned calls from disk.
+// A response at a fixed block never changes, so repeated runs against the
+// same pinned block skip the reference node. Entries are keyed by the chain
+// the client reported, so a client name reused on another network never
+// serves that network's answers.
+type responseCache struct {
+	dir     string
+	chainID string
+	client  string
+
+	hits, misses, uncacheable atomic.Int64
+}
+
+// cacheBlock returns the block a call is fully pinned to, after any block
+// override was applied. eth_getLogs needs both ends of its range pinned to
+// the same block; a range is not cached.
+func cacheBlock(rpcMethod string, params []interface{}) (uint64, bool) {
+	if rpcMethod == "eth_getLogs" {
+		if len(params) == 0 {
+			return 0, false
+		}
+		filter, ok := params[0].(map[string]interface{})
+		if !ok {
+			return 0, false
+		}
+		from, okFrom := hexBlock(filter["fromBlock"])
+		to, okTo := hexBlock(filter["toBlock"])
+		return to, okFrom && okTo && from == to
+	}
+	return pinnedBlock(rpcMethod, params)
+}
+
+// cacheBlockDir is the directory name of a block in the cache
+func cacheBlockDir(block uint64) string {
+	return fmt.Sprintf("0x%x", block)
+}
+
+// cacheChainDir is the directory name of a chain ID in the cache
+func cacheChainDir(chainID uint64) string {
+	return fmt.Sprintf("0x%x", chainID)
+}
+
+// cachePath is where the response of a call lives: keyed by chain, client,
+// method, block and the SHA-256 of the canonical (key-ordered JSON) params
+func (rc *responseCache) cachePath(rpcMethod string, params []interface{}, block uint64) (string, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	data, err := json.Marshal([]interface{}{rc.chainID, rc.client, rpcMethod, params, cacheBlockDir(block)})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return filepath.Join(rc.dir, rc.chainID, rc.client, rpcMethod, cacheBlockDir(block), hex.EncodeToString(sum[:])+".json"), nil
+}
+
+// get returns the cached response of a call. ok is false for a miss and for
+// a call that is not fully block-pinned; an unreadable entry is a miss.
+func (rc *responseCache) get(rpcMethod string, params []interface{}) (map[string]interface{}, bool) {
+	block, pinned := cacheBlock(rpcMethod, params)
+	if !pinned {
+		rc.uncacheable.Add(1)
+		return nil, false
+	}
+	path, err := rc.cachePath(rpcMethod, params, block)
+	if err != nil {
+		rc.misses.Add(1)
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		rc.misses.Add(1)
+		return nil, false
+	}
+	var cached CachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Response == nil {
+		rc.misses.Add(1)
+		return nil, false
+	}
+	rc.hits.Add(1)
+	return cached.Response, true
+}
+
+// put stores the response of a block-pinned call. Error responses are not
+// stored: they may be transient (rate limits, timeouts surfaced as errors).
+func (rc *responseCache) put(rpcMethod string, params []interface{}, response map[string]interface{}) error {
+	block, pinned := cacheBlock(rpcMethod, params)
+	if !pinned {
+		return nil
+	}
+	if _, failed := response["error"]; failed {
+		return nil
+	}
+	path, err := rc.cachePath(rpcMethod, params, block)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	data, err := json.MarshalIndent(CachedResponse{
+		ChainID:  rc.chainID,
+		Client:   rc.client,
+		Method:   rpcMethod,
+		Params:   params,
+		Block:    cacheBlockDir(block),
+		CachedAt: time.Now(),
+		Response: canonicalResponse(response),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached response: %w", err)
+	}
+	// Write a temporary file of our own then rename, so a concurrent or
+	// interrupted run never reads a partial entry, and workers storing the
+	// same call never write into each other's file
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	// CreateTemp makes the file 0600; entries are shared like the rest of
+	// the cache
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cached response: %w", err)
+	}
+	return nil
+}
+
+// newResponseCache opens the cache of client under dir, keyed by the chain ID
+// the client reports
+func (c *Comparator) newResponseCache(dir string, client *types.ClientConfig) (*responseCache, error) {
+	chainID, err := c.fetchChainID(client)
+	if err != nil {
+		return nil, fmt.Errorf("response cache needs the chain ID of %s: %w", client.Name, err)
+	}
+	id, ok := hexBlock(chainID)
+	if !ok {
+		return nil, fmt.Errorf("invalid chainId from %s: %q is not a hex number", client.Name, chainID)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create response cache: %w", err)
+	}
+	return &responseCache{dir: dir, chainID: cacheChainDir(id), client: client.Name}, nil
+}
+
+// stats returns the cache counters of the run
+func (rc *responseCache) stats() CacheStats {
+	return CacheStats{
+		Dir:         rc.dir,
+		ChainID:     rc.chainID,
+		Client:      rc.client,
+		Hits:        rc.hits.Load(),
+		Misses:      rc.misses.Load(),
+		Uncacheable: rc.uncacheable.Load(),
+	}
+}
+
+// CacheStats returns the response cache counters, or nil without a cache
+func (c *Comparator) CacheStats() *CacheStats {
+	if c.cache == nil {
+		return nil
+	}
+	stats := c.cache.stats()
+	return &stats
+}
+
+// InvalidateResponseCache removes the cached responses under dir for chain
+// ID, client, JSON-RPC method and block; an empty filter matches everything.
+// It returns the number of entries removed.
+func InvalidateResponseCache(dir, chainID, client, method, block string) (int, error) {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return 0, fmt.Errorf("response cache %s is not a directory", dir)
+	}
+	if chainID != "" {
+		id, ok := hexBlock(chainID)
+		if !ok {
+			return 0, fmt.Errorf("chain ID %q is not a hex number", chainID)
+		}
+		chainID = cacheChainDir(id)
+	}
+	if block != "" {
+		b, ok := hexBlock(block)
+		if !ok {
+			return 0, fmt.Errorf("block %q is not a hex block number", block)
+		}
+		block = cacheBlockDir(b)
+	}
+
+	removed := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		// <chain>/<client>/<method>/<block>/<key>.json
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 5 {
+			return nil
+		}
+		if (chainID != "" && parts[0] != chainID) || (client != "" && parts[1] != client) ||
+			(method != "" && parts[2] != method) || (block != "" && parts[3] != block) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("failed to invalidate response cache: %w", err)
+	}
+	return removed, nil
+}
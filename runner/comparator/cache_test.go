@@ -0,0 +1,149 @@
+package comparator
+
+import (
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// The reference client's block-pinned calls are answered from the cache on
+// the next run on the same chain; unpinned calls always reach the client.
+func TestResponseCacheServesPinnedCalls(t *testing.T) {
+	var refCalls atomic.Int32
+	countCalls := func(rpcRequest) interface{} {
+		refCalls.Add(1)
+		return "0x10"
+	}
+	ref := newRPCFake(t, "0x1", countCalls)
+	other := newRPCFake(t, "0x1", func(rpcRequest) interface{} { return "0x10" })
+	cacheDir := t.TempDir()
+
+	var balanceTimings map[string]ClientTiming
+	runAgainst := func(refURL string) *Comparator {
+		c, err := NewComparator(&ComparisonConfig{
+			Clients:       []*types.ClientConfig{{Name: "archive", URL: refURL}, {Name: "reth", URL: other.URL}},
+			OutputDir:     t.TempDir(),
+			BlockOverride: "0x100",
+			ResponseCache: cacheDir,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// eth_getBalance is pinned by the block override; eth_blockNumber is not
+		for _, method := range []string{"eth_getBalance", "eth_blockNumber"} {
+			result, err := c.CompareResponses(method, []interface{}{"0xabc"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Differences) != 0 {
+				t.Errorf("%s differences = %v", method, result.Differences)
+			}
+			if method == "eth_getBalance" {
+				balanceTimings = result.Timings
+			}
+		}
+		return c
+	}
+	run := func() *Comparator { return runAgainst(ref.URL) }
+
+	run()
+	if n := refCalls.Load(); n != 2 {
+		t.Fatalf("first run made %d reference calls, want 2", n)
+	}
+
+	refCalls.Store(0)
+	second := run()
+	if n := refCalls.Load(); n != 1 {
+		t.Errorf("second run made %d reference calls, want 1 (eth_blockNumber only)", n)
+	}
+	stats := second.CacheStats()
+	if stats.ChainID != "0x1" || stats.Client != "archive" || stats.Hits != 1 || stats.Misses != 0 || stats.Uncacheable != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	// The cached answer is kept in the timings, marked instead of timed
+	if timing, ok := balanceTimings["archive"]; !ok || !timing.Cached || timing.LatencyMs != 0 {
+		t.Errorf("archive timing = %+v, %v; want a cached marker", timing, ok)
+	}
+	if timing := balanceTimings["reth"]; timing.Cached {
+		t.Errorf("reth timing = %+v, want a measured answer", timing)
+	}
+
+	// The same client name on another chain does not share the entries
+	refCalls.Store(0)
+	runAgainst(newRPCFake(t, "0x5", countCalls).URL)
+	if n := refCalls.Load(); n != 2 {
+		t.Errorf("a run on another chain made %d reference calls, want 2", n)
+	}
+
+	removed, err := InvalidateResponseCache(cacheDir, "0x1", "", "eth_getBalance", "0x100")
+	if err != nil || removed != 1 {
+		t.Fatalf("InvalidateResponseCache = %d, %v", removed, err)
+	}
+	refCalls.Store(0)
+	run()
+	if n := refCalls.Load(); n != 2 {
+		t.Errorf("after invalidation the reference was called %d times, want 2", n)
+	}
+}
+
+// eth_getLogs is only cacheable when its whole range is one pinned block.
+func TestCacheBlockGetLogs(t *testing.T) {
+	if _, ok := cacheBlock("eth_getLogs", []interface{}{map[string]interface{}{"fromBlock": "0x1", "toBlock": "0x5"}}); ok {
+		t.Error("a block range should not be cacheable")
+	}
+	if b, ok := cacheBlock("eth_getLogs", []interface{}{map[string]interface{}{"fromBlock": "0x5", "toBlock": "0x5"}}); !ok || b != 5 {
+		t.Errorf("cacheBlock = %d, %v; want 5, true", b, ok)
+	}
+}
+
+// Cached answers are never flagged and never serve as the baseline.
+func TestSlowClientsSkipsCached(t *testing.T) {
+	timings := map[string]ClientTiming{
+		"archive": {Cached: true},
+		"geth":    {LatencyMs: 60},
+		"reth":    {LatencyMs: 400},
+	}
+	if slow := slowClients(timings, DefaultSlowFactor); len(slow) != 1 || slow[0] != "reth" {
+		t.Errorf("slowClients = %v, want [reth]", slow)
+	}
+	if others := othersMedian(timings, "reth"); others != 60 {
+		t.Errorf("othersMedian = %v, want 60", others)
+	}
+}
+
+// Workers storing the same call at once each write their own temporary file,
+// and the entry left behind is whole.
+func TestResponseCacheConcurrentPut(t *testing.T) {
+	rc := &responseCache{dir: t.TempDir(), chainID: "0x1", client: "archive"}
+	params := []interface{}{"0xabc", "0x100"}
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": "0x10"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := rc.put("eth_getBalance", params, response); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cached, ok := rc.get("eth_getBalance", params)
+	if !ok || cached["result"] != "0x10" {
+		t.Fatalf("get = %v, %v", cached, ok)
+	}
+	path, err := rc.cachePath("eth_getBalance", params, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	if len(leftovers) != 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+}
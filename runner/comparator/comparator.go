@@ -1,6 +1,9 @@
 package comparator
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -30,6 +33,21 @@ type ComparisonResult struct {
 	TransportErrors map[string]string      `json:"transport_errors,omitempty"`
 	ErrorClass      map[string]string      `json:"error_class,omitempty"`
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	// Ledger is the call's known-differences status (see ApplyLedger); empty
+	// when no ledger was applied or the call has no differences
+	Ledger string `json:"ledger,omitempty"`
+	// Consensus attributes the differences to the clients outside the
+	// majority; set when three or more clients answered
+	Consensus *CallConsensus `json:"consensus,omitempty"`
+	// ConsensusError is why the majority vote could not be computed; the
+	// differences against the reference still stand
+	ConsensusError string `json:"consensus_error,omitempty"`
+	// Timings holds each live client's latency and response size, or marks
+	// an answer served from the response cache; Slow lists
+	// the clients that answered more than SlowFactor times slower than the
+	// others
+	Timings map[string]ClientTiming `json:"timings,omitempty"`
+	Slow    []string                `json:"slow,omitempty"`
 }
 
 // hasDifferences reports whether the call has any post-filter differences.
@@ -52,6 +70,12 @@ func (r ComparisonResult) isEnvDifference() bool {
 	return r.hasDifferences() && len(r.ErrorClass) > 0
 }
 
+// isKnownDifference reports whether every difference of the call is accepted
+// by the known-differences ledger.
+func (r ComparisonResult) isKnownDifference() bool {
+	return r.hasDifferences() && r.Ledger == LedgerKnown
+}
+
 // ComparisonConfig represents the configuration for response comparison.
 //
 // Methods holds internal identifiers (e.g. "eth_getBalance_variant1" or
@@ -93,6 +117,28 @@ type ComparisonConfig struct {
 	// SkipAboveHead skips calls pinned to a numeric block above the lowest
 	// client head.
 	SkipAboveHead bool `json:"skip_above_head,omitempty"`
+
+	// SampleSeed is the seed calls were sampled from a corpus with; nil when
+	// every call was kept
+	SampleSeed *int64 `json:"sample_seed,omitempty"`
+
+	// AgainstGolden, when set, compares the clients to the golden snapshots in
+	// this directory, which answer as the reference client "golden"
+	AgainstGolden string `json:"against_golden,omitempty"`
+
+	// SlowFactor flags a client answering a call more than this many times
+	// slower than the median of the other clients (DefaultSlowFactor if unset)
+	SlowFactor float64 `json:"slow_factor,omitempty"`
+
+	// ResponseCache, when set, is the directory the reference client's
+	// block-pinned responses are cached in across runs
+	ResponseCache string `json:"response_cache,omitempty"`
+
+	// StreamPath, when set, is the JSONL file every result is appended to as
+	// it completes instead of being kept in memory. With Resume the stream is
+	// reopened and the calls it already holds are skipped.
+	StreamPath string `json:"-"`
+	Resume     bool   `json:"-"`
 }
 
 // Comparator handles comparing responses between different Ethereum clients
@@ -104,6 +150,21 @@ type Comparator struct {
 	results   []ComparisonResult
 	skipped   []skippedCall
 	verbose   bool
+
+	// stream holds the results when StreamPath is set; resumed lists the calls
+	// it held when the run was resumed
+	stream    *resultStream
+	resumed   map[string]bool
+	completed int
+
+	ledger       *ledgerState
+	ledgerReport *LedgerReport
+
+	// tallies caches the run-wide tallies of a streamed run; recording a
+	// result or applying a ledger clears it
+	tallies *runTallies
+
+	cache *responseCache
 }
 
 // skippedCall records a call omitted because it pins to a block above the
@@ -141,13 +202,58 @@ func NewComparator(cfg *ComparisonConfig) (*Comparator, error) {
 		cfg.TimeoutSeconds = 10 // 10 seconds
 	}
 
-	return &Comparator{
+	if cfg.SlowFactor <= 0 {
+		cfg.SlowFactor = DefaultSlowFactor
+	}
+
+	// Golden snapshots answer first, as the reference
+	if cfg.AgainstGolden != "" {
+		if info, err := os.Stat(cfg.AgainstGolden); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("golden directory %s is not readable", cfg.AgainstGolden)
+		}
+		if len(cfg.Clients) == 0 || cfg.Clients[0].Name != GoldenClientName {
+			cfg.Clients = append([]*types.ClientConfig{goldenClient(cfg.AgainstGolden)}, cfg.Clients...)
+		}
+	}
+
+	c := &Comparator{
 		config:    cfg,
 		validator: validator,
 		outputDir: cfg.OutputDir,
 		results:   make([]ComparisonResult, 0),
 		verbose:   cfg.Verbose,
-	}, nil
+	}
+
+	// Only the reference client is cached: it is the one every build is
+	// compared against, so the same calls hit it run after run
+	if cfg.ResponseCache != "" {
+		live := c.liveClients()
+		if len(live) == 0 {
+			return nil, fmt.Errorf("response cache needs a live client")
+		}
+		c.cache, err = c.newResponseCache(cfg.ResponseCache, live[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.StreamPath != "" {
+		clientNames := make([]string, len(cfg.Clients))
+		for i, client := range cfg.Clients {
+			clientNames[i] = client.Name
+		}
+		checkpoint := streamCheckpoint{RequestSetHash: requestSetHash(cfg), Clients: clientNames, StartedAt: time.Now()}
+		if err := checkCheckpoint(filepath.Dir(cfg.StreamPath), checkpoint, cfg.Resume); err != nil {
+			return nil, err
+		}
+		c.stream, c.resumed, err = openResultStream(cfg.StreamPath, cfg.Resume)
+		if err != nil {
+			return nil, err
+		}
+		c.completed = len(c.resumed)
+	}
+
+	return c, nil
 }
 
 // CompareResponses compares responses from different clients for a specific method and parameters
@@ -156,6 +262,7 @@ func (c *Comparator) CompareResponses(method string, params []interface{}) (*Com
 	schemaErrors := make(map[string][]string)
 	transportErrors := make(map[string]string)
 	errorClass := make(map[string]string)
+	timings := make(map[string]ClientTiming)
 
 	// Recover the real JSON-RPC method name from the identifier; loaders set
 	// MethodRPCNames to point each identifier (e.g. eth_call_variant1) at the
@@ -176,8 +283,31 @@ func (c *Comparator) CompareResponses(method string, params []interface{}) (*Com
 	// an otherwise good comparison.
 	answered := make([]*types.ClientConfig, 0, len(c.config.Clients))
 	for _, client := range c.config.Clients {
-		maxAttempts, baseDelay := c.retryParams(client)
-		response, err := makeJSONRPCCall(client.URL, rpcMethod, callParams, c.config.TimeoutSeconds, c.verbose, maxAttempts, baseDelay)
+		var response map[string]interface{}
+		var err error
+		cached := false
+		useCache := c.cache != nil && client.Name == c.cache.client
+		if useCache {
+			response, cached = c.cache.get(rpcMethod, callParams)
+			if cached {
+				timings[client.Name] = ClientTiming{Cached: true}
+			}
+		}
+		if c.isGolden(client) {
+			response, err = c.goldenResponse(rpcMethod, params)
+		} else if !cached {
+			maxAttempts, baseDelay := c.retryParams(client)
+			var timing ClientTiming
+			response, timing, err = timedJSONRPCCall(client.URL, rpcMethod, callParams, c.config.TimeoutSeconds, c.verbose, maxAttempts, baseDelay)
+			if err == nil {
+				timings[client.Name] = timing
+				if useCache {
+					if err := c.cache.put(rpcMethod, callParams, response); err != nil {
+						log.Printf("Failed to cache the %s response of %s: %v", rpcMethod, client.Name, err)
+					}
+				}
+			}
+		}
 		if err != nil {
 			transportErrors[client.Name] = err.Error()
 			continue
@@ -223,6 +353,22 @@ func (c *Comparator) CompareResponses(method string, params []interface{}) (*Com
 		}
 	}
 
+	// With three or more answers, find who is the odd one out
+	var consensus *CallConsensus
+	var consensusError string
+	if len(answered) >= 3 && len(differences) > 0 {
+		names := make([]string, len(answered))
+		for i, client := range answered {
+			names[i] = client.Name
+		}
+		var err error
+		consensus, err = majorityConsensus(newDiffContext(rpcMethod, c.config.Rules), names, responses, differences)
+		if err != nil {
+			log.Printf("Failed to compute majority consensus for %s, keeping the differences against %s: %v", method, names[0], err)
+			consensusError = err.Error()
+		}
+	}
+
 	// Create comparison result
 	result := &ComparisonResult{
 		Method:          method,
@@ -233,15 +379,19 @@ func (c *Comparator) CompareResponses(method string, params []interface{}) (*Com
 		SchemaErrors:    schemaErrors,
 		TransportErrors: transportErrors,
 		ErrorClass:      errorClass,
+		Consensus:       consensus,
+		ConsensusError:  consensusError,
+		Timings:         timings,
+		Slow:            slowClients(timings, c.config.SlowFactor),
 		Metadata: map[string]interface{}{
 			"clients": c.config.Clients,
 		},
 	}
 
 	// Save result
-	c.mutex.Lock()
-	c.results = append(c.results, *result)
-	c.mutex.Unlock()
+	if err := c.record(result); err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
@@ -264,34 +414,43 @@ func (c *Comparator) retryParams(client *types.ClientConfig) (maxAttempts int, b
 	return maxAttempts, baseDelay
 }
 
+// fetchChainID asks client for its eth_chainId
+func (c *Comparator) fetchChainID(client *types.ClientConfig) (string, error) {
+	maxAttempts, baseDelay := c.retryParams(client)
+	response, err := makeJSONRPCCall(client.URL, "eth_chainId", []interface{}{}, c.config.TimeoutSeconds, c.verbose, maxAttempts, baseDelay)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chainId from %s: %w", client.Name, err)
+	}
+
+	// Extract chainId from response
+	result, ok := response["result"]
+	if !ok {
+		return "", fmt.Errorf("invalid response from %s: missing result field", client.Name)
+	}
+
+	chainID, ok := result.(string)
+	if !ok {
+		return "", fmt.Errorf("invalid chainId from %s: expected string, got %T", client.Name, result)
+	}
+	return chainID, nil
+}
+
 // VerifyNetworkConsistency checks if all clients are on the same network by comparing eth_chainId
 func (c *Comparator) VerifyNetworkConsistency() error {
 	// Skip if there's only one client
-	if len(c.config.Clients) <= 1 {
+	clients := c.liveClients()
+	if len(clients) <= 1 {
 		return nil
 	}
 
 	// Get chainId from all clients
 	chainIDs := make(map[string]string)
-	for _, client := range c.config.Clients {
-		maxAttempts, baseDelay := c.retryParams(client)
-		response, err := makeJSONRPCCall(client.URL, "eth_chainId", []interface{}{}, c.config.TimeoutSeconds, c.verbose, maxAttempts, baseDelay)
+	for _, client := range clients {
+		chainID, err := c.fetchChainID(client)
 		if err != nil {
-			return fmt.Errorf("failed to get chainId from %s: %w", client.Name, err)
-		}
-
-		// Extract chainId from response
-		result, ok := response["result"]
-		if !ok {
-			return fmt.Errorf("invalid response from %s: missing result field", client.Name)
-		}
-
-		chainIDStr, ok := result.(string)
-		if !ok {
-			return fmt.Errorf("invalid chainId from %s: expected string, got %T", client.Name, result)
+			return err
 		}
-
-		chainIDs[client.Name] = chainIDStr
+		chainIDs[client.Name] = chainID
 	}
 
 	// Check if all chainIds are the same
@@ -314,46 +473,50 @@ func (c *Comparator) VerifyNetworkConsistency() error {
 	return nil
 }
 
-// RunComparisons runs comparisons for all configured methods
+// RunComparisons runs comparisons for all configured methods on a fixed pool
+// of Concurrency workers, skipping the calls a resumed stream already holds.
+// Results are returned only when they are kept in memory.
 func (c *Comparator) RunComparisons() ([]ComparisonResult, error) {
 	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, c.config.Concurrency)
-	errCh := make(chan error, len(c.config.Methods))
-
-	for _, method := range c.config.Methods {
-		// Loaders populate CustomParameters for every identifier they emit;
-		// the empty-slice fallback preserves behaviour for callers that pass
-		// bare method names without a matching CustomParameters entry (the
-		// OpenRPC loader does this for 0-arg methods and for base names when
-		// per-method variations are also present).
-		params := c.config.CustomParameters[method]
-		if params == nil {
-			params = []interface{}{}
-		}
+	var errMu sync.Mutex
+	var errors []string
+	jobs := make(chan string)
 
+	for i := 0; i < c.config.Concurrency; i++ {
 		wg.Add(1)
-		go func(method string, params []interface{}) {
+		go func() {
 			defer wg.Done()
-
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
-
-			_, err := c.CompareResponses(method, params)
-			if err != nil {
-				errCh <- fmt.Errorf("comparison failed for %s: %w", method, err)
+			for method := range jobs {
+				// Loaders populate CustomParameters for every identifier they
+				// emit; the empty-slice fallback preserves behaviour for callers
+				// that pass bare method names without a matching
+				// CustomParameters entry (the OpenRPC loader does this for 0-arg
+				// methods and for base names when per-method variations are
+				// also present).
+				params := c.config.CustomParameters[method]
+				if params == nil {
+					params = []interface{}{}
+				}
+
+				if _, err := c.CompareResponses(method, params); err != nil {
+					errMu.Lock()
+					errors = append(errors, fmt.Sprintf("comparison failed for %s: %v", method, err))
+					errMu.Unlock()
+				}
 			}
-		}(method, params)
+		}()
 	}
 
+	for _, method := range c.config.Methods {
+		if c.resumed[method] {
+			continue
+		}
+		jobs <- method
+	}
+	close(jobs)
+
 	// Wait for all comparisons to complete
 	wg.Wait()
-	close(errCh)
-
-	// Check for errors
-	var errors []string
-	for err := range errCh {
-		errors = append(errors, err.Error())
-	}
 
 	if len(errors) > 0 {
 		return c.results, fmt.Errorf("some comparisons failed: %s", strings.Join(errors, "; "))
@@ -422,7 +585,7 @@ func (c *Comparator) applySkipAboveHead() error {
 func (c *Comparator) lowestHead() (uint64, error) {
 	var lowest uint64
 	first := true
-	for _, client := range c.config.Clients {
+	for _, client := range c.liveClients() {
 		maxAttempts, baseDelay := c.retryParams(client)
 		resp, err := makeJSONRPCCall(client.URL, "eth_blockNumber", []interface{}{}, c.config.TimeoutSeconds, c.verbose, maxAttempts, baseDelay)
 		if err != nil {
@@ -446,7 +609,8 @@ func (c *Comparator) lowestHead() (uint64, error) {
 }
 
 // SaveResults saves comparison results to a JSON file, honoring the diff-only
-// and response-trimming output options.
+// and response-trimming output options. Results are encoded one at a time, so
+// a streamed run is never loaded into memory whole.
 func (c *Comparator) SaveResults(filename string) error {
 	// Use the provided filename directly as it should already be a full path
 	outputPath := filename
@@ -457,37 +621,77 @@ func (c *Comparator) SaveResults(filename string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Marshal results to JSON
-	data, err := json.MarshalIndent(c.resultsForOutput(), "", "  ")
+	file, err := os.Create(outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal results: %w", err)
+		return fmt.Errorf("failed to write results: %w", err)
 	}
+	defer file.Close()
+	w := bufio.NewWriter(file)
 
-	// Write to file
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+	// Same layout as json.MarshalIndent of the whole slice
+	written := 0
+	err = c.eachResult(func(r *ComparisonResult) error {
+		out, ok := c.outputResult(*r)
+		if !ok {
+			return nil
+		}
+		data, err := json.MarshalIndent(out, "  ", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		sep := ",\n  "
+		if written == 0 {
+			sep = "[\n  "
+		}
+		written++
+		if _, err := w.WriteString(sep); err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	closing := "\n]"
+	if written == 0 {
+		closing = "[]"
+	}
+	if _, err := w.WriteString(closing); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	if err := w.Flush(); err != nil {
 		return fmt.Errorf("failed to write results: %w", err)
 	}
-
 	return nil
 }
 
 // resultsForOutput applies the output options (--diff-only,
 // --omit-matching-responses, --max-response-bytes) to a copy of the results so
 // serialization stays small. The stored results are never mutated.
-func (c *Comparator) resultsForOutput() []ComparisonResult {
-	out := make([]ComparisonResult, 0, len(c.results))
-	for _, r := range c.results {
-		if c.config.DiffOnly && r.isIdentical() {
-			continue
+func (c *Comparator) resultsForOutput() ([]ComparisonResult, error) {
+	var out []ComparisonResult
+	err := c.eachResult(func(r *ComparisonResult) error {
+		if o, ok := c.outputResult(*r); ok {
+			out = append(out, o)
 		}
-		if c.config.OmitMatchingResponses {
-			r.Responses = nil
-		} else if c.config.MaxResponseBytes > 0 {
-			r.Responses = truncateResponses(r.Responses, c.config.MaxResponseBytes)
-		}
-		out = append(out, r)
+		return nil
+	})
+	return out, err
+}
+
+// outputResult applies the output options to one result; false means the
+// result is left out of the output
+func (c *Comparator) outputResult(r ComparisonResult) (ComparisonResult, bool) {
+	if c.config.DiffOnly && r.isIdentical() {
+		return r, false
 	}
-	return out
+	if c.config.OmitMatchingResponses {
+		r.Responses = nil
+	} else if c.config.MaxResponseBytes > 0 {
+		r.Responses = truncateResponses(r.Responses, c.config.MaxResponseBytes)
+	}
+	return r, true
 }
 
 // truncateResponses replaces any response whose JSON encoding exceeds maxBytes
@@ -513,75 +717,91 @@ func truncateResponses(responses map[string]interface{}, maxBytes int) map[strin
 
 // Summary tallies the results by outcome category. Differ counts real result
 // mismatches; DifferEnv counts mismatches attributable to an environment or
-// capability error (see classifyError).
+// capability error (see classifyError); Known counts mismatches the
+// known-differences ledger accepts. MajorityDisagreements counts, per client
+// and JSON-RPC method, the calls on which the client disagreed with the
+// majority of three or more clients. Slow counts, per client, the calls it
+// answered more than SlowFactor times slower than the others.
 type Summary struct {
 	Total          int
 	Identical      int
 	Differ         int
 	DifferEnv      int
+	Known          int
 	TransportError int
 	SchemaError    int
 	EnvError       map[string]int
 	Skipped        int
+
+	MajorityDisagreements map[string]map[string]int
+	Slow                  map[string]int
 }
 
-// Summarize computes the outcome tally for the completed run.
+// Summarize computes the outcome tally for the completed run. A results
+// stream that cannot be read back is logged and tallied as far as it goes.
 func (c *Comparator) Summarize() Summary {
-	s := Summary{Total: len(c.results), EnvError: map[string]int{}, Skipped: len(c.skipped)}
-	for _, r := range c.results {
-		switch {
-		case r.hasDifferences():
-			if r.isEnvDifference() {
-				s.DifferEnv++
-			} else {
-				s.Differ++
+	return c.runTallies().summary
+}
+
+// newSummary returns an empty tally of a run that skipped skipped calls
+func newSummary(skipped int) Summary {
+	return Summary{EnvError: map[string]int{}, Skipped: skipped, MajorityDisagreements: map[string]map[string]int{}, Slow: map[string]int{}}
+}
+
+// add tallies one result
+func (s *Summary) add(r *ComparisonResult, cfg *ComparisonConfig) {
+	s.Total++
+	for _, client := range r.Slow {
+		s.Slow[client]++
+	}
+	if r.Consensus != nil {
+		method := cfg.rpcMethodName(r.Method)
+		for client := range r.Consensus.Minority {
+			if s.MajorityDisagreements[client] == nil {
+				s.MajorityDisagreements[client] = map[string]int{}
 			}
-		case len(r.TransportErrors) > 0:
-			s.TransportError++
-		case len(r.SchemaErrors) > 0:
-			s.SchemaError++
-		default:
-			s.Identical++
+			s.MajorityDisagreements[client][method]++
 		}
-		for _, cls := range r.ErrorClass {
-			s.EnvError[cls]++
+	}
+	switch {
+	case r.hasDifferences():
+		if r.isKnownDifference() {
+			s.Known++
+		} else if r.isEnvDifference() {
+			s.DifferEnv++
+		} else {
+			s.Differ++
 		}
+	case len(r.TransportErrors) > 0:
+		s.TransportError++
+	case len(r.SchemaErrors) > 0:
+		s.SchemaError++
+	default:
+		s.Identical++
+	}
+	for _, cls := range r.ErrorClass {
+		s.EnvError[cls]++
 	}
-	return s
 }
 
 // HasDifferences reports whether any call has post-filter differences,
 // including environment/expected ones.
 func (c *Comparator) HasDifferences() bool {
-	for _, r := range c.results {
-		if r.hasDifferences() {
-			return true
-		}
-	}
-	return false
+	s := c.Summarize()
+	return s.Differ+s.DifferEnv+s.Known > 0
 }
 
 // HasRealDifferences reports whether any call has a real result mismatch (not
-// attributable to an environment/capability error). This is what --fail-on-diff
-// trips on by default.
+// attributable to an environment/capability error, nor accepted by the
+// ledger). This is what --fail-on-diff trips on by default.
 func (c *Comparator) HasRealDifferences() bool {
-	for _, r := range c.results {
-		if r.hasDifferences() && !r.isEnvDifference() {
-			return true
-		}
-	}
-	return false
+	return c.Summarize().Differ > 0
 }
 
 // HasEnvDifferences reports whether any differing call is attributable to an
-// environment/capability error.
+// environment/capability error and not accepted by the ledger.
 func (c *Comparator) HasEnvDifferences() bool {
-	for _, r := range c.results {
-		if r.isEnvDifference() {
-			return true
-		}
-	}
-	return false
+	return c.Summarize().DifferEnv > 0
 }
 
 // Provenance returns the effective comparison configuration so a report is
@@ -605,8 +825,12 @@ func (c *Comparator) Provenance() map[string]interface{} {
 		"omit_matching_responses": c.config.OmitMatchingResponses,
 		"max_response_bytes":      c.config.MaxResponseBytes,
 		"skip_above_head":         c.config.SkipAboveHead,
+		"slow_factor":             c.config.SlowFactor,
 		"call_count":              len(c.config.Methods),
 		"skipped":                 c.skipped,
+		"resumed_calls":           len(c.resumed),
+		"response_cache":          c.CacheStats(),
+		"majority_disagreements":  c.Summarize().MajorityDisagreements,
 	}
 }
 
@@ -626,17 +850,61 @@ func (c *Comparator) SaveProvenance(filename string) error {
 	return nil
 }
 
+// Manifest returns the comparison's reproducibility metadata: the config and
+// request-set hashes and the sampling seed. The caller adds the host, client
+// versions and artifacts.
+func (c *Comparator) Manifest() *types.RunManifest {
+	manifest := &types.RunManifest{
+		Kind:          types.ManifestKindCompare,
+		TestName:      c.config.Name,
+		Seed:          c.config.SampleSeed,
+		EngineVersion: "runner compare",
+	}
+	if data, err := json.Marshal(c.config); err == nil {
+		sum := sha256.Sum256(data)
+		manifest.ConfigHash = hex.EncodeToString(sum[:])
+	}
+	manifest.RequestSetHash = requestSetHash(c.config)
+	return manifest
+}
+
+// requestSetHash is the SHA-256 of the calls a config makes: identifiers,
+// wire methods and params
+func requestSetHash(cfg *ComparisonConfig) string {
+	requests := struct {
+		Methods          []string                 `json:"methods"`
+		MethodRPCNames   map[string]string        `json:"method_rpc_names"`
+		CustomParameters map[string][]interface{} `json:"custom_parameters"`
+	}{cfg.Methods, cfg.MethodRPCNames, cfg.CustomParameters}
+	data, err := json.Marshal(requests)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 // GenerateReport generates an HTML report from comparison results
 func (c *Comparator) GenerateReport(outputPath string) error {
 	// This will be implemented in a separate file
 	return nil
 }
 
-// GetResults returns all comparison results
+// GetResults returns all comparison results; empty when they are streamed
+// (see ComparisonConfig.StreamPath)
 func (c *Comparator) GetResults() []ComparisonResult {
 	return c.results
 }
 
+// rpcMethodName returns the JSON-RPC method a call identifier invokes, so
+// variants of one method are reported together.
+func (c *ComparisonConfig) rpcMethodName(method string) string {
+	if name, ok := c.MethodRPCNames[method]; ok && name != "" {
+		return name
+	}
+	return method
+}
+
 // MergeComparisonRules layers rules from a --rules file on top of the rules
 // already on the config. Layered rules are evaluated first, so they take
 // precedence over config rules on the same path.
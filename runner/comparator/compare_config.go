@@ -43,11 +43,15 @@ type rulesFile struct {
 }
 
 // validateRuleKinds returns an error describing the first rule with an unknown
-// kind, using ctx to identify where the rule came from.
+// kind or invalid kind-specific fields, using ctx to identify where the rule
+// came from.
 func validateRuleKinds(rules []ComparisonRule, ctx string) error {
 	for i, rule := range rules {
 		if !ValidRuleKind(rule.Kind) {
-			return fmt.Errorf("%s: rules[%d]: unknown kind %q (want ignore, numeric_tolerance, error_code_only or error_presence_only)", ctx, i, rule.Kind)
+			return fmt.Errorf("%s: rules[%d]: unknown kind %q (want ignore, numeric_tolerance, error_code_only, error_presence_only, unordered, regex, hex_normalize, null_equals_missing or only)", ctx, i, rule.Kind)
+		}
+		if err := validateRule(rule); err != nil {
+			return fmt.Errorf("%s: rules[%d]: %w", ctx, i, err)
 		}
 	}
 	return nil
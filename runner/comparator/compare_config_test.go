@@ -317,3 +317,24 @@ calls:
 		})
 	}
 }
+
+func TestLoadCompareConfig_InvalidRuleFields(t *testing.T) {
+	for _, rule := range []string{
+		"- path: result\n      kind: regex",
+		"- path: result\n      kind: regex\n      pattern: \"[\"",
+		"- kind: only",
+	} {
+		path := writeCompareFixture(t, `
+name: "bad rule"
+comparison:
+  rules:
+    `+rule+`
+calls:
+  eth_blockNumber:
+    - params: []
+`)
+		if _, err := LoadCompareConfig(path); err == nil || !strings.Contains(err.Error(), "rules[0]") {
+			t.Errorf("expected an error for %q, got %v", rule, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package comparator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CallConsensus attributes a call's differences to the clients that disagree
+// with the majority. It is only computed when three or more clients answered
+// and they did not all agree.
+type CallConsensus struct {
+	// Minority maps each client to the paths where it disagrees with the
+	// majority value
+	Minority map[string][]string `json:"minority,omitempty"`
+	// Split lists the paths on which no value is held by more than half of the
+	// clients
+	Split []string `json:"split,omitempty"`
+}
+
+// majorityConsensus compares every pair of answered clients under the same
+// rules as the reference comparison and, for each differing path, groups the
+// clients that agree on it. refDiffs are the differences already computed
+// against names[0], keyed by client.
+func majorityConsensus(ctx *diffContext, names []string, responses map[string]interface{}, refDiffs map[string]interface{}) (*CallConsensus, error) {
+	n := len(names)
+	pairPaths := make([][]map[string]bool, n)
+	for i := range pairPaths {
+		pairPaths[i] = make([]map[string]bool, n)
+	}
+	allPaths := make(map[string]bool)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			var diff interface{}
+			if i == 0 {
+				diff = refDiffs[names[j]]
+			} else {
+				left, ok := responses[names[i]].(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("response of %s is %T, not a JSON-RPC object", names[i], responses[names[i]])
+				}
+				right, ok := responses[names[j]].(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("response of %s is %T, not a JSON-RPC object", names[j], responses[names[j]])
+				}
+				d, err := compareJSONRPCResponses(ctx, left, right)
+				if err != nil {
+					return nil, fmt.Errorf("failed to compare %s with %s: %w", names[i], names[j], err)
+				}
+				diff = d
+			}
+			paths := make(map[string]bool)
+			for _, entry := range flattenDifferences(diff) {
+				paths[entry.Path] = true
+				allPaths[entry.Path] = true
+			}
+			pairPaths[i][j], pairPaths[j][i] = paths, paths
+		}
+	}
+	if len(allPaths) == 0 {
+		return nil, nil
+	}
+
+	sorted := make([]string, 0, len(allPaths))
+	for path := range allPaths {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+
+	consensus := &CallConsensus{}
+	for _, path := range sorted {
+		// Group clients by agreement with each group's first member
+		var groups [][]int
+		for i := 0; i < n; i++ {
+			placed := false
+			for g, group := range groups {
+				if !differsAt(pairPaths[group[0]][i], path) {
+					groups[g] = append(groups[g], i)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				groups = append(groups, []int{i})
+			}
+		}
+
+		majority := -1
+		for g, group := range groups {
+			if len(group)*2 > n {
+				majority = g
+			}
+		}
+		if majority < 0 {
+			consensus.Split = append(consensus.Split, path)
+			continue
+		}
+		for g, group := range groups {
+			if g == majority {
+				continue
+			}
+			for _, i := range group {
+				if consensus.Minority == nil {
+					consensus.Minority = make(map[string][]string)
+				}
+				consensus.Minority[names[i]] = append(consensus.Minority[names[i]], path)
+			}
+		}
+	}
+	return consensus, nil
+}
+
+// differsAt reports whether a pair's differing paths cover path, either at
+// the path itself or at one of its ancestors (a missing object, a type
+// mismatch)
+func differsAt(paths map[string]bool, path string) bool {
+	for p := path; p != ""; p = parentPath(p) {
+		if paths[p] {
+			return true
+		}
+	}
+	return false
+}
+
+// parentPath strips the last field or index from a diff path
+func parentPath(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i <= 0 {
+		return ""
+	}
+	return path[:i]
+}
@@ -0,0 +1,100 @@
+package comparator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// With the reference client in the minority, the difference is attributed to
+// the reference, not to the clients it disagrees with.
+func TestCompareResponsesAttributesMinority(t *testing.T) {
+	block := func(yParity bool, gasUsed string) func(rpcRequest) interface{} {
+		return func(rpcRequest) interface{} {
+			tx := map[string]interface{}{"hash": "0x1"}
+			if yParity {
+				tx["yParity"] = "0x1"
+			}
+			return map[string]interface{}{"gasUsed": gasUsed, "transactions": []interface{}{tx}}
+		}
+	}
+	clients := []*types.ClientConfig{
+		{Name: "erigon", URL: newRPCFake(t, "0x1", block(false, "0x5")).URL},
+		{Name: "geth", URL: newRPCFake(t, "0x1", block(true, "0x5")).URL},
+		{Name: "nethermind", URL: newRPCFake(t, "0x1", block(true, "0x6")).URL},
+		{Name: "reth", URL: newRPCFake(t, "0x1", block(true, "0x7")).URL},
+	}
+	c, err := NewComparator(&ComparisonConfig{Clients: clients, OutputDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.CompareResponses("eth_getBlockByNumber", []interface{}{"0x1", true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Consensus == nil {
+		t.Fatal("consensus should be computed with four clients")
+	}
+	want := map[string][]string{"erigon": {"result.transactions[0].yParity"}}
+	if !reflect.DeepEqual(result.Consensus.Minority, want) {
+		t.Errorf("minority = %v, want %v", result.Consensus.Minority, want)
+	}
+	// gasUsed is 0x5, 0x5, 0x6, 0x7: no value has more than half
+	if !reflect.DeepEqual(result.Consensus.Split, []string{"result.gasUsed"}) {
+		t.Errorf("split = %v", result.Consensus.Split)
+	}
+
+	s := c.Summarize()
+	if s.MajorityDisagreements["erigon"]["eth_getBlockByNumber"] != 1 || len(s.MajorityDisagreements) != 1 {
+		t.Errorf("majority disagreements = %v", s.MajorityDisagreements)
+	}
+	out := filepath.Join(t.TempDir(), "comparison-provenance.json")
+	if err := c.SaveProvenance(out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var provenance struct {
+		MajorityDisagreements map[string]map[string]int `json:"majority_disagreements"`
+	}
+	if err := json.Unmarshal(data, &provenance); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(provenance.MajorityDisagreements, s.MajorityDisagreements) {
+		t.Errorf("provenance majority disagreements = %v, want %v", provenance.MajorityDisagreements, s.MajorityDisagreements)
+	}
+}
+
+// A missing parent object counts as disagreeing on every path below it.
+func TestDiffersAtAncestor(t *testing.T) {
+	paths := map[string]bool{"result.block": true}
+	if !differsAt(paths, "result.block.transactions[2].v") {
+		t.Error("a difference at an ancestor should cover the path")
+	}
+	if differsAt(paths, "result.blockHash") {
+		t.Error("a sibling with a common prefix is not an ancestor")
+	}
+}
+
+// A response that is not a JSON-RPC object fails the vote instead of
+// panicking, so the caller can keep the reference differences.
+func TestMajorityConsensusRejectsNonObjectResponse(t *testing.T) {
+	names := []string{"geth", "nethermind", "reth"}
+	responses := map[string]interface{}{
+		"geth":       map[string]interface{}{"result": "0x1"},
+		"nethermind": "not an object",
+		"reth":       map[string]interface{}{"result": "0x2"},
+	}
+	refDiffs := map[string]interface{}{"reth": map[string]interface{}{"result": "differs"}}
+	consensus, err := majorityConsensus(newDiffContext("eth_call", nil), names, responses, refDiffs)
+	if err == nil || consensus != nil {
+		t.Errorf("majorityConsensus = %+v, %v; want an error", consensus, err)
+	}
+}
@@ -104,6 +104,9 @@ func LoadCorpusConfig(dir string, sample int, seed int64, blockOverride string)
 		MethodRPCNames:   make(map[string]string),
 		CustomParameters: make(map[string][]interface{}),
 	}
+	if sample > 0 {
+		cfg.SampleSeed = &seed
+	}
 
 	for _, method := range order {
 		calls := sampleCalls(byMethod[method], sample, rng)
@@ -178,6 +181,12 @@ func sampleCalls(calls [][]interface{}, n int, rng *rand.Rand) [][]interface{} {
 	return out
 }
 
+// CorpusDrops reports whether loading a corpus drops method: always, or
+// only without a block override
+func CorpusDrops(method string) (always, withoutBlockOverride bool) {
+	return isCorpusExcluded(method, true), isCorpusExcluded(method, false)
+}
+
 func isCorpusExcluded(method string, keepPinnable bool) bool {
 	if strings.HasPrefix(method, "debug_") {
 		return true
@@ -70,9 +70,20 @@ func compareJSONRPCResponses(ctx *diffContext, resp1, resp2 map[string]interface
 		}
 	}
 
-	// If both have results, compare them
+	// If both have results, compare them; tracer output is aligned by call
+	// frame and account rather than by array index
 	if hasResult1 && hasResult2 {
-		resultDiffs, err := deepCompare(ctx, "result", result1, result2)
+		var resultDiffs []DiffEntry
+		var err error
+		if traceMethods[ctx.method] {
+			trace := &traceDiff{ctx: ctx}
+			resultDiffs, err = trace.compare("result", result1, result2)
+			if trace.divergence != nil {
+				differences["trace_divergence"] = trace.divergence
+			}
+		} else {
+			resultDiffs, err = deepCompare(ctx, "result", result1, result2)
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to compare results: %w", err)
 		}
@@ -114,11 +125,19 @@ func compareJSONRPCResponses(ctx *diffContext, resp1, resp2 map[string]interface
 
 // deepCompare recursively compares two values and returns their differences
 func deepCompare(ctx *diffContext, path string, val1, val2 interface{}) ([]DiffEntry, error) {
-	// An ignore rule drops this path (and its whole subtree) from comparison.
-	if ctx.rules.matchesIgnore(path) {
+	// An ignore rule drops this path (and its whole subtree) from comparison,
+	// as do only rules that select other paths.
+	if ctx.rules.matchesIgnore(path) || ctx.rules.skippedByOnly(path) {
 		return nil, nil
 	}
 
+	// A regex rule accepts any two scalars that both match its pattern.
+	if rule, ok := firstMatch(ctx.rules.regexes, path); ok && isScalar(val1) && isScalar(val2) {
+		if rule.value.MatchString(fmt.Sprint(val1)) && rule.value.MatchString(fmt.Sprint(val2)) {
+			return nil, nil
+		}
+	}
+
 	// Handle nil values
 	if val1 == nil && val2 == nil {
 		return nil, nil
@@ -159,6 +178,15 @@ func deepCompare(ctx *diffContext, path string, val1, val2 interface{}) ([]DiffE
 		// Special case for Ethereum hex strings: treat 0x and 0x0000...0000 as equal
 		if str1, ok1 := val1.(string); ok1 {
 			if str2, ok2 := val2.(string); ok2 {
+				// A hex_normalize rule ignores case and leading zeros.
+				if _, ok := firstMatch(ctx.rules.hexNormalize, path); ok {
+					n1, hex1 := normalizeHex(str1)
+					n2, hex2 := normalizeHex(str2)
+					if hex1 && hex2 && n1 == n2 {
+						return nil, nil
+					}
+				}
+
 				// Check if both are hex strings
 				if strings.HasPrefix(str1, "0x") && strings.HasPrefix(str2, "0x") {
 					// Check if one is 0x and the other is a zero-value hex string
@@ -240,13 +268,21 @@ func compareObjects(ctx *diffContext, path string, obj1, obj2 map[string]interfa
 		}
 
 		// An ignore rule drops this key entirely, including missing/extra cases.
-		if ctx.rules.matchesIgnore(keyPath) {
+		if ctx.rules.matchesIgnore(keyPath) || ctx.rules.skippedByOnly(keyPath) {
 			continue
 		}
 
 		val1, exists1 := obj1[key]
 		val2, exists2 := obj2[key]
 
+		// A null_equals_missing rule accepts null on one side and no field on
+		// the other.
+		if exists1 != exists2 && val1 == nil && val2 == nil {
+			if _, ok := firstMatch(ctx.rules.nullEqualsMissing, keyPath); ok {
+				continue
+			}
+		}
+
 		// Check if key exists in both objects
 		if !exists1 {
 			differences = append(differences, DiffEntry{
@@ -282,6 +318,10 @@ func compareObjects(ctx *diffContext, path string, obj1, obj2 map[string]interfa
 
 // compareArrays compares two arrays and returns their differences
 func compareArrays(ctx *diffContext, path string, arr1, arr2 []interface{}) ([]DiffEntry, error) {
+	if rule, ok := firstMatch(ctx.rules.unordered, path); ok {
+		return compareUnordered(ctx, path, arr1, arr2, rule.rule.Key)
+	}
+
 	var differences []DiffEntry
 
 	// Compare array lengths
@@ -328,6 +368,105 @@ func compareArrays(ctx *diffContext, path string, arr1, arr2 []interface{}) ([]D
 	return differences, nil
 }
 
+// compareUnordered compares two arrays as multisets. Without a key, elements
+// match when they compare equal under the nested rules (identical elements
+// are paired first, the rest by comparing them in depth); with one, elements
+// match on that field and matched pairs are compared in depth. Unmatched
+// elements are reported as extra (only in the first array) or missing (only
+// in the second), at their own index.
+func compareUnordered(ctx *diffContext, path string, arr1, arr2 []interface{}, key string) ([]DiffEntry, error) {
+	var differences []DiffEntry
+	if len(arr1) != len(arr2) {
+		differences = append(differences, DiffEntry{
+			Path:   path,
+			Type:   DiffTypeArrayLengthMismatch,
+			Value1: len(arr1),
+			Value2: len(arr2),
+		})
+	}
+
+	identity := func(v interface{}) string {
+		if obj, ok := v.(map[string]interface{}); ok && key != "" {
+			if k, ok := obj[key]; ok {
+				v = k
+			}
+		}
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+
+	unmatched := make(map[string][]int, len(arr2))
+	for j, v := range arr2 {
+		id := identity(v)
+		unmatched[id] = append(unmatched[id], j)
+	}
+	var unpaired []int
+	for i, v := range arr1 {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		id := identity(v)
+		candidates := unmatched[id]
+		if len(candidates) == 0 {
+			if key == "" {
+				unpaired = append(unpaired, i)
+				continue
+			}
+			differences = append(differences, DiffEntry{Path: itemPath, Type: DiffTypeFieldExtra, Value1: v})
+			continue
+		}
+		j := candidates[0]
+		unmatched[id] = candidates[1:]
+		if key != "" {
+			diffs, err := deepCompare(ctx, itemPath, v, arr2[j])
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare %s: %w", itemPath, err)
+			}
+			differences = append(differences, diffs...)
+		}
+	}
+
+	var missing []int
+	for _, indices := range unmatched {
+		missing = append(missing, indices...)
+	}
+	sort.Ints(missing)
+
+	// Without a key, elements that differ only where nested rules (ignore,
+	// tolerances, normalization) allow it still pair up
+	for _, i := range unpaired {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		paired := -1
+		for m, j := range missing {
+			diffs, err := deepCompare(ctx, itemPath, arr1[i], arr2[j])
+			if err != nil {
+				return nil, fmt.Errorf("failed to compare %s: %w", itemPath, err)
+			}
+			if len(diffs) == 0 {
+				paired = m
+				break
+			}
+		}
+		if paired < 0 {
+			differences = append(differences, DiffEntry{Path: itemPath, Type: DiffTypeFieldExtra, Value1: arr1[i]})
+			continue
+		}
+		missing = append(missing[:paired], missing[paired+1:]...)
+	}
+	for _, j := range missing {
+		differences = append(differences, DiffEntry{Path: fmt.Sprintf("%s[%d]", path, j), Type: DiffTypeFieldMissing, Value2: arr2[j]})
+	}
+	return differences, nil
+}
+
+// isScalar reports whether a decoded JSON value is a string, number or bool
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case string, float64, bool, json.Number, int, int64:
+		return true
+	default:
+		return false
+	}
+}
+
 // isZeroHex checks if a hex string contains only zeros after the 0x prefix
 func isZeroHex(hexStr string) bool {
 	// Remove 0x prefix
@@ -231,3 +231,124 @@ func TestClassifyError(t *testing.T) {
 		t.Errorf("classifyError on result response = %q, want empty", got)
 	}
 }
+
+func TestUnorderedArrays(t *testing.T) {
+	logs := func(order ...string) []interface{} {
+		out := make([]interface{}, 0, len(order))
+		for _, idx := range order {
+			out = append(out, map[string]interface{}{"logIndex": idx, "data": "0x" + idx[2:]})
+		}
+		return out
+	}
+
+	// Multiset: same elements in another order are equal
+	ctx := newDiffContext("eth_getLogs", []ComparisonRule{{Path: "result", Kind: RuleUnordered}})
+	diffs, _ := compareJSONRPCResponses(ctx, resultResp(logs("0x1", "0x2")), resultResp(logs("0x2", "0x1")))
+	if hasResultDiff(diffs) {
+		t.Errorf("reordered elements should be equal, got %v", diffs)
+	}
+
+	// Keyed: elements are matched by logIndex and compared in depth
+	ctx = newDiffContext("eth_getLogs", []ComparisonRule{{Path: "result", Kind: RuleUnordered, Key: "logIndex"}})
+	changed := logs("0x2", "0x1")
+	changed[1].(map[string]interface{})["data"] = "0xff"
+	entries, err := deepCompare(ctx, "result", logs("0x1", "0x2"), changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != "result[0].data" || entries[0].Value2 != "0xff" {
+		t.Errorf("keyed unordered diff = %+v", entries)
+	}
+
+	// Unmatched elements are extra or missing at their own index
+	entries, _ = deepCompare(ctx, "result", logs("0x1", "0x3"), logs("0x1", "0x4"))
+	if len(entries) != 2 || entries[0].Type != DiffTypeFieldExtra || entries[1].Type != DiffTypeFieldMissing || entries[1].Path != "result[1]" {
+		t.Errorf("unmatched elements = %+v", entries)
+	}
+
+	// Without a key, nested rules still apply when pairing elements
+	ctx = newDiffContext("eth_getLogs", []ComparisonRule{
+		{Path: "result", Kind: RuleUnordered},
+		{Path: "result[*].blockTimestamp", Kind: RuleIgnore},
+	})
+	stamped := func(ts string, indices ...string) []interface{} {
+		out := logs(indices...)
+		for _, l := range out {
+			l.(map[string]interface{})["blockTimestamp"] = ts
+		}
+		return out
+	}
+	entries, err = deepCompare(ctx, "result", stamped("0x10", "0x1", "0x2"), stamped("0x11", "0x2", "0x1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("elements differing only in an ignored field should pair up, got %+v", entries)
+	}
+	entries, _ = deepCompare(ctx, "result", stamped("0x10", "0x1", "0x3"), stamped("0x11", "0x4", "0x1"))
+	if len(entries) != 2 || entries[0].Path != "result[1]" || entries[0].Type != DiffTypeFieldExtra || entries[1].Path != "result[0]" || entries[1].Type != DiffTypeFieldMissing {
+		t.Errorf("unmatched elements under nested rules = %+v", entries)
+	}
+}
+
+func TestRegexRule(t *testing.T) {
+	ctx := newDiffContext("web3_clientVersion", []ComparisonRule{{Path: "result", Kind: RuleRegex, Pattern: `^[A-Za-z]+/v\d+\.\d+`}})
+	diffs, _ := compareJSONRPCResponses(ctx, resultResp("Geth/v1.14.0-stable"), resultResp("Nethermind/v1.27.0"))
+	if hasResultDiff(diffs) {
+		t.Errorf("values matching the pattern on both sides should be equal, got %v", diffs)
+	}
+	diffs, _ = compareJSONRPCResponses(ctx, resultResp("Geth/v1.14.0-stable"), resultResp("unknown"))
+	if !hasResultDiff(diffs) {
+		t.Error("a value not matching the pattern should still differ")
+	}
+}
+
+func TestHexNormalize(t *testing.T) {
+	ctx := newDiffContext("eth_getTransactionByHash", []ComparisonRule{{Path: "result.v", Kind: RuleHexNormalize}})
+	diffs, _ := compareJSONRPCResponses(ctx,
+		resultResp(map[string]interface{}{"v": "0x01", "hash": "0xAB"}),
+		resultResp(map[string]interface{}{"v": "0x1", "hash": "0xab"}))
+	entries, _ := diffs["result_differences"].([]DiffEntry)
+	if len(entries) != 1 || entries[0].Path != "result.hash" {
+		t.Errorf("only the unnormalized path should differ, got %v", diffs)
+	}
+}
+
+func TestNullEqualsMissing(t *testing.T) {
+	ctx := newDiffContext("eth_getTransactionReceipt", []ComparisonRule{{Kind: RuleNullEqualsMissing}})
+	diffs, _ := compareJSONRPCResponses(ctx,
+		resultResp(map[string]interface{}{"contractAddress": nil, "status": "0x1"}),
+		resultResp(map[string]interface{}{"status": "0x1"}))
+	if hasResultDiff(diffs) {
+		t.Errorf("null and missing should be equal, got %v", diffs)
+	}
+	diffs, _ = compareJSONRPCResponses(ctx,
+		resultResp(map[string]interface{}{"contractAddress": "0x1", "status": "0x1"}),
+		resultResp(map[string]interface{}{"status": "0x1"}))
+	if !hasResultDiff(diffs) {
+		t.Error("a non-null value against a missing field should still differ")
+	}
+}
+
+func TestOnlyRule(t *testing.T) {
+	ctx := newDiffContext("eth_getBlockByNumber", []ComparisonRule{
+		{Method: "eth_getBlockByNumber", Path: "result.transactions[*].hash", Kind: RuleOnly},
+		{Method: "eth_getBlockByNumber", Path: "result.stateRoot", Kind: RuleOnly},
+	})
+	block := func(stateRoot, hash, extra string) map[string]interface{} {
+		return resultResp(map[string]interface{}{
+			"stateRoot":    stateRoot,
+			"extraData":    extra,
+			"transactions": []interface{}{map[string]interface{}{"hash": hash, "v": extra}},
+		})
+	}
+	diffs, _ := compareJSONRPCResponses(ctx, block("0x1", "0xa", "0x00"), block("0x1", "0xa", "0xff"))
+	if hasResultDiff(diffs) {
+		t.Errorf("unselected paths should be skipped, got %v", diffs)
+	}
+	diffs, _ = compareJSONRPCResponses(ctx, block("0x1", "0xa", "0x00"), block("0x1", "0xb", "0x00"))
+	entries, _ := diffs["result_differences"].([]DiffEntry)
+	if len(entries) != 1 || entries[0].Path != "result.transactions[0].hash" {
+		t.Errorf("selected path should be compared, got %v", diffs)
+	}
+}
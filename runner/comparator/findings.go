@@ -0,0 +1,217 @@
+package comparator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// maxFindingExamples bounds the example calls kept per finding
+const maxFindingExamples = 3
+
+// Finding groups the differences of many calls that share a root cause: the
+// same normalized path, diff type and client pair. Array indices are
+// wildcarded ("result.transactions[*].yParity") and method variants are
+// grouped under their JSON-RPC method, so one client omitting a field on
+// every transaction of every block is one finding rather than thousands of
+// differences.
+type Finding struct {
+	Signature string `json:"signature"`
+	DiffType  string `json:"diff_type"`
+	Reference string `json:"reference"`
+	Client    string `json:"client"`
+	// Calls counts the calls showing the difference; Occurrences counts every
+	// matching diff entry, e.g. once per transaction
+	Calls       int              `json:"calls"`
+	Occurrences int              `json:"occurrences"`
+	Methods     []string         `json:"methods"`
+	Examples    []FindingExample `json:"examples"`
+}
+
+// FindingExample is one call that shows a finding
+type FindingExample struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+	Path   string        `json:"path"`
+	Value1 interface{}   `json:"value1"`
+	Value2 interface{}   `json:"value2"`
+}
+
+// arrayIndexPattern matches a concrete array index in a diff path
+var arrayIndexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// normalizeDiffPath wildcards the array indices of a diff path, the same
+// "[*]" form comparison rules use
+func normalizeDiffPath(path string) string {
+	return arrayIndexPattern.ReplaceAllString(path, "[*]")
+}
+
+// Findings clusters the differences of the completed run, ranked by the
+// number of calls each one affects. A results stream that cannot be read back
+// is logged and clustered as far as it goes.
+func (c *Comparator) Findings() []Finding {
+	return c.runTallies().findings
+}
+
+// clusterFindings groups every difference of results into findings
+func clusterFindings(results []ComparisonResult, cfg *ComparisonConfig) []Finding {
+	fc := newFindingClusterer(cfg)
+	for _, r := range results {
+		fc.add(r)
+	}
+	return fc.findings()
+}
+
+type findingKey struct {
+	signature, diffType, reference, client string
+}
+
+// findingClusterer accumulates findings one result at a time, so a streamed
+// run is clustered without holding its results
+type findingClusterer struct {
+	cfg     *ComparisonConfig
+	byKey   map[findingKey]*Finding
+	methods map[findingKey]map[string]bool
+}
+
+func newFindingClusterer(cfg *ComparisonConfig) *findingClusterer {
+	return &findingClusterer{cfg: cfg, byKey: make(map[findingKey]*Finding), methods: make(map[findingKey]map[string]bool)}
+}
+
+// add clusters the differences of one call. The reference of a call is its
+// first client (in config order) that answered, matching CompareResponses.
+func (fc *findingClusterer) add(r ComparisonResult) {
+	if !r.hasDifferences() {
+		return
+	}
+	reference := referenceClient(r, fc.cfg)
+	rpcMethod := fc.cfg.rpcMethodName(r.Method)
+
+	for client, diff := range r.Differences {
+		seen := make(map[findingKey]bool)
+		for _, entry := range flattenDifferences(diff) {
+			key := findingKey{normalizeDiffPath(entry.Path), string(entry.Type), reference, client}
+			f, ok := fc.byKey[key]
+			if !ok {
+				f = &Finding{Signature: key.signature, DiffType: key.diffType, Reference: reference, Client: client}
+				fc.byKey[key] = f
+				fc.methods[key] = make(map[string]bool)
+			}
+			f.Occurrences++
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			f.Calls++
+			fc.methods[key][rpcMethod] = true
+			if len(f.Examples) < maxFindingExamples {
+				f.Examples = append(f.Examples, FindingExample{
+					Method: r.Method,
+					Params: r.Params,
+					Path:   entry.Path,
+					Value1: entry.Value1,
+					Value2: entry.Value2,
+				})
+			}
+		}
+	}
+}
+
+// findings returns the clustered findings, ranked
+func (fc *findingClusterer) findings() []Finding {
+	findings := make([]Finding, 0, len(fc.byKey))
+	for key, f := range fc.byKey {
+		for method := range fc.methods[key] {
+			f.Methods = append(f.Methods, method)
+		}
+		sort.Strings(f.Methods)
+		findings = append(findings, *f)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		if a.Occurrences != b.Occurrences {
+			return a.Occurrences > b.Occurrences
+		}
+		if a.Signature != b.Signature {
+			return a.Signature < b.Signature
+		}
+		if a.DiffType != b.DiffType {
+			return a.DiffType < b.DiffType
+		}
+		return a.Client < b.Client
+	})
+	return findings
+}
+
+// referenceClient returns the client the other responses of r were compared
+// against
+func referenceClient(r ComparisonResult, cfg *ComparisonConfig) string {
+	for _, client := range cfg.Clients {
+		if _, ok := r.Responses[client.Name]; ok {
+			return client.Name
+		}
+	}
+	return ""
+}
+
+// flattenDifferences turns one client's differences, as produced by
+// compareJSONRPCResponses, into diff entries. Presence mismatches become
+// entries on "result" or "error". Differences read back from JSON are
+// decoded into the same form.
+func flattenDifferences(diff interface{}) []DiffEntry {
+	fields, ok := diff.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var entries []DiffEntry
+	for _, presence := range []string{"result", "error"} {
+		p, ok := fields[presence+"_presence"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		entries = append(entries, DiffEntry{
+			Path:   presence,
+			Type:   DiffType(presence + "_presence"),
+			Value1: p["resp1"],
+			Value2: p["resp2"],
+		})
+	}
+	for _, field := range []string{"result_differences", "error_differences"} {
+		switch list := fields[field].(type) {
+		case []DiffEntry:
+			entries = append(entries, list...)
+		case nil:
+		default:
+			data, err := json.Marshal(list)
+			if err != nil {
+				continue
+			}
+			var decoded []DiffEntry
+			if json.Unmarshal(data, &decoded) == nil {
+				entries = append(entries, decoded...)
+			}
+		}
+	}
+	return entries
+}
+
+// SaveFindings writes the ranked findings to a JSON file
+func (c *Comparator) SaveFindings(filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c.Findings(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal findings: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write findings: %w", err)
+	}
+	return nil
+}
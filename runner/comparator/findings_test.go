@@ -0,0 +1,101 @@
+package comparator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// Differences at different array indices and in method variants cluster into
+// one finding, ranked above rarer ones.
+func TestFindingsClusterByNormalizedPath(t *testing.T) {
+	missing := func(path string) DiffEntry {
+		return DiffEntry{Path: path, Type: DiffTypeFieldMissing, Value1: "0x1"}
+	}
+	responses := map[string]interface{}{"geth": map[string]interface{}{}, "erigon": map[string]interface{}{}}
+	c := &Comparator{
+		config: &ComparisonConfig{
+			Clients:        []*types.ClientConfig{{Name: "geth"}, {Name: "erigon"}},
+			MethodRPCNames: map[string]string{"eth_getBlockByNumber_variant1": "eth_getBlockByNumber"},
+		},
+		results: []ComparisonResult{
+			{
+				Method:    "eth_getBlockByNumber",
+				Params:    []interface{}{"0x1", true},
+				Responses: responses,
+				Differences: map[string]interface{}{"erigon": map[string]interface{}{
+					"result_differences": []DiffEntry{
+						missing("result.transactions[0].yParity"),
+						missing("result.transactions[1].yParity"),
+					},
+				}},
+			},
+			{
+				Method:    "eth_getBlockByNumber_variant1",
+				Params:    []interface{}{"0x2", true},
+				Responses: responses,
+				Differences: map[string]interface{}{"erigon": map[string]interface{}{
+					"result_differences": []DiffEntry{missing("result.transactions[3].yParity")},
+				}},
+			},
+			{
+				Method:    "eth_getTransactionByHash",
+				Responses: responses,
+				Differences: map[string]interface{}{"erigon": map[string]interface{}{
+					"result_differences": []DiffEntry{missing("result.yParity")},
+					"error_presence":     map[string]interface{}{"type": "inconsistent", "resp1": false, "resp2": true},
+				}},
+			},
+			{Method: "eth_chainId", Responses: responses},
+		},
+	}
+
+	findings := c.Findings()
+	if len(findings) != 3 {
+		t.Fatalf("want 3 findings, got %d: %+v", len(findings), findings)
+	}
+	top := findings[0]
+	if top.Signature != "result.transactions[*].yParity" || top.DiffType != string(DiffTypeFieldMissing) {
+		t.Errorf("top finding = %s %s", top.DiffType, top.Signature)
+	}
+	if top.Calls != 2 || top.Occurrences != 3 {
+		t.Errorf("calls/occurrences = %d/%d, want 2/3", top.Calls, top.Occurrences)
+	}
+	if top.Reference != "geth" || top.Client != "erigon" {
+		t.Errorf("client pair = %s vs %s", top.Reference, top.Client)
+	}
+	if !reflect.DeepEqual(top.Methods, []string{"eth_getBlockByNumber"}) {
+		t.Errorf("methods = %v, want variants grouped", top.Methods)
+	}
+	if len(top.Examples) != 2 || top.Examples[1].Method != "eth_getBlockByNumber_variant1" {
+		t.Errorf("examples = %+v", top.Examples)
+	}
+
+	var presence bool
+	for _, f := range findings[1:] {
+		if f.DiffType == "error_presence" && f.Signature == "error" {
+			presence = true
+		}
+	}
+	if !presence {
+		t.Errorf("error presence mismatch should be a finding: %+v", findings[1:])
+	}
+}
+
+// Differences read back from a results file cluster like in-memory ones.
+func TestFindingsFromDecodedResults(t *testing.T) {
+	data := []byte(`[{"method":"eth_getBalance","responses":{"a":{},"b":{}},
+		"differences":{"b":{"result_differences":[{"path":"result","type":"value_mismatch","value1":"0x1","value2":"0x2"}]}}}]`)
+	var results []ComparisonResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ComparisonConfig{Clients: []*types.ClientConfig{{Name: "a"}, {Name: "b"}}}
+
+	findings := clusterFindings(results, cfg)
+	if len(findings) != 1 || findings[0].Signature != "result" || findings[0].Examples[0].Value2 != "0x2" {
+		t.Errorf("findings = %+v", findings)
+	}
+}
@@ -0,0 +1,153 @@
+package comparator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// GoldenClientName is the name golden snapshots are compared under when a
+// run is checked --against-golden; it is the reference of every call
+const GoldenClientName = "golden"
+
+// GoldenSnapshot is a validated response stored for a method and params
+type GoldenSnapshot struct {
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+	// Block is the block override the response was recorded at; a snapshot is
+	// only compared in runs pinned to the same block
+	Block      string                 `json:"block,omitempty"`
+	Client     string                 `json:"client"`
+	RecordedAt time.Time              `json:"recorded_at"`
+	Response   map[string]interface{} `json:"response"`
+}
+
+// goldenKey is the SHA-256 hex digest of a call's method and params. Params
+// are hashed as JSON, which orders object keys, so equal calls share a key.
+func goldenKey(rpcMethod string, params []interface{}) (string, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+	data, err := json.Marshal([]interface{}{rpcMethod, params})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// goldenPath is where the snapshot of a call lives under dir
+func goldenPath(dir, rpcMethod string, params []interface{}) (string, error) {
+	key, err := goldenKey(rpcMethod, params)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, rpcMethod, key+".json"), nil
+}
+
+// canonicalResponse drops the envelope fields that vary between requests,
+// keeping result or error
+func canonicalResponse(resp map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(resp))
+	for k, v := range resp {
+		if k == "id" || k == "jsonrpc" {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// goldenClient is the stand-in client that answers from the snapshots
+func goldenClient(dir string) *types.ClientConfig {
+	return &types.ClientConfig{Name: GoldenClientName, URL: "golden://" + dir}
+}
+
+// isGolden reports whether client answers from golden snapshots
+func (c *Comparator) isGolden(client *types.ClientConfig) bool {
+	return c.config.AgainstGolden != "" && client.Name == GoldenClientName
+}
+
+// liveClients returns the clients that are called over JSON-RPC
+func (c *Comparator) liveClients() []*types.ClientConfig {
+	live := make([]*types.ClientConfig, 0, len(c.config.Clients))
+	for _, client := range c.config.Clients {
+		if !c.isGolden(client) {
+			live = append(live, client)
+		}
+	}
+	return live
+}
+
+// goldenResponse returns the recorded response of a call. A missing
+// snapshot, or one pinned to another block, is reported like a transport
+// error of the golden client.
+func (c *Comparator) goldenResponse(rpcMethod string, params []interface{}) (map[string]interface{}, error) {
+	path, err := goldenPath(c.config.AgainstGolden, rpcMethod, params)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no golden snapshot for this call")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden snapshot: %w", err)
+	}
+	var snapshot GoldenSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse golden snapshot %s: %w", path, err)
+	}
+	if snapshot.Block != c.config.BlockOverride {
+		return nil, fmt.Errorf("golden snapshot is pinned to block %q, this run to %q", snapshot.Block, c.config.BlockOverride)
+	}
+	return snapshot.Response, nil
+}
+
+// RecordGolden stores the reference response of every call under dir, keyed
+// by method and params hash, and returns the number of snapshots written.
+// Calls the reference could not answer are skipped.
+func (c *Comparator) RecordGolden(dir string) (int, error) {
+	recordedAt := time.Now()
+	written := 0
+	err := c.eachResult(func(r *ComparisonResult) error {
+		client := referenceClient(*r, c.config)
+		resp, ok := r.Responses[client].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		rpcMethod := c.config.rpcMethodName(r.Method)
+		snapshot := GoldenSnapshot{
+			Method:     rpcMethod,
+			Params:     r.Params,
+			Block:      c.config.BlockOverride,
+			Client:     client,
+			RecordedAt: recordedAt,
+			Response:   canonicalResponse(resp),
+		}
+		path, err := goldenPath(dir, rpcMethod, r.Params)
+		if err != nil {
+			return fmt.Errorf("failed to key %s: %w", r.Method, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create golden directory: %w", err)
+		}
+		data, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal golden snapshot: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write golden snapshot: %w", err)
+		}
+		written++
+		return nil
+	})
+	return written, err
+}
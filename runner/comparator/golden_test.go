@@ -0,0 +1,77 @@
+package comparator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// Responses recorded from one build are the reference when a single client
+// is later compared against them.
+func TestGoldenRecordAndCompare(t *testing.T) {
+	balance := func(value string) func(rpcRequest) interface{} {
+		return func(req rpcRequest) interface{} { return value }
+	}
+	golden := t.TempDir()
+	params := []interface{}{"0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045", "latest"}
+	cfg := func(url string) *ComparisonConfig {
+		return &ComparisonConfig{
+			Methods:          []string{"eth_getBalance_vitalik"},
+			MethodRPCNames:   map[string]string{"eth_getBalance_vitalik": "eth_getBalance"},
+			CustomParameters: map[string][]interface{}{"eth_getBalance_vitalik": params},
+			Clients:          []*types.ClientConfig{{Name: "geth", URL: url}},
+			BlockOverride:    "0x10",
+			OutputDir:        t.TempDir(),
+		}
+	}
+
+	recorder, err := NewComparator(cfg(newRPCFake(t, "0x1", balance("0xabc")).URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recorder.Run(); err != nil {
+		t.Fatal(err)
+	}
+	written, err := recorder.RecordGolden(golden)
+	if err != nil || written != 1 {
+		t.Fatalf("RecordGolden = %d, %v", written, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(golden, "eth_getBalance", "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("want one snapshot under the method directory, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), `"jsonrpc"`) || !strings.Contains(string(data), `"block": "0x10"`) {
+		t.Errorf("snapshot should drop the envelope and carry the block:\n%s", data)
+	}
+
+	check := cfg(newRPCFake(t, "0x1", balance("0xdef")).URL)
+	check.AgainstGolden = golden
+	comp, err := NewComparator(check)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results, err := comp.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Differences["geth"] == nil {
+		t.Fatalf("the new build should differ from the snapshot: %+v", results)
+	}
+	if findings := comp.Findings(); len(findings) != 1 || findings[0].Reference != GoldenClientName {
+		t.Errorf("golden should be the reference: %+v", findings)
+	}
+
+	// A run pinned to another block does not use the snapshot
+	check = cfg(newRPCFake(t, "0x1", balance("0xabc")).URL)
+	check.AgainstGolden = golden
+	check.BlockOverride = "0x20"
+	comp, _ = NewComparator(check)
+	results, _ = comp.Run()
+	if len(results) != 1 || !strings.Contains(results[0].TransportErrors[GoldenClientName], "pinned to block") {
+		t.Errorf("want a block mismatch, got %+v", results)
+	}
+}
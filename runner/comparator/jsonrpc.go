@@ -7,6 +7,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -34,11 +35,18 @@ type JSONRPCError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// formatCurlCommand formats a JSON-RPC request as a curl command for logging purposes
-func formatCurlCommand(url string, requestJSON []byte) string {
-	// Use double quotes for JSON payload to avoid shell escaping issues
-	return fmt.Sprintf("curl -X POST -H 'Content-Type: application/json' -d %q %s",
-		string(requestJSON), url)
+// FormatCurlCommand formats a JSON-RPC request as a curl command for logging
+// purposes and for reproduction scripts
+func FormatCurlCommand(url string, requestJSON []byte) string {
+	return fmt.Sprintf("curl -X POST -H 'Content-Type: application/json' -d %s %s",
+		shellQuote(string(requestJSON)), url)
+}
+
+// shellQuote single-quotes s for a POSIX shell, where nothing inside single
+// quotes is expanded. Each single quote in s closes the quoting, is written
+// escaped and reopens it.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 // makeJSONRPCCall makes a JSON-RPC call to the specified endpoint, retrying
@@ -46,6 +54,14 @@ func formatCurlCommand(url string, requestJSON []byte) string {
 // maxAttempts. A 200 response carrying a JSON-RPC error object is returned as a
 // valid response (not retried); a 4xx is a hard failure (not retried).
 func makeJSONRPCCall(url, method string, params []interface{}, timeoutSeconds int, verbose bool, maxAttempts int, baseDelay time.Duration) (map[string]interface{}, error) {
+	response, _, err := timedJSONRPCCall(url, method, params, timeoutSeconds, verbose, maxAttempts, baseDelay)
+	return response, err
+}
+
+// timedJSONRPCCall is makeJSONRPCCall that also returns the latency and body
+// size of the attempt that succeeded; backoff and failed attempts are not
+// counted.
+func timedJSONRPCCall(url, method string, params []interface{}, timeoutSeconds int, verbose bool, maxAttempts int, baseDelay time.Duration) (map[string]interface{}, ClientTiming, error) {
 	// Create request
 	request := JSONRPCRequest{
 		JSONRPC: "2.0",
@@ -57,12 +73,12 @@ func makeJSONRPCCall(url, method string, params []interface{}, timeoutSeconds in
 	// Marshal request to JSON
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, ClientTiming{}, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Log the equivalent curl command if verbose mode is enabled
 	if verbose {
-		log.Printf("JSON-RPC Request to %s: %s", url, formatCurlCommand(url, requestJSON))
+		log.Printf("JSON-RPC Request to %s: %s", url, FormatCurlCommand(url, requestJSON))
 	}
 
 	// Create HTTP client with timeout
@@ -82,10 +98,11 @@ func makeJSONRPCCall(url, method string, params []interface{}, timeoutSeconds in
 
 		req, err := http.NewRequest("POST", url, bytes.NewBuffer(requestJSON))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+			return nil, ClientTiming{}, fmt.Errorf("failed to create HTTP request: %w", err)
 		}
 		req.Header.Set("Content-Type", "application/json")
 
+		start := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("HTTP request failed: %w", err)
@@ -94,6 +111,7 @@ func makeJSONRPCCall(url, method string, params []interface{}, timeoutSeconds in
 
 		body, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
+		latency := time.Since(start)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to read response body: %w", err)
 			continue
@@ -104,17 +122,17 @@ func makeJSONRPCCall(url, method string, params []interface{}, timeoutSeconds in
 			if resp.StatusCode >= 500 {
 				continue
 			}
-			return nil, lastErr
+			return nil, ClientTiming{}, lastErr
 		}
 
 		var rawResponse map[string]interface{}
 		if err := json.Unmarshal(body, &rawResponse); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
+			return nil, ClientTiming{}, fmt.Errorf("failed to parse response: %w", err)
 		}
-		return rawResponse, nil
+		return rawResponse, ClientTiming{LatencyMs: float64(latency.Microseconds()) / 1000, ResponseBytes: len(body)}, nil
 	}
 
-	return nil, lastErr
+	return nil, ClientTiming{}, lastErr
 }
 
 // backoffDelay returns the exponential backoff for a given (1-based) retry
@@ -137,7 +155,7 @@ func makeBatchJSONRPCCall(url string, requests []JSONRPCRequest, timeoutSeconds
 
 	// Log the equivalent curl command for batch request if verbose mode is enabled
 	if verbose {
-		log.Printf("Batch JSON-RPC Request to %s: %s", url, formatCurlCommand(url, requestJSON))
+		log.Printf("Batch JSON-RPC Request to %s: %s", url, FormatCurlCommand(url, requestJSON))
 	}
 
 	// Create HTTP client with timeout
@@ -0,0 +1,99 @@
+package comparator
+
+import (
+	"sort"
+)
+
+// DefaultSlowFactor is how many times slower than the other clients a client
+// must answer a call to be flagged as slow
+const DefaultSlowFactor = 5.0
+
+// minSlowLatencyMs keeps calls answered faster than this from being flagged,
+// so scheduling noise on fast calls is not reported as slowness
+const minSlowLatencyMs = 50.0
+
+// ClientTiming is how long a client took to answer a call and how large its
+// response body was. Cached answers came from the response cache and carry
+// no latency.
+type ClientTiming struct {
+	LatencyMs     float64 `json:"latency_ms"`
+	ResponseBytes int     `json:"response_bytes"`
+	Cached        bool    `json:"cached,omitempty"`
+}
+
+// SlowCall is a call one client answered more than SlowFactor times slower
+// than the median of the other clients
+type SlowCall struct {
+	Method    string  `json:"method"`
+	Client    string  `json:"client"`
+	LatencyMs float64 `json:"latency_ms"`
+	OthersMs  float64 `json:"others_ms"`
+	Factor    float64 `json:"factor"`
+}
+
+// slowClients returns, sorted, the clients whose latency exceeds factor times
+// the median latency of the other clients that answered. Cached answers are
+// neither flagged nor compared against.
+func slowClients(timings map[string]ClientTiming, factor float64) []string {
+	if len(timings) < 2 || factor <= 0 {
+		return nil
+	}
+	var slow []string
+	for client, timing := range timings {
+		if timing.Cached || timing.LatencyMs < minSlowLatencyMs {
+			continue
+		}
+		if others := othersMedian(timings, client); others > 0 && timing.LatencyMs > factor*others {
+			slow = append(slow, client)
+		}
+	}
+	sort.Strings(slow)
+	return slow
+}
+
+// othersMedian is the median latency of every client but client, leaving out
+// cached answers
+func othersMedian(timings map[string]ClientTiming, client string) float64 {
+	latencies := make([]float64, 0, len(timings)-1)
+	for name, timing := range timings {
+		if name != client && !timing.Cached {
+			latencies = append(latencies, timing.LatencyMs)
+		}
+	}
+	if len(latencies) == 0 {
+		return 0
+	}
+	sort.Float64s(latencies)
+	mid := len(latencies) / 2
+	if len(latencies)%2 == 0 {
+		return (latencies[mid-1] + latencies[mid]) / 2
+	}
+	return latencies[mid]
+}
+
+// SlowCalls returns every slow client answer of the run, slowest relative to
+// the other clients first
+func (c *Comparator) SlowCalls() ([]SlowCall, error) {
+	tallies := c.runTallies()
+	return tallies.slow, tallies.err
+}
+
+// slowCallsOf returns the slow client answers of one call
+func slowCallsOf(r *ComparisonResult) []SlowCall {
+	var calls []SlowCall
+	for _, client := range r.Slow {
+		timing := r.Timings[client]
+		others := othersMedian(r.Timings, client)
+		if others <= 0 {
+			continue
+		}
+		calls = append(calls, SlowCall{
+			Method:    r.Method,
+			Client:    client,
+			LatencyMs: timing.LatencyMs,
+			OthersMs:  others,
+			Factor:    timing.LatencyMs / others,
+		})
+	}
+	return calls
+}
@@ -0,0 +1,66 @@
+package comparator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// A client is slow against the median of the others, and fast calls are
+// never flagged however large the ratio.
+func TestSlowClients(t *testing.T) {
+	timings := map[string]ClientTiming{
+		"geth":       {LatencyMs: 100},
+		"nethermind": {LatencyMs: 120},
+		"reth":       {LatencyMs: 90},
+		"erigon":     {LatencyMs: 900},
+	}
+	if got := slowClients(timings, 5); !reflect.DeepEqual(got, []string{"erigon"}) {
+		t.Errorf("slow = %v, want [erigon]", got)
+	}
+	if got := slowClients(timings, 10); got != nil {
+		t.Errorf("slow at 10x = %v, want none", got)
+	}
+
+	fast := map[string]ClientTiming{"geth": {LatencyMs: 0.5}, "reth": {LatencyMs: 40}}
+	if got := slowClients(fast, 5); got != nil {
+		t.Errorf("calls under %.0fms should not be flagged, got %v", minSlowLatencyMs, got)
+	}
+}
+
+// CompareResponses records each client's latency and body size and flags the
+// slow one in the result and the summary.
+func TestCompareResponsesRecordsTimings(t *testing.T) {
+	fast := newRPCFake(t, "0x1", func(rpcRequest) interface{} { return "0x10" })
+	slow := newRPCFake(t, "0x1", func(rpcRequest) interface{} {
+		time.Sleep(80 * time.Millisecond)
+		return "0x10"
+	})
+	c, err := NewComparator(&ComparisonConfig{
+		Clients:    []*types.ClientConfig{{Name: "geth", URL: fast.URL}, {Name: "reth", URL: slow.URL}},
+		OutputDir:  t.TempDir(),
+		SlowFactor: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := c.CompareResponses("eth_blockNumber", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Timings) != 2 || result.Timings["geth"].ResponseBytes == 0 {
+		t.Fatalf("timings = %+v", result.Timings)
+	}
+	if result.Timings["reth"].LatencyMs < 80 {
+		t.Errorf("reth latency = %.1fms, want at least 80ms", result.Timings["reth"].LatencyMs)
+	}
+	if !reflect.DeepEqual(result.Slow, []string{"reth"}) {
+		t.Errorf("slow = %v, want [reth]", result.Slow)
+	}
+	if s := c.Summarize(); s.Slow["reth"] != 1 || len(s.Slow) != 1 {
+		t.Errorf("summary slow = %v", s.Slow)
+	}
+}
@@ -0,0 +1,242 @@
+package comparator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jsonrpc-bench/runner/config"
+)
+
+// Ledger statuses of a differing call
+const (
+	// LedgerKnown means every difference of the call matches a live ledger entry
+	LedgerKnown = "known"
+	// LedgerChanged means a ledger entry matched but the client's value is no
+	// longer the one it records
+	LedgerChanged = "changed"
+	// LedgerNew means at least one difference is not in the ledger
+	LedgerNew = "new"
+)
+
+// ledgerDateLayout is the layout of a ledger entry's expiry date
+const ledgerDateLayout = "2006-01-02"
+
+// LedgerEntry is one accepted difference, fingerprinted by method, path, diff
+// type and client. Unlike an ignore rule it only covers that exact finding,
+// and it expires.
+type LedgerEntry struct {
+	Method string   `json:"method" yaml:"method"`
+	Path   string   `json:"path" yaml:"path"`
+	Type   DiffType `json:"type" yaml:"type"`
+	Client string   `json:"client" yaml:"client"`
+
+	Owner   string `json:"owner" yaml:"owner"`
+	Link    string `json:"link" yaml:"link"`
+	Expires string `json:"expires" yaml:"expires"`
+	Note    string `json:"note,omitempty" yaml:"note,omitempty"`
+	// Value, when set, pins the client's accepted value; a difference with
+	// another value is reported as changed
+	Value interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+
+	expires time.Time
+}
+
+// Fingerprint identifies the finding the entry accepts
+func (e LedgerEntry) Fingerprint() string {
+	return ledgerFingerprint(e.Method, normalizeDiffPath(e.Path), string(e.Type), e.Client)
+}
+
+// expired reports whether the entry's expiry date has passed at now. An entry
+// is valid through the whole of its expiry day.
+func (e LedgerEntry) expired(now time.Time) bool {
+	return !now.Before(e.expires.AddDate(0, 0, 1))
+}
+
+func ledgerFingerprint(method, signature, diffType, client string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{method, signature, diffType, client}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Ledger is a known-differences file
+type Ledger struct {
+	Entries []LedgerEntry `json:"known_differences" yaml:"known_differences"`
+}
+
+// LoadLedger reads and validates a known-differences ledger
+func LoadLedger(path string) (*Ledger, error) {
+	safePath, err := config.SafeReadPath(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(safePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+	var ledger Ledger
+	if err := yaml.Unmarshal(data, &ledger); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger: %w", err)
+	}
+
+	seen := make(map[string]int, len(ledger.Entries))
+	for i := range ledger.Entries {
+		e := &ledger.Entries[i]
+		if e.Method == "" || e.Path == "" || e.Type == "" || e.Client == "" {
+			return nil, fmt.Errorf("ledger: known_differences[%d]: method, path, type and client are required", i)
+		}
+		if e.Owner == "" || e.Link == "" || e.Expires == "" {
+			return nil, fmt.Errorf("ledger: known_differences[%d]: owner, link and expires are required", i)
+		}
+		e.expires, err = time.ParseInLocation(ledgerDateLayout, e.Expires, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("ledger: known_differences[%d]: expires %q is not a YYYY-MM-DD date", i, e.Expires)
+		}
+		fp := e.Fingerprint()
+		if j, dup := seen[fp]; dup {
+			return nil, fmt.Errorf("ledger: known_differences[%d] duplicates known_differences[%d]", i, j)
+		}
+		seen[fp] = i
+	}
+	return &ledger, nil
+}
+
+// LedgerReport is the outcome of checking a run against a ledger. Call counts
+// cover differing calls only.
+type LedgerReport struct {
+	Known   int `json:"known"`
+	Changed int `json:"changed"`
+	New     int `json:"new"`
+	// Expired entries no longer accept their finding
+	Expired []LedgerEntry `json:"expired,omitempty"`
+	// Stale entries are live but matched no difference in this run
+	Stale []LedgerEntry `json:"stale,omitempty"`
+}
+
+// ledgerState is the applied ledger: its live entries by fingerprint and the
+// ones a difference of the run matched
+type ledgerState struct {
+	live    map[string]*LedgerEntry
+	matched map[string]bool
+}
+
+// ApplyLedger marks every differing call as known, changed or new against the
+// ledger's live entries. Known calls no longer count as real or environment
+// differences for --fail-on-diff and --fail-on-env-diff.
+func (c *Comparator) ApplyLedger(ledger *Ledger, now time.Time) (*LedgerReport, error) {
+	report := &LedgerReport{}
+	state := &ledgerState{live: make(map[string]*LedgerEntry, len(ledger.Entries)), matched: make(map[string]bool)}
+	for i := range ledger.Entries {
+		e := &ledger.Entries[i]
+		if e.expired(now) {
+			report.Expired = append(report.Expired, *e)
+			continue
+		}
+		state.live[e.Fingerprint()] = e
+	}
+
+	c.ledger = state
+	c.mutex.Lock()
+	c.tallies = nil
+	c.mutex.Unlock()
+	err := c.eachResult(func(r *ComparisonResult) error {
+		if !r.hasDifferences() {
+			return nil
+		}
+		switch r.Ledger {
+		case LedgerKnown:
+			report.Known++
+		case LedgerChanged:
+			report.Changed++
+		default:
+			report.New++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for fp, e := range state.live {
+		if !state.matched[fp] {
+			report.Stale = append(report.Stale, *e)
+		}
+	}
+	sort.Slice(report.Stale, func(i, j int) bool { return report.Stale[i].Fingerprint() < report.Stale[j].Fingerprint() })
+	c.ledgerReport = report
+	return report, nil
+}
+
+// classify sets the ledger status of a differing result when a ledger is
+// applied
+func (c *Comparator) classify(r *ComparisonResult) {
+	if c.ledger == nil || !r.hasDifferences() {
+		return
+	}
+	r.Ledger = classifyAgainstLedger(*r, c.config, c.ledger.live, c.ledger.matched)
+}
+
+// classifyAgainstLedger returns the ledger status of a differing call and
+// records the entries it matched. Every difference is walked, even after an
+// unmatched one, so an entry is marked matched whatever order the clients'
+// differences are visited in.
+func classifyAgainstLedger(r ComparisonResult, cfg *ComparisonConfig, live map[string]*LedgerEntry, matched map[string]bool) string {
+	rpcMethod := cfg.rpcMethodName(r.Method)
+	unmatched, changed := false, false
+	for client, diff := range r.Differences {
+		for _, entry := range flattenDifferences(diff) {
+			fp := ledgerFingerprint(rpcMethod, normalizeDiffPath(entry.Path), string(entry.Type), client)
+			e, ok := live[fp]
+			if !ok {
+				unmatched = true
+				continue
+			}
+			matched[fp] = true
+			if e.Value != nil && !sameJSON(e.Value, entry.Value2) {
+				changed = true
+			}
+		}
+	}
+	switch {
+	case unmatched:
+		return LedgerNew
+	case changed:
+		return LedgerChanged
+	}
+	return LedgerKnown
+}
+
+// sameJSON reports whether two values encode to the same JSON, so YAML- and
+// JSON-decoded values compare equal
+func sameJSON(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// LedgerReport returns the report of the last ApplyLedger, or nil
+func (c *Comparator) LedgerReport() *LedgerReport {
+	return c.ledgerReport
+}
+
+// SaveLedgerReport writes the ledger report to a JSON file
+func (c *Comparator) SaveLedgerReport(filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c.ledgerReport, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger report: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write ledger report: %w", err)
+	}
+	return nil
+}
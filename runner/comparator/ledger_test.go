@@ -0,0 +1,159 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// Calls whose differences all match live entries are known and stop tripping
+// --fail-on-diff; a pinned value that moved is changed; unmatched and expired
+// findings are new; live entries that matched nothing are stale.
+func TestApplyLedger(t *testing.T) {
+	ledger, err := LoadLedger(writeTempYAML(t, "ledger.yaml", `
+known_differences:
+  - method: eth_getBlockByNumber
+    path: result.transactions[*].yParity
+    type: field_missing
+    client: erigon
+    owner: alice
+    link: https://example.com/issues/1
+    expires: "2026-12-31"
+  - method: eth_getBlockByNumber
+    path: result.totalDifficulty
+    type: value_mismatch
+    client: erigon
+    owner: bob
+    link: https://example.com/issues/2
+    expires: "2026-12-31"
+    value: "0x0"
+  - method: eth_getBalance
+    path: result
+    type: value_mismatch
+    client: erigon
+    owner: carol
+    link: https://example.com/issues/3
+    expires: "2026-01-01"
+  - method: eth_getLogs
+    path: result
+    type: array_length_mismatch
+    client: erigon
+    owner: dave
+    link: https://example.com/issues/4
+    expires: "2026-12-31"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := func(entries ...DiffEntry) map[string]interface{} {
+		return map[string]interface{}{"erigon": map[string]interface{}{"result_differences": entries}}
+	}
+	responses := map[string]interface{}{"geth": map[string]interface{}{}, "erigon": map[string]interface{}{}}
+	c := &Comparator{
+		config: &ComparisonConfig{
+			Clients:        []*types.ClientConfig{{Name: "geth"}, {Name: "erigon"}},
+			MethodRPCNames: map[string]string{"eth_getBlockByNumber_variant1": "eth_getBlockByNumber"},
+		},
+		results: []ComparisonResult{
+			{Method: "eth_getBlockByNumber_variant1", Responses: responses, Differences: diff(
+				DiffEntry{Path: "result.transactions[4].yParity", Type: DiffTypeFieldMissing},
+				DiffEntry{Path: "result.totalDifficulty", Type: DiffTypeValueMismatch, Value1: "0x1", Value2: "0x0"},
+			)},
+			{Method: "eth_getBlockByNumber", Responses: responses, Differences: diff(
+				DiffEntry{Path: "result.totalDifficulty", Type: DiffTypeValueMismatch, Value1: "0x1", Value2: nil},
+			)},
+			{Method: "eth_getBalance", Responses: responses, Differences: diff(
+				DiffEntry{Path: "result", Type: DiffTypeValueMismatch, Value1: "0x1", Value2: "0x2"},
+			)},
+			{Method: "eth_chainId", Responses: responses},
+		},
+	}
+
+	report, err := c.ApplyLedger(ledger, time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{LedgerKnown, LedgerChanged, LedgerNew, ""}
+	for i, r := range c.results {
+		if r.Ledger != want[i] {
+			t.Errorf("results[%d] (%s) ledger = %q, want %q", i, r.Method, r.Ledger, want[i])
+		}
+	}
+	if report.Known != 1 || report.Changed != 1 || report.New != 1 {
+		t.Errorf("report counts = %d/%d/%d, want 1/1/1", report.Known, report.Changed, report.New)
+	}
+	if len(report.Expired) != 1 || report.Expired[0].Owner != "carol" {
+		t.Errorf("expired = %+v", report.Expired)
+	}
+	if len(report.Stale) != 1 || report.Stale[0].Owner != "dave" {
+		t.Errorf("stale = %+v", report.Stale)
+	}
+
+	if s := c.Summarize(); s.Known != 1 || s.Differ != 2 {
+		t.Errorf("summary known/differ = %d/%d, want 1/2", s.Known, s.Differ)
+	}
+	c.results = c.results[:1]
+	if c.HasRealDifferences() {
+		t.Error("known differences must not count as real differences")
+	}
+}
+
+// A known difference sharing a call with a new one is still matched, so its
+// entry is never reported stale, whatever order the clients are visited in.
+func TestApplyLedgerMatchesAlongsideNewDifference(t *testing.T) {
+	ledger, err := LoadLedger(writeTempYAML(t, "ledger.yaml", `
+known_differences:
+  - method: eth_getBlockByNumber
+    path: result.totalDifficulty
+    type: field_missing
+    client: erigon
+    owner: alice
+    link: https://example.com/issues/1
+    expires: "2026-12-31"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	responses := map[string]interface{}{"geth": map[string]interface{}{}, "erigon": map[string]interface{}{}, "reth": map[string]interface{}{}}
+	c := &Comparator{
+		config: &ComparisonConfig{Clients: []*types.ClientConfig{{Name: "geth"}, {Name: "erigon"}, {Name: "reth"}}},
+		results: []ComparisonResult{{Method: "eth_getBlockByNumber", Responses: responses, Differences: map[string]interface{}{
+			"erigon": map[string]interface{}{"result_differences": []DiffEntry{{Path: "result.totalDifficulty", Type: DiffTypeFieldMissing}}},
+			"reth":   map[string]interface{}{"result_differences": []DiffEntry{{Path: "result.baseFeePerGas", Type: DiffTypeValueMismatch, Value1: "0x1", Value2: "0x2"}}},
+		}}},
+	}
+
+	report, err := c.ApplyLedger(ledger, time.Date(2026, 6, 1, 12, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.results[0].Ledger != LedgerNew {
+		t.Errorf("ledger = %q, want %q", c.results[0].Ledger, LedgerNew)
+	}
+	if len(report.Stale) != 0 {
+		t.Errorf("stale = %+v, want none", report.Stale)
+	}
+}
+
+// Entries missing their fingerprint or accountability fields are rejected.
+func TestLoadLedgerValidation(t *testing.T) {
+	cases := map[string]string{
+		"required":   "known_differences:\n  - method: eth_call\n    path: result\n    type: value_mismatch\n    owner: a\n    link: l\n    expires: \"2026-01-01\"\n",
+		"owner":      "known_differences:\n  - method: eth_call\n    path: result\n    type: value_mismatch\n    client: b\n    link: l\n    expires: \"2026-01-01\"\n",
+		"YYYY-MM-DD": "known_differences:\n  - method: eth_call\n    path: result\n    type: value_mismatch\n    client: b\n    owner: a\n    link: l\n    expires: soon\n",
+		"duplicates": "known_differences:\n" +
+			"  - {method: eth_call, path: \"result[0]\", type: value_mismatch, client: b, owner: a, link: l, expires: \"2026-01-01\"}\n" +
+			"  - {method: eth_call, path: \"result[*]\", type: value_mismatch, client: b, owner: a, link: l, expires: \"2026-01-01\"}\n",
+	}
+	for want, content := range cases {
+		_, err := LoadLedger(writeTempYAML(t, "ledger.yaml", content))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("want error containing %q, got %v", want, err)
+		}
+	}
+}
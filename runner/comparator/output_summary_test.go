@@ -21,7 +21,10 @@ func TestResultsForOutputDiffOnlyTruncates(t *testing.T) {
 		},
 	}
 
-	out := c.resultsForOutput()
+	out, err := c.resultsForOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
 	if len(out) != 1 || out[0].Method != "diff" {
 		t.Fatalf("diff-only should keep only the differing call, got %v", out)
 	}
@@ -49,7 +52,10 @@ func TestResultsForOutputDiffOnlyKeepBodies(t *testing.T) {
 			{Method: "diff", Differences: map[string]interface{}{"n": 1}, Responses: map[string]interface{}{"nodeA": big}},
 		},
 	}
-	out := c.resultsForOutput()
+	out, err := c.resultsForOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
 	if out[0].Responses["nodeA"] != big {
 		t.Error("without a cap, diff-only should keep full bodies")
 	}
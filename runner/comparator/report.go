@@ -57,8 +57,24 @@ type ReportData struct {
 	Summary         ComparisonSummary                              `json:"summary"`
 	Scopes          []string                                       `json:"scopes"`
 	ScopedMethods   map[string]map[string][]MethodComparisonResult `json:"scoped_methods"`
+	Findings        []Finding                                      `json:"findings"`
+
+	// Disagreements is the client × method matrix of calls on which a client
+	// disagreed with the majority (see Summary.MajorityDisagreements)
+	Disagreements       map[string]map[string]int `json:"disagreements,omitempty"`
+	DisagreeingClients  []string                  `json:"disagreeing_clients,omitempty"`
+	DisagreementMethods []string                  `json:"disagreement_methods,omitempty"`
+
+	// SlowCalls are the slowest of the calls a client answered more than
+	// SlowFactor times slower than the others; SlowCallCount counts them all
+	SlowCalls     []SlowCall `json:"slow_calls,omitempty"`
+	SlowCallCount int        `json:"slow_call_count,omitempty"`
+	SlowFactor    float64    `json:"slow_factor,omitempty"`
 }
 
+// maxReportSlowCalls bounds the slow calls listed in the HTML report
+const maxReportSlowCalls = 50
+
 // formatJSON formats a JSON object for display
 func formatJSON(obj interface{}) (string, error) {
 	data, err := json.MarshalIndent(obj, "", "  ")
@@ -71,7 +87,10 @@ func formatJSON(obj interface{}) (string, error) {
 // GenerateHTMLReport generates an HTML report from the comparison results
 func (c *Comparator) GenerateHTMLReport(outputPath string) error {
 	// Honor the lean-output options so the report stays small.
-	results := c.resultsForOutput()
+	results, err := c.resultsForOutput()
+	if err != nil {
+		return err
+	}
 
 	// Convert comparison results to response diffs
 	responseDiffs := make([]types.ResponseDiff, len(results))
@@ -104,7 +123,8 @@ func (c *Comparator) GenerateHTMLReport(outputPath string) error {
 	}
 
 	benchmarkResult := &types.BenchmarkResult{
-		Config: cfg,
+		SchemaVersion: types.ResultSchemaVersion,
+		Config:        cfg,
 		ResponseDiff: map[string]interface{}{
 			"diffs": responseDiffs,
 		},
@@ -123,8 +143,32 @@ func (c *Comparator) GenerateHTMLReport(outputPath string) error {
 		return fmt.Errorf("failed to parse HTML template: %w", err)
 	}
 
+	data := reportData(benchmarkResult, responseDiffs, outputPath)
+	data.Findings = c.Findings()
+	data.Disagreements = c.Summarize().MajorityDisagreements
+	methods := make(map[string]bool)
+	for client, byMethod := range data.Disagreements {
+		data.DisagreeingClients = append(data.DisagreeingClients, client)
+		for method := range byMethod {
+			if !methods[method] {
+				methods[method] = true
+				data.DisagreementMethods = append(data.DisagreementMethods, method)
+			}
+		}
+	}
+	sort.Strings(data.DisagreeingClients)
+	sort.Strings(data.DisagreementMethods)
+
+	slow, err := c.SlowCalls()
+	if err != nil {
+		return err
+	}
+	data.SlowCallCount = len(slow)
+	data.SlowCalls = slow[:min(maxReportSlowCalls, len(slow))]
+	data.SlowFactor = c.config.SlowFactor
+
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, reportData(benchmarkResult, responseDiffs, outputPath)); err != nil {
+	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
@@ -615,6 +659,75 @@ const htmlReportTemplate = `<!DOCTYPE html>
         </div>
     </div>
 
+    {{if .Disagreements}}
+    <div class="config">
+        <h2>Disagreements with the Majority</h2>
+        <table class="diff-table">
+            <tr>
+                <th>Client</th>
+                {{range .DisagreementMethods}}<th>{{.}}</th>{{end}}
+            </tr>
+            {{range $client := .DisagreeingClients}}
+            <tr>
+                <td>{{$client}}</td>
+                {{range $method := $.DisagreementMethods}}<td>{{index $.Disagreements $client $method}}</td>{{end}}
+            </tr>
+            {{end}}
+        </table>
+    </div>
+    {{end}}
+
+    {{if .SlowCalls}}
+    <div class="config">
+        <h2>Slow Calls ({{.SlowCallCount}})</h2>
+        <p>Calls a client answered more than {{printf "%.0f" .SlowFactor}}x slower than the median of the other clients{{if gt .SlowCallCount (len .SlowCalls)}}; the {{len .SlowCalls}} slowest are listed{{end}}.</p>
+        <table class="diff-table">
+            <tr>
+                <th>Method</th>
+                <th>Client</th>
+                <th>Latency</th>
+                <th>Others (median)</th>
+                <th>Factor</th>
+            </tr>
+            {{range .SlowCalls}}
+            <tr>
+                <td>{{.Method}}</td>
+                <td>{{.Client}}</td>
+                <td>{{printf "%.1f" .LatencyMs}} ms</td>
+                <td>{{printf "%.1f" .OthersMs}} ms</td>
+                <td>{{printf "%.1f" .Factor}}x</td>
+            </tr>
+            {{end}}
+        </table>
+    </div>
+    {{end}}
+
+    {{if .Findings}}
+    <div class="config">
+        <h2>Findings ({{len .Findings}})</h2>
+        <table class="diff-table">
+            <tr>
+                <th>Calls</th>
+                <th>Signature</th>
+                <th>Type</th>
+                <th>Clients</th>
+                <th>Methods</th>
+                <th>Examples</th>
+            </tr>
+            {{range .Findings}}
+            <tr>
+                <td>{{.Calls}}{{if ne .Calls .Occurrences}} ({{.Occurrences}} entries){{end}}</td>
+                <td><code>{{.Signature}}</code></td>
+                <td>{{.DiffType}}</td>
+                <td>{{.Reference}} vs {{.Client}}</td>
+                <td>{{range $i, $m := .Methods}}{{if $i}}, {{end}}{{$m}}{{end}}</td>
+                <td>{{range .Examples}}<div><code>{{.Method}} {{.Path}}</code></div>{{end}}</td>
+            </tr>
+            {{end}}
+        </table>
+    </div>
+    {{end}}
+
     <div class="tabs">
         <button class="tab-button active" onclick="openTab('errors')">Errors ({{len .ErrorMethods}})</button>
         <button class="tab-button" onclick="openTab('differences')">Differences ({{len .DiffMethods}})</button>
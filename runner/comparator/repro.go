@@ -0,0 +1,330 @@
+package comparator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReproDir is the directory of a compare output that holds one repro bundle
+// per differing call
+const ReproDir = "repro"
+
+// reproFile is the bundle description a repro directory is replayed from
+const reproFile = "repro.json"
+
+// ReproClient is a client of a repro bundle. A golden client is replayed
+// from its recorded response. URL has its userinfo and query removed, which
+// Redacted records; replaying then needs the client's URL from clients.yaml.
+type ReproClient struct {
+	Name     string `json:"name"`
+	URL      string `json:"url,omitempty"`
+	Redacted bool   `json:"redacted,omitempty"`
+	Version  string `json:"version,omitempty"`
+	Golden   bool   `json:"golden,omitempty"`
+}
+
+// ReproBundle is a self-contained reproduction of one differing call: the
+// exact request sent, the clients it was sent to, the rules applied and the
+// differences found
+type ReproBundle struct {
+	Method          string                 `json:"method"`
+	Request         JSONRPCRequest         `json:"request"`
+	Block           string                 `json:"block,omitempty"`
+	Reference       string                 `json:"reference"`
+	Clients         []ReproClient          `json:"clients"`
+	Rules           []ComparisonRule       `json:"rules,omitempty"`
+	Differences     map[string]interface{} `json:"differences"`
+	TransportErrors map[string]string      `json:"transport_errors,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+}
+
+// ReproOutcome is the result of replaying a repro bundle
+type ReproOutcome struct {
+	Bundle          *ReproBundle
+	Differences     map[string]interface{}
+	TransportErrors map[string]string
+}
+
+// Diverges reports whether the replayed call still differs between clients
+func (o *ReproOutcome) Diverges() bool {
+	return len(o.Differences) > 0
+}
+
+// unsafeDirChars matches the characters not kept in a bundle directory name
+var unsafeDirChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// WriteReproBundles writes a repro bundle for every differing call under
+// dir, replacing the bundles of an earlier run, and returns how many were
+// written. versions maps client names to their web3_clientVersion.
+func (c *Comparator) WriteReproBundles(dir string, versions map[string]string) (int, error) {
+	if err := removeReproBundles(dir); err != nil {
+		return 0, fmt.Errorf("failed to clear earlier repro bundles: %w", err)
+	}
+	written := 0
+	used := make(map[string]bool)
+	err := c.eachResult(func(r *ComparisonResult) error {
+		if !r.hasDifferences() {
+			return nil
+		}
+		bundle := c.reproBundle(r, versions)
+		if err := writeReproBundle(filepath.Join(dir, reproDirName(r.Method, used)), bundle, r.Responses); err != nil {
+			return fmt.Errorf("failed to write repro bundle for %s: %w", r.Method, err)
+		}
+		written++
+		return nil
+	})
+	return written, err
+}
+
+// removeReproBundles removes the bundles an earlier run wrote to dir, the
+// directories holding a repro.json, and leaves anything else there alone
+func removeReproBundles(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		bundleDir := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(bundleDir, reproFile)); err != nil {
+			continue
+		}
+		if err := os.RemoveAll(bundleDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reproDirName names the bundle directory of a call. A method name that had
+// characters replaced gets a hash suffix, so that names such as "a/b" and
+// "a_b" don't share a directory, and a name already used gets a counter.
+func reproDirName(method string, used map[string]bool) string {
+	name := unsafeDirChars.ReplaceAllString(method, "_")
+	if name != method {
+		sum := sha256.Sum256([]byte(method))
+		name += "-" + hex.EncodeToString(sum[:4])
+	}
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s-%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// RedactURL removes the userinfo and query of a client URL, where API keys
+// are usually passed, and reports whether it removed anything
+func RedactURL(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		// Unparsable URLs are not written at all
+		return "", raw != ""
+	}
+	if u.User == nil && u.RawQuery == "" && u.Fragment == "" {
+		return raw, false
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String(), true
+}
+
+// reproBundle describes the call of r as it went over the wire
+func (c *Comparator) reproBundle(r *ComparisonResult, versions map[string]string) *ReproBundle {
+	rpcMethod := c.config.rpcMethodName(r.Method)
+	params := r.Params
+	if params == nil {
+		params = []interface{}{}
+	}
+	if c.config.BlockOverride != "" {
+		params = applyBlockOverride(rpcMethod, params, c.config.BlockOverride)
+	}
+
+	bundle := &ReproBundle{
+		Method:          r.Method,
+		Request:         JSONRPCRequest{JSONRPC: "2.0", Method: rpcMethod, Params: params, ID: 1},
+		Reference:       referenceClient(*r, c.config),
+		Differences:     r.Differences,
+		TransportErrors: r.TransportErrors,
+		CreatedAt:       time.Now(),
+	}
+	if block, ok := pinnedBlock(rpcMethod, params); ok {
+		bundle.Block = fmt.Sprintf("0x%x", block)
+	}
+	for _, client := range c.config.Clients {
+		if c.isGolden(client) {
+			bundle.Clients = append(bundle.Clients, ReproClient{Name: client.Name, Golden: true})
+			continue
+		}
+		redacted, ok := RedactURL(client.URL)
+		bundle.Clients = append(bundle.Clients, ReproClient{Name: client.Name, URL: redacted, Redacted: ok, Version: versions[client.Name]})
+	}
+	for _, rule := range c.config.Rules {
+		if rule.Method == "" || rule.Method == rpcMethod {
+			bundle.Rules = append(bundle.Rules, rule)
+		}
+	}
+	return bundle
+}
+
+// writeReproBundle lays a bundle out in dir: repro.json, request.json, a
+// curl script, each client's raw response and the rendered diff
+func writeReproBundle(dir string, bundle *ReproBundle, responses map[string]interface{}) error {
+	if err := os.MkdirAll(filepath.Join(dir, "responses"), 0755); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(dir, reproFile), bundle); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(dir, "request.json"), bundle.Request); err != nil {
+		return err
+	}
+	for client, response := range responses {
+		if err := writeJSONFile(filepath.Join(dir, "responses", unsafeDirChars.ReplaceAllString(client, "_")+".json"), response); err != nil {
+			return err
+		}
+	}
+
+	requestJSON, err := json.Marshal(bundle.Request)
+	if err != nil {
+		return err
+	}
+	var script strings.Builder
+	script.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&script, "# %s", bundle.Request.Method)
+	if bundle.Block != "" {
+		fmt.Fprintf(&script, " at block %s", bundle.Block)
+	}
+	script.WriteString("\n")
+	for _, client := range bundle.Clients {
+		if client.Golden {
+			continue
+		}
+		fmt.Fprintf(&script, "\n# %s", client.Name)
+		if client.Version != "" {
+			fmt.Fprintf(&script, " (%s)", client.Version)
+		}
+		if client.Redacted {
+			script.WriteString("\n# Credentials were removed from this URL; use the one in clients.yaml")
+		}
+		fmt.Fprintf(&script, "\n%s\n", FormatCurlCommand(client.URL, requestJSON))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "curl.sh"), []byte(script.String()), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, "diff.txt"), []byte(renderDifferences(bundle.Reference, bundle.Differences)), 0644)
+}
+
+// renderDifferences formats differences as text, one entry per line, grouped
+// by client
+func renderDifferences(reference string, differences map[string]interface{}) string {
+	clients := make([]string, 0, len(differences))
+	for client := range differences {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+
+	var out strings.Builder
+	for _, client := range clients {
+		fmt.Fprintf(&out, "%s vs %s\n", reference, client)
+		for _, entry := range flattenDifferences(differences[client]) {
+			v1, _ := json.Marshal(entry.Value1)
+			v2, _ := json.Marshal(entry.Value2)
+			fmt.Fprintf(&out, "  %s %s: %s -> %s\n", entry.Type, entry.Path, v1, v2)
+		}
+	}
+	return out.String()
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReplayRepro sends the request of the bundle in dir to its clients again
+// and compares the responses with the bundle's rules. Golden clients answer
+// with their recorded response. urls, keyed by client name, replaces the
+// recorded URLs, which is needed for the redacted ones; it may be nil.
+func ReplayRepro(dir string, timeoutSeconds int, urls map[string]string) (*ReproOutcome, error) {
+	data, err := os.ReadFile(filepath.Join(dir, reproFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read repro bundle: %w", err)
+	}
+	var bundle ReproBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse repro bundle: %w", err)
+	}
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = 10
+	}
+
+	outcome := &ReproOutcome{Bundle: &bundle, Differences: map[string]interface{}{}, TransportErrors: map[string]string{}}
+	responses := make(map[string]map[string]interface{}, len(bundle.Clients))
+	for _, client := range bundle.Clients {
+		if client.Golden {
+			recorded, err := os.ReadFile(filepath.Join(dir, "responses", unsafeDirChars.ReplaceAllString(client.Name, "_")+".json"))
+			if err != nil {
+				outcome.TransportErrors[client.Name] = fmt.Sprintf("no recorded golden response: %v", err)
+				continue
+			}
+			var response map[string]interface{}
+			if err := json.Unmarshal(recorded, &response); err != nil {
+				return nil, fmt.Errorf("failed to parse recorded response of %s: %w", client.Name, err)
+			}
+			responses[client.Name] = response
+			continue
+		}
+		clientURL := client.URL
+		if override, ok := urls[client.Name]; ok {
+			clientURL = override
+		} else if client.Redacted {
+			outcome.TransportErrors[client.Name] = "its URL was redacted in the bundle and no URL was given"
+			continue
+		}
+		response, err := makeJSONRPCCall(clientURL, bundle.Request.Method, bundle.Request.Params, timeoutSeconds, false, 3, 200*time.Millisecond)
+		if err != nil {
+			outcome.TransportErrors[client.Name] = err.Error()
+			continue
+		}
+		responses[client.Name] = response
+	}
+
+	reference, ok := responses[bundle.Reference]
+	if !ok {
+		return nil, fmt.Errorf("reference client %s did not answer: %s", bundle.Reference, outcome.TransportErrors[bundle.Reference])
+	}
+	ctx := newDiffContext(bundle.Request.Method, bundle.Rules)
+	for _, client := range bundle.Clients {
+		response, ok := responses[client.Name]
+		if !ok || client.Name == bundle.Reference {
+			continue
+		}
+		diff, err := compareJSONRPCResponses(ctx, reference, response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare responses: %w", err)
+		}
+		if len(diff) > 0 {
+			outcome.Differences[client.Name] = diff
+		}
+	}
+	return outcome, nil
+}
@@ -0,0 +1,177 @@
+package comparator
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// A differing call gets a bundle holding the exact pinned request, curl
+// commands, raw responses and the diff; replaying it tracks whether the
+// clients still diverge.
+func TestReproBundleReplay(t *testing.T) {
+	var fixed atomic.Bool
+	ref := newRPCFake(t, "0x1", func(rpcRequest) interface{} { return "0x10" })
+	other := newRPCFake(t, "0x1", func(rpcRequest) interface{} {
+		if fixed.Load() {
+			return "0x10"
+		}
+		return "0x11"
+	})
+	c, err := NewComparator(&ComparisonConfig{
+		Clients:       []*types.ClientConfig{{Name: "geth", URL: ref.URL}, {Name: "reth", URL: other.URL}},
+		OutputDir:     t.TempDir(),
+		BlockOverride: "0x100",
+		Rules:         []ComparisonRule{{Method: "eth_getBalance", Path: "result", Kind: RuleIgnore}, {Method: "eth_getCode", Path: "result", Kind: RuleIgnore}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{"eth_getCode", "eth_getTransactionCount"} {
+		if _, err := c.CompareResponses(method, []interface{}{"0xabc"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := filepath.Join(t.TempDir(), ReproDir)
+	written, err := c.WriteReproBundles(dir, map[string]string{"geth": "Geth/v1.14.0"})
+	if err != nil || written != 1 {
+		t.Fatalf("WriteReproBundles = %d, %v; want only the eth_getTransactionCount bundle", written, err)
+	}
+	bundleDir := filepath.Join(dir, "eth_getTransactionCount")
+	for _, name := range []string{"repro.json", "request.json", "curl.sh", "diff.txt", "responses/geth.json", "responses/reth.json"} {
+		if _, err := os.Stat(filepath.Join(bundleDir, name)); err != nil {
+			t.Errorf("bundle is missing %s", name)
+		}
+	}
+	curl, _ := os.ReadFile(filepath.Join(bundleDir, "curl.sh"))
+	if !strings.Contains(string(curl), other.URL) || !strings.Contains(string(curl), "0x100") || !strings.Contains(string(curl), "Geth/v1.14.0") {
+		t.Errorf("curl.sh should carry each client's pinned request and version:\n%s", curl)
+	}
+	diff, _ := os.ReadFile(filepath.Join(bundleDir, "diff.txt"))
+	if !strings.Contains(string(diff), `result: "0x10" -> "0x11"`) {
+		t.Errorf("diff.txt = %s", diff)
+	}
+
+	outcome, err := ReplayRepro(bundleDir, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outcome.Diverges() || outcome.Bundle.Block != "0x100" || len(outcome.Bundle.Rules) != 0 {
+		t.Errorf("outcome = %+v, bundle = %+v", outcome, outcome.Bundle)
+	}
+
+	fixed.Store(true)
+	outcome, err = ReplayRepro(bundleDir, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.Diverges() {
+		t.Errorf("after the fix the call should agree, got %v", outcome.Differences)
+	}
+}
+
+// Credentials in client URLs stay out of a bundle; replaying takes the full
+// URLs from the caller.
+func TestReproBundleRedactsURLs(t *testing.T) {
+	ref := newRPCFake(t, "0x1", func(rpcRequest) interface{} { return "0x10" })
+	other := newRPCFake(t, "0x1", func(rpcRequest) interface{} { return "0x11" })
+	secretURL := strings.Replace(other.URL, "http://", "http://user:hunter2@", 1) + "/?apikey=hunter2"
+	c, err := NewComparator(&ComparisonConfig{
+		Clients:   []*types.ClientConfig{{Name: "geth", URL: ref.URL}, {Name: "reth", URL: secretURL}},
+		OutputDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CompareResponses("eth_getTransactionCount", []interface{}{"0xabc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), ReproDir)
+	if _, err := c.WriteReproBundles(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	bundleDir := filepath.Join(dir, "eth_getTransactionCount")
+	for _, name := range []string{"repro.json", "curl.sh"} {
+		data, _ := os.ReadFile(filepath.Join(bundleDir, name))
+		if strings.Contains(string(data), "hunter2") {
+			t.Errorf("%s leaks the credentials:\n%s", name, data)
+		}
+	}
+
+	outcome, err := ReplayRepro(bundleDir, 5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := outcome.TransportErrors["reth"]; !ok || outcome.Diverges() {
+		t.Errorf("a redacted client should not be called without its URL: %+v", outcome)
+	}
+	outcome, err = ReplayRepro(bundleDir, 5, map[string]string{"reth": secretURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !outcome.Diverges() {
+		t.Errorf("with the full URL the call should still diverge: %+v", outcome)
+	}
+}
+
+// Bundle directories stay distinct when method names sanitize alike, and
+// only earlier bundles are cleared from the repro directory.
+func TestReproBundleDirs(t *testing.T) {
+	used := make(map[string]bool)
+	names := []string{reproDirName("a/b", used), reproDirName("a_b", used), reproDirName("a_b", used), reproDirName("a b", used)}
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] || strings.ContainsAny(name, "/ ") {
+			t.Errorf("bundle directory names = %v", names)
+		}
+		seen[name] = true
+	}
+	if names[1] != "a_b" || names[2] != "a_b-2" {
+		t.Errorf("bundle directory names = %v", names)
+	}
+
+	dir := t.TempDir()
+	for _, path := range []string{"old/" + reproFile, "old/responses/geth.json", "notes/todo.txt"} {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := removeReproBundles(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old")); !os.IsNotExist(err) {
+		t.Error("the earlier bundle should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes", "todo.txt")); err != nil {
+		t.Error("files that are not bundles should be kept")
+	}
+}
+
+// Payloads in curl scripts reach curl byte for byte: the shell expands
+// nothing inside them and a single quote survives.
+func TestFormatCurlCommandQuotesPayload(t *testing.T) {
+	payload := `{"method":"eth_call","params":[{"data":"$HOME ` + "`id`" + ` it's é"}]}`
+	cmd := FormatCurlCommand("http://localhost:8545", []byte(payload))
+	if !strings.HasPrefix(cmd, "curl -X POST -H 'Content-Type: application/json' -d '") || !strings.HasSuffix(cmd, "' http://localhost:8545") {
+		t.Fatalf("command = %s", cmd)
+	}
+
+	out, err := exec.Command("sh", "-c", "printf '%s' "+shellQuote(payload)).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != payload {
+		t.Errorf("shell passed %s, want %s", out, payload)
+	}
+}
@@ -1,9 +1,12 @@
 package comparator
 
 import (
+	"fmt"
 	"math/big"
 	"regexp"
 	"strings"
+
+	"github.com/jsonrpc-bench/runner/types"
 )
 
 // ComparisonRuleKind enumerates the ways a difference can be declared expected.
@@ -20,29 +23,65 @@ const (
 	RuleErrorCodeOnly ComparisonRuleKind = "error_code_only"
 	// RuleErrorPresenceOnly treats any two error responses as equal.
 	RuleErrorPresenceOnly ComparisonRuleKind = "error_presence_only"
+	// RuleUnordered compares arrays as multisets, or matches their elements
+	// by a Key field such as logIndex.
+	RuleUnordered ComparisonRuleKind = "unordered"
+	// RuleRegex treats two values as equal when both match Pattern.
+	RuleRegex ComparisonRuleKind = "regex"
+	// RuleHexNormalize compares hex strings ignoring case and leading zeros.
+	RuleHexNormalize ComparisonRuleKind = "hex_normalize"
+	// RuleNullEqualsMissing treats a null field as equal to an absent one.
+	RuleNullEqualsMissing ComparisonRuleKind = "null_equals_missing"
+	// RuleOnly restricts comparison to the selected paths; every other path
+	// of the method's responses is skipped.
+	RuleOnly ComparisonRuleKind = "only"
 )
 
 // ComparisonRule declares that a particular difference is expected and should
 // not be reported as a finding. A rule with an empty Method applies to every
-// method; an empty Path applies to the whole response value.
+// method; an empty Path applies to the whole response value (for unordered,
+// regex, hex_normalize and null_equals_missing: to every path in it).
 type ComparisonRule struct {
 	Method string             `json:"method,omitempty" yaml:"method,omitempty"`
 	Path   string             `json:"path,omitempty" yaml:"path,omitempty"`
 	Kind   ComparisonRuleKind `json:"kind" yaml:"kind"`
 	Abs    float64            `json:"abs,omitempty" yaml:"abs,omitempty"`
 	Rel    float64            `json:"rel,omitempty" yaml:"rel,omitempty"`
+	// Key is the element field unordered arrays are matched by
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+	// Pattern is the regular expression both values must match (regex)
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
 }
 
 // ValidRuleKind reports whether kind is one of the supported rule kinds.
 func ValidRuleKind(kind ComparisonRuleKind) bool {
 	switch kind {
-	case RuleIgnore, RuleNumericTolerance, RuleErrorCodeOnly, RuleErrorPresenceOnly:
+	case RuleIgnore, RuleNumericTolerance, RuleErrorCodeOnly, RuleErrorPresenceOnly,
+		RuleUnordered, RuleRegex, RuleHexNormalize, RuleNullEqualsMissing, RuleOnly:
 		return true
 	default:
 		return false
 	}
 }
 
+// validateRule checks the kind-specific fields of a rule
+func validateRule(rule ComparisonRule) error {
+	switch rule.Kind {
+	case RuleRegex:
+		if rule.Pattern == "" {
+			return fmt.Errorf("regex rule needs a pattern")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", rule.Pattern, err)
+		}
+	case RuleOnly:
+		if rule.Path == "" {
+			return fmt.Errorf("only rule needs a path")
+		}
+	}
+	return nil
+}
+
 // defaultEstimateGasRelTolerance is applied to eth_estimateGas unless the
 // config already declares a numeric_tolerance rule for it: gas estimates that
 // differ by no more than 10% are treated as equal.
@@ -58,13 +97,24 @@ type diffContext struct {
 type compiledRule struct {
 	rule ComparisonRule
 	re   *regexp.Regexp
+	// value is the compiled Pattern of a regex rule
+	value *regexp.Regexp
 }
 
 type ruleSet struct {
 	ignores           []compiledRule
 	tolerances        []compiledRule
+	unordered         []compiledRule
+	regexes           []compiledRule
+	hexNormalize      []compiledRule
+	nullEqualsMissing []compiledRule
 	errorCodeOnly     bool
 	errorPresenceOnly bool
+
+	// onlyAllowed holds each only rule's path and all of its ancestors;
+	// onlyBelow matches paths under a selected one
+	onlyAllowed []*regexp.Regexp
+	onlyBelow   []*regexp.Regexp
 }
 
 // newDiffContext filters the config rules down to those that apply to method
@@ -104,11 +154,50 @@ func compileRuleSet(rules []ComparisonRule) *ruleSet {
 			rs.errorCodeOnly = true
 		case RuleErrorPresenceOnly:
 			rs.errorPresenceOnly = true
+		case RuleUnordered:
+			rs.unordered = append(rs.unordered, compiledRule{rule: r, re: compileRulePathOrAll(r.Path)})
+		case RuleRegex:
+			value, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				continue // rejected when the rules are loaded
+			}
+			rs.regexes = append(rs.regexes, compiledRule{rule: r, re: compileRulePathOrAll(r.Path), value: value})
+		case RuleHexNormalize:
+			rs.hexNormalize = append(rs.hexNormalize, compiledRule{rule: r, re: compileRulePathOrAll(r.Path)})
+		case RuleNullEqualsMissing:
+			rs.nullEqualsMissing = append(rs.nullEqualsMissing, compiledRule{rule: r, re: compileRulePathOrAll(r.Path)})
+		case RuleOnly:
+			for _, prefix := range pathPrefixes(r.Path) {
+				rs.onlyAllowed = append(rs.onlyAllowed, compileRulePath(prefix))
+			}
+			below := compileRulePath(r.Path).String()
+			rs.onlyBelow = append(rs.onlyBelow, regexp.MustCompile(strings.TrimSuffix(below, "$")+`[.\[]`))
 		}
 	}
 	return rs
 }
 
+// compileRulePathOrAll compiles a rule path, matching every path when empty
+func compileRulePathOrAll(path string) *regexp.Regexp {
+	if path == "" {
+		return regexp.MustCompile(".*")
+	}
+	return compileRulePath(path)
+}
+
+// pathPrefixes returns a path and each of its ancestors, e.g.
+// "result.logs[*].address" gives "result", "result.logs", "result.logs[*]"
+// and the path itself.
+func pathPrefixes(path string) []string {
+	var prefixes []string
+	for i := 1; i < len(path); i++ {
+		if path[i] == '.' || path[i] == '[' {
+			prefixes = append(prefixes, path[:i])
+		}
+	}
+	return append(prefixes, path)
+}
+
 // compileRulePath turns a rule path into an anchored regexp. A trailing or
 // embedded "[*]" matches any array index, so "result.transactions[*].v"
 // matches "result.transactions[3].v".
@@ -127,6 +216,48 @@ func (rs *ruleSet) matchesIgnore(path string) bool {
 	return false
 }
 
+// skippedByOnly reports whether only rules apply and path is neither a
+// selected path, an ancestor of one, nor under one.
+func (rs *ruleSet) skippedByOnly(path string) bool {
+	if len(rs.onlyAllowed) == 0 {
+		return false
+	}
+	for _, re := range rs.onlyAllowed {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	for _, re := range rs.onlyBelow {
+		if re.MatchString(path) {
+			return false
+		}
+	}
+	return true
+}
+
+// firstMatch returns the first rule of rules whose path matches
+func firstMatch(rules []compiledRule, path string) (compiledRule, bool) {
+	for _, c := range rules {
+		if c.re != nil && c.re.MatchString(path) {
+			return c, true
+		}
+	}
+	return compiledRule{}, false
+}
+
+// normalizeHex lowercases a hex string and strips its leading zeros, so
+// "0x00AB" and "0xab" compare equal. "0x" and "0x0" both become "0x0".
+func normalizeHex(s string) (string, bool) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return "", false
+	}
+	digits := strings.TrimLeft(strings.ToLower(s[2:]), "0")
+	if digits == "" {
+		digits = "0"
+	}
+	return "0x" + digits, true
+}
+
 func (rs *ruleSet) toleranceFor(path string) (ComparisonRule, bool) {
 	for _, c := range rs.tolerances {
 		if c.re != nil && c.re.MatchString(path) {
@@ -205,8 +336,9 @@ func errorCode(errVal interface{}) (int, bool) {
 }
 
 // classifyError buckets an error response into an environment/capability class
-// so it can be filtered as configuration rather than a correctness finding.
-// It returns "" for ordinary execution errors and non-error responses.
+// (see types.ClassifyEnvironmentError) so it can be filtered as configuration
+// rather than a correctness finding. It returns "" for ordinary execution
+// errors and non-error responses.
 func classifyError(resp map[string]interface{}) string {
 	e, ok := resp["error"].(map[string]interface{})
 	if !ok {
@@ -217,17 +349,5 @@ func classifyError(resp map[string]interface{}) string {
 		code = int(f)
 	}
 	msg, _ := e["message"].(string)
-	lower := strings.ToLower(msg)
-
-	switch code {
-	case -32601, -32600:
-		return "namespace_disabled"
-	case -32002:
-		return "no_state"
-	case -32602:
-		if strings.Contains(lower, "range") || strings.Contains(lower, "logs") || strings.Contains(lower, "limit") {
-			return "range_cap"
-		}
-	}
-	return ""
+	return types.ClassifyEnvironmentError(code, msg)
 }
@@ -0,0 +1,286 @@
+package comparator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// CheckpointFile is written next to a results stream and identifies the run
+// it belongs to
+const CheckpointFile = "comparison-checkpoint.json"
+
+// streamCheckpoint pins a results stream to its request set and clients, so
+// a resumed run never mixes the results of two different runs
+type streamCheckpoint struct {
+	RequestSetHash string    `json:"request_set_hash"`
+	Clients        []string  `json:"clients"`
+	StartedAt      time.Time `json:"started_at"`
+}
+
+// resultStream appends every completed call to a JSONL file as soon as it
+// finishes. A crash loses at most the calls in flight, and memory no longer
+// grows with the corpus.
+type resultStream struct {
+	path string
+	file *os.File
+	w    *bufio.Writer
+}
+
+// openResultStream creates the stream at path, or with resume reopens it for
+// appending and returns the calls it already holds. A trailing partial line
+// left by a crash is cut off.
+func openResultStream(path string, resume bool) (*resultStream, map[string]bool, error) {
+	done := make(map[string]bool)
+	if !resume {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create results stream: %w", err)
+		}
+		return &resultStream{path: path, file: file, w: bufio.NewWriter(file)}, done, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, fmt.Errorf("nothing to resume: %s does not exist", path)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open results stream: %w", err)
+	}
+
+	var good int64
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// io.EOF with a partial line, or a read error: stop at the last
+			// complete call
+			if !errors.Is(err, io.EOF) {
+				file.Close()
+				return nil, nil, fmt.Errorf("failed to read results stream: %w", err)
+			}
+			break
+		}
+		var call struct {
+			Method string `json:"method"`
+		}
+		if json.Unmarshal(bytes.TrimSpace(line), &call) != nil || call.Method == "" {
+			break
+		}
+		done[call.Method] = true
+		good += int64(len(line))
+	}
+
+	if err := file.Truncate(good); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to truncate results stream: %w", err)
+	}
+	if _, err := file.Seek(good, io.SeekStart); err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("failed to seek results stream: %w", err)
+	}
+	return &resultStream{path: path, file: file, w: bufio.NewWriter(file)}, done, nil
+}
+
+// write appends one result and flushes it to the file
+func (s *resultStream) write(data []byte) error {
+	if _, err := s.w.Write(data); err != nil {
+		return err
+	}
+	if err := s.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+func (s *resultStream) close() error {
+	if err := s.w.Flush(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// checkCheckpoint writes the checkpoint of a new stream, or on resume checks
+// that the stream in dir was written for the same request set and clients
+func checkCheckpoint(dir string, want streamCheckpoint, resume bool) error {
+	path := filepath.Join(dir, CheckpointFile)
+	if !resume {
+		data, err := json.MarshalIndent(want, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal checkpoint: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write checkpoint: %w", err)
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("nothing to resume: failed to read checkpoint: %w", err)
+	}
+	var got streamCheckpoint
+	if err := json.Unmarshal(data, &got); err != nil {
+		return fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	if got.RequestSetHash != want.RequestSetHash {
+		return fmt.Errorf("cannot resume %s: it was run with a different request set", dir)
+	}
+	if fmt.Sprint(got.Clients) != fmt.Sprint(want.Clients) {
+		return fmt.Errorf("cannot resume %s: it was run against clients %v, not %v", dir, got.Clients, want.Clients)
+	}
+	return nil
+}
+
+// record stores a completed result: appended to the stream when one is open,
+// kept in memory otherwise
+func (c *Comparator) record(result *ComparisonResult) error {
+	if c.stream == nil {
+		c.mutex.Lock()
+		c.results = append(c.results, *result)
+		c.completed++
+		c.mutex.Unlock()
+		return nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.stream.write(data); err != nil {
+		return fmt.Errorf("failed to write results stream: %w", err)
+	}
+	c.completed++
+	c.tallies = nil
+	return nil
+}
+
+// runTallies are the run-wide figures built from every result: the outcome
+// summary, the findings and the slow calls. err is why the results could not
+// be read to the end.
+type runTallies struct {
+	summary  Summary
+	findings []Finding
+	slow     []SlowCall
+	err      error
+}
+
+// runTallies builds the summary, findings and slow calls in one pass over the
+// results. A streamed run is read back and decoded once for all three, not
+// once per caller; the tallies are kept until a result is recorded or a
+// ledger applied. A results stream that cannot be read back is logged and
+// tallied as far as it goes.
+func (c *Comparator) runTallies() *runTallies {
+	c.mutex.Lock()
+	cached := c.tallies
+	c.mutex.Unlock()
+	if cached != nil {
+		return cached
+	}
+
+	t := &runTallies{summary: newSummary(len(c.skipped))}
+	fc := newFindingClusterer(c.config)
+	t.err = c.eachResult(func(r *ComparisonResult) error {
+		t.summary.add(r, c.config)
+		fc.add(*r)
+		t.slow = append(t.slow, slowCallsOf(r)...)
+		return nil
+	})
+	if t.err != nil {
+		log.Printf("Failed to read back comparison results: %v", t.err)
+	}
+	t.findings = fc.findings()
+	sort.SliceStable(t.slow, func(i, j int) bool { return t.slow[i].Factor > t.slow[j].Factor })
+
+	// In-memory results are cheap to walk and may be changed in place, so
+	// only a stream's tallies are kept
+	if c.stream != nil {
+		c.mutex.Lock()
+		c.tallies = t
+		c.mutex.Unlock()
+	}
+	return t
+}
+
+// eachResult calls fn with every result of the run, reading them back from
+// the stream when one is open. The applied ledger, if any, sets each
+// differing result's Ledger status first.
+func (c *Comparator) eachResult(fn func(r *ComparisonResult) error) error {
+	if c.stream == nil {
+		for i := range c.results {
+			c.classify(&c.results[i])
+			if err := fn(&c.results[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	c.mutex.Lock()
+	err := c.stream.w.Flush()
+	c.mutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to flush results stream: %w", err)
+	}
+
+	file, err := os.Open(c.stream.path)
+	if err != nil {
+		return fmt.Errorf("failed to open results stream: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var r ComparisonResult
+			if err := json.Unmarshal(line, &r); err != nil {
+				return fmt.Errorf("failed to parse results stream: %w", err)
+			}
+			c.classify(&r)
+			if err := fn(&r); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read results stream: %w", err)
+		}
+	}
+}
+
+// Completed returns the number of calls compared, including those a resumed
+// run found already done
+func (c *Comparator) Completed() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.completed
+}
+
+// Resumed returns the number of calls a resumed run skipped as already done
+func (c *Comparator) Resumed() int {
+	return len(c.resumed)
+}
+
+// Close flushes and closes the results stream, if one is open
+func (c *Comparator) Close() error {
+	if c.stream == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stream.close()
+}
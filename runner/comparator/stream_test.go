@@ -0,0 +1,155 @@
+package comparator
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+func streamedConfig(t *testing.T, dir, url string) *ComparisonConfig {
+	t.Helper()
+	return &ComparisonConfig{
+		Methods: []string{"eth_getBalance_a", "eth_getBalance_b", "eth_getBalance_c"},
+		MethodRPCNames: map[string]string{
+			"eth_getBalance_a": "eth_getBalance", "eth_getBalance_b": "eth_getBalance", "eth_getBalance_c": "eth_getBalance",
+		},
+		CustomParameters: map[string][]interface{}{
+			"eth_getBalance_a": {"0xa", "0x1"}, "eth_getBalance_b": {"0xb", "0x1"}, "eth_getBalance_c": {"0xc", "0x1"},
+		},
+		Clients:    []*types.ClientConfig{{Name: "geth", URL: url}, {Name: "reth", URL: url}},
+		OutputDir:  dir,
+		StreamPath: filepath.Join(dir, "comparison-results.jsonl"),
+	}
+}
+
+// A run cut off mid-write is resumed from its stream: the partial line is
+// dropped, only the missing calls are made, and the output covers every call.
+func TestResumeStreamedRun(t *testing.T) {
+	var calls atomic.Int32
+	url := newRPCFake(t, "0x1", func(rpcRequest) interface{} {
+		calls.Add(1)
+		return "0x10"
+	}).URL
+	dir := t.TempDir()
+
+	first, err := NewComparator(streamedConfig(t, dir, url))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := first.RunComparisons(); err != nil {
+		t.Fatal(err)
+	}
+	if len(first.GetResults()) != 0 {
+		t.Error("streamed results must not be kept in memory")
+	}
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash after two calls, halfway through writing the third
+	stream := filepath.Join(dir, "comparison-results.jsonl")
+	data, err := os.ReadFile(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	crashed := lines[0] + lines[1] + lines[2][:len(lines[2])/2]
+	if err := os.WriteFile(stream, []byte(crashed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls.Store(0)
+	cfg := streamedConfig(t, dir, url)
+	cfg.Resume = true
+	resumed, err := NewComparator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Close()
+	if resumed.Resumed() != 2 {
+		t.Errorf("resumed = %d, want 2", resumed.Resumed())
+	}
+	if _, err := resumed.RunComparisons(); err != nil {
+		t.Fatal(err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("resumed run made %d requests, want 2 (one call, two clients)", n)
+	}
+
+	if s := resumed.Summarize(); s.Total != 3 || s.Identical != 3 {
+		t.Errorf("summary = %+v, want 3 identical calls", s)
+	}
+	out := filepath.Join(dir, "comparison-results.json")
+	if err := resumed.SaveResults(out); err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []ComparisonResult
+	if err := json.Unmarshal(saved, &results); err != nil {
+		t.Fatalf("saved results are not a JSON array: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("saved %d results, want 3", len(results))
+	}
+}
+
+// A stream is only resumed with the request set it was started with.
+func TestResumeRejectsOtherRequestSet(t *testing.T) {
+	url := newRPCFake(t, "0x1", func(rpcRequest) interface{} { return "0x10" }).URL
+	dir := t.TempDir()
+
+	first, err := NewComparator(streamedConfig(t, dir, url))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Close()
+
+	cfg := streamedConfig(t, dir, url)
+	cfg.Methods = cfg.Methods[:2]
+	cfg.Resume = true
+	if _, err := NewComparator(cfg); err == nil || !strings.Contains(err.Error(), "different request set") {
+		t.Errorf("err = %v, want a request set mismatch", err)
+	}
+}
+
+// A streamed run is read back once for its summary, findings and slow calls,
+// and read again once another result is recorded.
+func TestStreamedTalliesReusedUntilRecord(t *testing.T) {
+	url := newRPCFake(t, "0x1", func(rpcRequest) interface{} { return "0x10" }).URL
+	dir := t.TempDir()
+	comp, err := NewComparator(streamedConfig(t, dir, url))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer comp.Close()
+	if _, err := comp.RunComparisons(); err != nil {
+		t.Fatal(err)
+	}
+
+	first := comp.runTallies()
+	comp.Findings()
+	if _, err := comp.SlowCalls(); err != nil {
+		t.Fatal(err)
+	}
+	if comp.runTallies() != first {
+		t.Error("tallies were rebuilt without a new result")
+	}
+	if s := comp.Summarize(); s.Total != 3 {
+		t.Errorf("summary total = %d, want 3", s.Total)
+	}
+
+	if err := comp.record(&ComparisonResult{Method: "eth_getBalance_a", Differences: map[string]interface{}{}}); err != nil {
+		t.Fatal(err)
+	}
+	if s := comp.Summarize(); s.Total != 4 {
+		t.Errorf("summary total after record = %d, want 4", s.Total)
+	}
+}
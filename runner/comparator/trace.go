@@ -0,0 +1,387 @@
+package comparator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// traceMethods are the methods whose results are compared as tracer output
+// rather than generic JSON
+var traceMethods = map[string]bool{
+	"debug_traceTransaction":   true,
+	"debug_traceCall":          true,
+	"debug_traceBlockByNumber": true,
+	"debug_traceBlockByHash":   true,
+}
+
+// addressPattern matches a 20-byte hex address, the keys of prestateTracer
+// output
+var addressPattern = regexp.MustCompile(`^0[xX][0-9a-fA-F]{40}$`)
+
+// TraceDivergence locates the first call frame where two callTracer traces
+// diverge
+type TraceDivergence struct {
+	// Path is the frame's path in the first response
+	Path string `json:"path"`
+	// CallPath lists the frames from the root down, e.g.
+	// ["CALL 0xa->0xb", "DELEGATECALL 0xb->0xc"]
+	CallPath []string  `json:"call_path"`
+	First    DiffEntry `json:"first_difference"`
+}
+
+// traceDiff compares tracer output. Call trees are aligned by
+// (type, from, to, input) so an extra or missing internal call is reported
+// once instead of shifting every later index; prestate maps are compared by
+// account and storage key regardless of hex case.
+type traceDiff struct {
+	ctx        *diffContext
+	divergence *TraceDivergence
+}
+
+// compare dispatches on the shape of the tracer output and falls back to
+// deepCompare for anything else, such as struct logger output.
+func (t *traceDiff) compare(path string, val1, val2 interface{}) ([]DiffEntry, error) {
+	if t.ctx.rules.matchesIgnore(path) || t.ctx.rules.skippedByOnly(path) {
+		return nil, nil
+	}
+	switch v1 := val1.(type) {
+	case []interface{}:
+		v2, ok := val2.([]interface{})
+		if ok && isBlockTrace(v1) && isBlockTrace(v2) {
+			return t.compareBlockTraces(path, v1, v2)
+		}
+	case map[string]interface{}:
+		v2, ok := val2.(map[string]interface{})
+		if !ok {
+			break
+		}
+		if isCallFrame(v1) && isCallFrame(v2) {
+			return t.compareFrames(path, nil, v1, v2)
+		}
+		if isPrestateDiffMode(v1) && isPrestateDiffMode(v2) {
+			var diffs []DiffEntry
+			for _, side := range []string{"pre", "post"} {
+				entries, err := t.comparePrestate(path+"."+side, v1[side].(map[string]interface{}), v2[side].(map[string]interface{}))
+				if err != nil {
+					return nil, err
+				}
+				diffs = append(diffs, entries...)
+			}
+			return diffs, nil
+		}
+		if isPrestate(v1) && isPrestate(v2) {
+			return t.comparePrestate(path, v1, v2)
+		}
+	}
+	return deepCompare(t.ctx, path, val1, val2)
+}
+
+// compareBlockTraces compares the per-transaction traces of a block in order
+func (t *traceDiff) compareBlockTraces(path string, arr1, arr2 []interface{}) ([]DiffEntry, error) {
+	var diffs []DiffEntry
+	if len(arr1) != len(arr2) {
+		diffs = append(diffs, DiffEntry{Path: path, Type: DiffTypeArrayLengthMismatch, Value1: len(arr1), Value2: len(arr2)})
+	}
+	for i := 0; i < len(arr1) && i < len(arr2); i++ {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+		item1, item2 := arr1[i].(map[string]interface{}), arr2[i].(map[string]interface{})
+		rest1, rest2 := withoutKey(item1, "result"), withoutKey(item2, "result")
+		entries, err := deepCompare(t.ctx, itemPath, rest1, rest2)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, entries...)
+		entries, err = t.compare(itemPath+".result", item1["result"], item2["result"])
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, entries...)
+	}
+	return diffs, nil
+}
+
+// compareFrames compares two callTracer frames: their own fields first, then
+// their subcalls aligned by call identity
+func (t *traceDiff) compareFrames(path string, parents []string, f1, f2 map[string]interface{}) ([]DiffEntry, error) {
+	callPath := append(append([]string(nil), parents...), frameLabel(f1))
+	callPath = callPath[:len(callPath):len(callPath)]
+
+	diffs, err := deepCompare(t.ctx, path, withoutKey(f1, "calls"), withoutKey(f2, "calls"))
+	if err != nil {
+		return nil, err
+	}
+	t.noteDivergence(path, callPath, diffs)
+
+	callsPath := path + ".calls"
+	if t.ctx.rules.matchesIgnore(callsPath) || t.ctx.rules.skippedByOnly(callsPath) {
+		return diffs, nil
+	}
+	calls1, _ := f1["calls"].([]interface{})
+	calls2, _ := f2["calls"].([]interface{})
+	for _, pair := range alignCalls(calls1, calls2) {
+		var entries []DiffEntry
+		switch {
+		case pair.i < 0:
+			missing := DiffEntry{Path: fmt.Sprintf("%s[%d]", callsPath, pair.j), Type: DiffTypeFieldMissing, Value2: frameLabel(calls2[pair.j])}
+			entries = []DiffEntry{missing}
+			t.noteDivergence(missing.Path, append(callPath, fmt.Sprint(missing.Value2)), entries)
+		case pair.j < 0:
+			extra := DiffEntry{Path: fmt.Sprintf("%s[%d]", callsPath, pair.i), Type: DiffTypeFieldExtra, Value1: frameLabel(calls1[pair.i])}
+			entries = []DiffEntry{extra}
+			t.noteDivergence(extra.Path, append(callPath, fmt.Sprint(extra.Value1)), entries)
+		default:
+			childPath := fmt.Sprintf("%s[%d]", callsPath, pair.i)
+			child1, ok1 := calls1[pair.i].(map[string]interface{})
+			child2, ok2 := calls2[pair.j].(map[string]interface{})
+			if ok1 && ok2 {
+				entries, err = t.compareFrames(childPath, callPath, child1, child2)
+			} else {
+				entries, err = deepCompare(t.ctx, childPath, calls1[pair.i], calls2[pair.j])
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		diffs = append(diffs, entries...)
+	}
+	return diffs, nil
+}
+
+// noteDivergence records the first frame with differences
+func (t *traceDiff) noteDivergence(path string, callPath []string, diffs []DiffEntry) {
+	if t.divergence != nil || len(diffs) == 0 {
+		return
+	}
+	t.divergence = &TraceDivergence{Path: path, CallPath: append([]string(nil), callPath...), First: diffs[0]}
+}
+
+// callPair is one step of a subcall alignment; -1 marks a call present on
+// one side only
+type callPair struct{ i, j int }
+
+// alignCalls matches two subcall lists on their longest common subsequence of
+// call identities. Between two matched calls, leftover calls are paired by
+// position when both sides have the same number of them, so a call whose
+// input changed is still compared field by field.
+func alignCalls(calls1, calls2 []interface{}) []callPair {
+	n, m := len(calls1), len(calls2)
+	keys1, keys2 := make([]string, n), make([]string, m)
+	for i, c := range calls1 {
+		keys1[i] = callKey(c)
+	}
+	for j, c := range calls2 {
+		keys2[j] = callKey(c)
+	}
+
+	// lcs[i][j] is the LCS length of keys1[i:] and keys2[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if keys1[i] == keys2[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var pairs []callPair
+	var gap1, gap2 []int
+	flush := func() {
+		if len(gap1) == len(gap2) {
+			for k := range gap1 {
+				pairs = append(pairs, callPair{gap1[k], gap2[k]})
+			}
+		} else {
+			for _, i := range gap1 {
+				pairs = append(pairs, callPair{i, -1})
+			}
+			for _, j := range gap2 {
+				pairs = append(pairs, callPair{-1, j})
+			}
+		}
+		gap1, gap2 = nil, nil
+	}
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case keys1[i] == keys2[j]:
+			flush()
+			pairs = append(pairs, callPair{i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			gap1 = append(gap1, i)
+			i++
+		default:
+			gap2 = append(gap2, j)
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		gap1 = append(gap1, i)
+	}
+	for ; j < m; j++ {
+		gap2 = append(gap2, j)
+	}
+	flush()
+	return pairs
+}
+
+// callKey is the identity a subcall is aligned on
+func callKey(call interface{}) string {
+	frame, _ := call.(map[string]interface{})
+	field := func(name string) string {
+		s, _ := frame[name].(string)
+		return strings.ToLower(s)
+	}
+	return strings.Join([]string{strings.ToUpper(field("type")), field("from"), field("to"), field("input")}, "|")
+}
+
+// frameLabel describes a frame for a call path, e.g. "CALL 0xa->0xb"
+func frameLabel(v interface{}) string {
+	frame, _ := v.(map[string]interface{})
+	typ, _ := frame["type"].(string)
+	from, _ := frame["from"].(string)
+	to, _ := frame["to"].(string)
+	return fmt.Sprintf("%s %s->%s", strings.ToUpper(typ), strings.ToLower(from), strings.ToLower(to))
+}
+
+// comparePrestate compares prestateTracer output account by account and the
+// storage of each account slot by slot. Addresses and slots are matched
+// case-insensitively.
+func (t *traceDiff) comparePrestate(path string, p1, p2 map[string]interface{}) ([]DiffEntry, error) {
+	accounts1, accounts2 := lowerKeys(p1), lowerKeys(p2)
+	var diffs []DiffEntry
+	for _, addr := range unionKeys(accounts1, accounts2) {
+		accountPath := path + "." + addr
+		if t.ctx.rules.matchesIgnore(accountPath) || t.ctx.rules.skippedByOnly(accountPath) {
+			continue
+		}
+		a1, ok1 := accounts1[addr]
+		a2, ok2 := accounts2[addr]
+		if !ok1 {
+			diffs = append(diffs, DiffEntry{Path: accountPath, Type: DiffTypeFieldMissing, Value2: a2})
+			continue
+		}
+		if !ok2 {
+			diffs = append(diffs, DiffEntry{Path: accountPath, Type: DiffTypeFieldExtra, Value1: a1})
+			continue
+		}
+		acc1, isMap1 := a1.(map[string]interface{})
+		acc2, isMap2 := a2.(map[string]interface{})
+		if !isMap1 || !isMap2 {
+			entries, err := deepCompare(t.ctx, accountPath, a1, a2)
+			if err != nil {
+				return nil, err
+			}
+			diffs = append(diffs, entries...)
+			continue
+		}
+
+		entries, err := deepCompare(t.ctx, accountPath, withoutKey(acc1, "storage"), withoutKey(acc2, "storage"))
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, entries...)
+
+		storage1, has1 := acc1["storage"].(map[string]interface{})
+		storage2, has2 := acc2["storage"].(map[string]interface{})
+		if !has1 && !has2 {
+			continue
+		}
+		entries, err = deepCompare(t.ctx, accountPath+".storage", lowerKeys(storage1), lowerKeys(storage2))
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, entries...)
+	}
+	return diffs, nil
+}
+
+// isCallFrame reports whether v looks like a callTracer frame
+func isCallFrame(v map[string]interface{}) bool {
+	_, hasType := v["type"].(string)
+	_, hasFrom := v["from"].(string)
+	return hasType && hasFrom
+}
+
+// isPrestate reports whether v looks like prestateTracer output: a non-empty
+// map from address to account
+func isPrestate(v map[string]interface{}) bool {
+	if len(v) == 0 {
+		return false
+	}
+	for key, account := range v {
+		if _, ok := account.(map[string]interface{}); !ok || !addressPattern.MatchString(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPrestateDiffMode reports whether v is prestateTracer output in diff mode
+func isPrestateDiffMode(v map[string]interface{}) bool {
+	if len(v) != 2 {
+		return false
+	}
+	pre, ok1 := v["pre"].(map[string]interface{})
+	post, ok2 := v["post"].(map[string]interface{})
+	return ok1 && ok2 && (len(pre) == 0 || isPrestate(pre)) && (len(post) == 0 || isPrestate(post))
+}
+
+// isBlockTrace reports whether arr is a block's per-transaction traces
+func isBlockTrace(arr []interface{}) bool {
+	for _, item := range arr {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := obj["result"]; !ok {
+			return false
+		}
+	}
+	return len(arr) > 0
+}
+
+// withoutKey returns a shallow copy of obj without key
+func withoutKey(obj map[string]interface{}, key string) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// lowerKeys returns a copy of obj with lowercased keys
+func lowerKeys(obj map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(obj))
+	for k, v := range obj {
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
+
+// unionKeys returns the sorted keys present in either map
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,98 @@
+package comparator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func frame(typ, from, to, input string, calls ...interface{}) map[string]interface{} {
+	f := map[string]interface{}{"type": typ, "from": from, "to": to, "input": input, "gasUsed": "0x10"}
+	if len(calls) > 0 {
+		f["calls"] = calls
+	}
+	return f
+}
+
+// One extra internal call is reported once, not as a shift of every later
+// subcall, and the first divergent frame carries its call path.
+func TestTraceDiffAlignsCallTrees(t *testing.T) {
+	later := func() []interface{} {
+		return []interface{}{
+			frame("STATICCALL", "0xb", "0xd", "0x02"),
+			frame("STATICCALL", "0xb", "0xe", "0x03"),
+			frame("STATICCALL", "0xb", "0xf", "0x04"),
+		}
+	}
+	calls1 := append([]interface{}{frame("CALL", "0xb", "0xc", "0x01")}, later()...)
+	calls2 := later()
+	calls2[2].(map[string]interface{})["gasUsed"] = "0x20"
+
+	ctx := newDiffContext("debug_traceTransaction", nil)
+	diffs, err := compareJSONRPCResponses(ctx,
+		resultResp(frame("CALL", "0xa", "0xb", "0x", frame("DELEGATECALL", "0xb", "0xb", "0x", calls1...))),
+		resultResp(frame("CALL", "0xa", "0xb", "0x", frame("DELEGATECALL", "0xb", "0xb", "0x", calls2...))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, _ := diffs["result_differences"].([]DiffEntry)
+	if len(entries) != 2 {
+		t.Fatalf("want the extra call and one gas difference, got %+v", entries)
+	}
+	if entries[0].Path != "result.calls[0].calls[0]" || entries[0].Type != DiffTypeFieldExtra {
+		t.Errorf("extra call = %+v", entries[0])
+	}
+	if entries[1].Path != "result.calls[0].calls[3].gasUsed" {
+		t.Errorf("gas difference = %+v", entries[1])
+	}
+
+	divergence, ok := diffs["trace_divergence"].(*TraceDivergence)
+	if !ok {
+		t.Fatalf("trace_divergence missing: %v", diffs)
+	}
+	wantPath := []string{"CALL 0xa->0xb", "DELEGATECALL 0xb->0xb", "CALL 0xb->0xc"}
+	if divergence.Path != "result.calls[0].calls[0]" || !reflect.DeepEqual(divergence.CallPath, wantPath) {
+		t.Errorf("divergence = %+v", divergence)
+	}
+}
+
+// A call whose input changed is compared field by field rather than reported
+// as one extra and one missing call.
+func TestTraceDiffPairsChangedCall(t *testing.T) {
+	ctx := newDiffContext("debug_traceCall", nil)
+	entries, err := (&traceDiff{ctx: ctx}).compare("result",
+		frame("CALL", "0xa", "0xb", "0x", frame("CALL", "0xb", "0xc", "0x01")),
+		frame("CALL", "0xa", "0xb", "0x", frame("CALL", "0xb", "0xc", "0x02")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Path != "result.calls[0].input" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+// Prestate maps are matched by account and storage slot regardless of case.
+func TestTraceDiffPrestate(t *testing.T) {
+	addr := "0x00000000000000000000000000000000000000aa"
+	upper := "0x00000000000000000000000000000000000000AA"
+	other := "0x00000000000000000000000000000000000000bb"
+	pre1 := map[string]interface{}{
+		addr: map[string]interface{}{"balance": "0x1", "storage": map[string]interface{}{"0x0a": "0x1", "0x0b": "0x2"}},
+	}
+	pre2 := map[string]interface{}{
+		upper: map[string]interface{}{"balance": "0x1", "storage": map[string]interface{}{"0x0A": "0x1", "0x0b": "0x3"}},
+		other: map[string]interface{}{"balance": "0x0"},
+	}
+
+	ctx := newDiffContext("debug_traceTransaction", nil)
+	entries, err := (&traceDiff{ctx: ctx}).compare("result", pre1, pre2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v", entries)
+	}
+	if entries[0].Path != "result."+addr+".storage.0x0b" || entries[1].Path != "result."+other || entries[1].Type != DiffTypeFieldMissing {
+		t.Errorf("entries = %+v", entries)
+	}
+}
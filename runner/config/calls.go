@@ -39,9 +39,9 @@ func (c *Call) LoadFile() error {
 }
 
 // Sample returns a random call from the call collection or the single call if no collection is provided
-func (c *Call) Sample() (RPCCall, error) {
+func (c *Call) Sample(rng *rand.Rand) (RPCCall, error) {
 	if len(c.Calls) > 0 {
-		return c.Calls[rand.Intn(len(c.Calls))], nil // Uniformly sample a call
+		return c.Calls[rng.Intn(len(c.Calls))], nil // Uniformly sample a call
 	}
 
 	// Use the single call if no collection is provided
@@ -144,6 +144,11 @@ func (cr *ClientRegistry) validateConfig(config types.ClientsConfig) error {
 			}
 		}
 
+		// Resource accounting reads either the cgroup or the process, not both
+		if client.Process != "" && client.Cgroup != "" {
+			return fmt.Errorf("client %s sets both process and cgroup; use one", client.Name)
+		}
+
 		// Validate rate limit configuration if present
 		if client.RateLimit != nil {
 			if client.RateLimit.RequestsPerSecond <= 0 {
@@ -11,6 +11,7 @@ import (
 type Config struct {
 	TestName        string                `yaml:"test_name"`
 	Description     string                `yaml:"description"`
+	Tags            []string              `yaml:"tags"`
 	ClientRefs      []string              `yaml:"clients"`
 	Duration        string                `yaml:"duration"`
 	RPS             int                   `yaml:"rps"`
@@ -18,6 +19,7 @@ type Config struct {
 	VUs             int                   `yaml:"vus"`
 	Calls           []*Call               `yaml:"calls"`
 	CallsFile       string                `yaml:"calls_file"` // Optional: use file containing RPC calls instead of generating them
+	Seed            int64                 `yaml:"seed"`       // Optional: seeds request generation; 0 draws one at random
 	ResolvedClients []*types.ClientConfig `yaml:"-"`
 	Outputs         *Outputs              `yaml:"-"`
 }
@@ -15,7 +15,15 @@ type PrometheusRW struct {
 	BasicAuth BasicAuth `yaml:"basic_auth"`
 }
 
+// RequestStream is a k6 JSON output recording every request's samples, so
+// individual requests can be analysed after the run. It is large and off by
+// default.
+type RequestStream struct {
+	Path string `yaml:"path"`
+}
+
 // Outputs represents the outputs to be used by the benchmarks
 type Outputs struct {
-	PrometheusRW *PrometheusRW `yaml:"prometheus_rw,omitempty"`
+	PrometheusRW  *PrometheusRW  `yaml:"prometheus_rw,omitempty"`
+	RequestStream *RequestStream `yaml:"request_stream,omitempty"`
 }
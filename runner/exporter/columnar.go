@@ -0,0 +1,339 @@
+package exporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// Columnar formats
+const (
+	ColumnarParquet = "parquet"
+	ColumnarArrow   = "arrow"
+)
+
+// ColumnarSchemaVersion is stored in every table's schema metadata. Bump it
+// when a column is renamed, removed or changes type; adding a nullable column
+// at the end is compatible.
+const ColumnarSchemaVersion = "1"
+
+// ParseColumnarFormats parses a comma-separated list of columnar formats
+func ParseColumnarFormats(value string) ([]string, error) {
+	var formats []string
+	for _, f := range strings.Split(value, ",") {
+		f = strings.TrimSpace(f)
+		switch f {
+		case "":
+			continue
+		case ColumnarParquet, ColumnarArrow:
+			formats = append(formats, f)
+		default:
+			return nil, fmt.Errorf("unsupported columnar format %q (use %s or %s)", f, ColumnarParquet, ColumnarArrow)
+		}
+	}
+	return formats, nil
+}
+
+var (
+	tsType = &arrow.TimestampType{Unit: arrow.Millisecond, TimeZone: "UTC"}
+
+	runsSchema = columnarSchema(
+		arrow.Field{Name: "run_id", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "test_name", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "timestamp", Type: tsType},
+		arrow.Field{Name: "start_time", Type: tsType, Nullable: true},
+		arrow.Field{Name: "end_time", Type: tsType, Nullable: true},
+		arrow.Field{Name: "duration_seconds", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "git_commit", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "git_branch", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "os", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "architecture", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "cpu_model", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "cpu_cores", Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: "total_memory_gb", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "k6_version", Type: arrow.BinaryTypes.String},
+	)
+
+	metricsSchema = columnarSchema(
+		arrow.Field{Name: "run_id", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "client", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "client_version", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "method", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "requests", Type: arrow.PrimitiveTypes.Int64},
+		arrow.Field{Name: "errors", Type: arrow.PrimitiveTypes.Int64},
+		arrow.Field{Name: "error_rate", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "throughput", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "latency_min_ms", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "latency_avg_ms", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "latency_p50_ms", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "latency_p90_ms", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "latency_p95_ms", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "latency_p99_ms", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "latency_max_ms", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	)
+
+	timeSeriesSchema = columnarSchema(
+		arrow.Field{Name: "run_id", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "client", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "metric", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "timestamp", Type: tsType},
+		arrow.Field{Name: "value", Type: arrow.PrimitiveTypes.Float64},
+	)
+
+	requestsSchema = columnarSchema(
+		arrow.Field{Name: "run_id", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "client", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "request_id", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "method", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "latency_ms", Type: arrow.PrimitiveTypes.Float64},
+	)
+)
+
+func columnarSchema(fields ...arrow.Field) *arrow.Schema {
+	md := arrow.NewMetadata([]string{"jsonrpc_bench.schema_version"}, []string{ColumnarSchemaVersion})
+	return arrow.NewSchema(fields, &md)
+}
+
+// ColumnarRun identifies a run in a columnar dataset
+type ColumnarRun struct {
+	RunID     string
+	TestName  string
+	GitCommit string
+	GitBranch string
+	Timestamp time.Time
+}
+
+// ColumnarDataset accumulates one or more runs into four tables: runs,
+// metrics (per client and method; method "all" is the client aggregate),
+// time_series (client timelines, client resources and the generator under
+// client "generator") and requests (per-request latencies, when captured)
+type ColumnarDataset struct {
+	runs       *array.RecordBuilder
+	metrics    *array.RecordBuilder
+	timeSeries *array.RecordBuilder
+	requests   *array.RecordBuilder
+}
+
+// NewColumnarDataset creates an empty dataset
+func NewColumnarDataset() *ColumnarDataset {
+	mem := memory.NewGoAllocator()
+	return &ColumnarDataset{
+		runs:       array.NewRecordBuilder(mem, runsSchema),
+		metrics:    array.NewRecordBuilder(mem, metricsSchema),
+		timeSeries: array.NewRecordBuilder(mem, timeSeriesSchema),
+		requests:   array.NewRecordBuilder(mem, requestsSchema),
+	}
+}
+
+// Release frees the dataset's buffers
+func (d *ColumnarDataset) Release() {
+	d.runs.Release()
+	d.metrics.Release()
+	d.timeSeries.Release()
+	d.requests.Release()
+}
+
+// AddRun appends a run. latencies maps client -> request id -> latency in ms
+// and methods maps request id -> method, as in metrics.RequestCapture; both
+// may be nil. Rows are added in a stable order.
+func (d *ColumnarDataset) AddRun(run ColumnarRun, result *types.BenchmarkResult, latencies map[string]map[string]float64, methods map[string]string) {
+	d.addRunRow(run, result)
+
+	clients := sortedKeys(result.ClientMetrics)
+	for _, name := range clients {
+		client := result.ClientMetrics[name]
+		score, hasScore := result.PerformanceScore[name]
+		d.addMetricRow(run.RunID, name, client.Version, "all", client.Latency, client.TotalRequests, client.TotalErrors, client.ErrorRate, score, hasScore)
+		for _, method := range sortedKeys(client.Methods) {
+			summary := client.Methods[method]
+			d.addMetricRow(run.RunID, name, client.Version, method, summary, summary.Count, summary.ErrorCount, summary.ErrorRate, 0, false)
+		}
+
+		for _, metric := range sortedKeys(client.TimeSeries) {
+			for _, point := range client.TimeSeries[metric] {
+				d.addTimeSeriesRow(run.RunID, name, metric, point.Timestamp, point.Value)
+			}
+		}
+		if client.Resources != nil {
+			for _, sample := range client.Resources.Samples {
+				d.addTimeSeriesRow(run.RunID, name, "cpu_cores", sample.Timestamp, sample.CPUCores)
+				d.addTimeSeriesRow(run.RunID, name, "memory_bytes", sample.Timestamp, float64(sample.MemoryBytes))
+				if sample.Watts > 0 {
+					d.addTimeSeriesRow(run.RunID, name, "watts", sample.Timestamp, sample.Watts)
+				}
+			}
+		}
+	}
+
+	for _, sample := range result.SystemMetrics {
+		if sample.Timestamp == 0 {
+			continue
+		}
+		d.addTimeSeriesRow(run.RunID, "generator", "host_cpu_percent", sample.Timestamp, sample.HostCPUPercent)
+		d.addTimeSeriesRow(run.RunID, "generator", "process_cpu_percent", sample.Timestamp, sample.CPUUsage)
+		d.addTimeSeriesRow(run.RunID, "generator", "memory_mb", sample.Timestamp, sample.MemoryUsage)
+	}
+
+	ids := sortedKeys(methods)
+	for _, client := range sortedKeys(latencies) {
+		for _, id := range ids {
+			if latency, ok := latencies[client][id]; ok {
+				d.addRequestRow(run.RunID, client, id, methods[id], latency)
+			}
+		}
+	}
+}
+
+func (d *ColumnarDataset) addRunRow(run ColumnarRun, result *types.BenchmarkResult) {
+	b := d.runs
+	env := result.Environment
+	b.Field(0).(*array.StringBuilder).Append(run.RunID)
+	b.Field(1).(*array.StringBuilder).Append(run.TestName)
+	b.Field(2).(*array.TimestampBuilder).Append(arrow.Timestamp(run.Timestamp.UnixMilli()))
+	appendRunTime(b.Field(3).(*array.TimestampBuilder), result.StartTime)
+	appendRunTime(b.Field(4).(*array.TimestampBuilder), result.EndTime)
+	duration, _ := time.ParseDuration(result.Duration)
+	b.Field(5).(*array.Float64Builder).Append(duration.Seconds())
+	b.Field(6).(*array.StringBuilder).Append(run.GitCommit)
+	b.Field(7).(*array.StringBuilder).Append(run.GitBranch)
+	b.Field(8).(*array.StringBuilder).Append(env.OS)
+	b.Field(9).(*array.StringBuilder).Append(env.Architecture)
+	b.Field(10).(*array.StringBuilder).Append(env.CPUModel)
+	b.Field(11).(*array.Int32Builder).Append(int32(env.CPUCores))
+	b.Field(12).(*array.Float64Builder).Append(env.TotalMemoryGB)
+	b.Field(13).(*array.StringBuilder).Append(env.K6Version)
+}
+
+// appendRunTime appends a BenchmarkResult time.DateTime bound, or null
+func appendRunTime(b *array.TimestampBuilder, value string) {
+	t, err := time.ParseInLocation(time.DateTime, value, time.Local)
+	if err != nil {
+		b.AppendNull()
+		return
+	}
+	b.Append(arrow.Timestamp(t.UnixMilli()))
+}
+
+func (d *ColumnarDataset) addMetricRow(runID, client, version, method string, s types.MetricSummary, count, errorCount int64, errorRate, score float64, hasScore bool) {
+	b := d.metrics
+	b.Field(0).(*array.StringBuilder).Append(runID)
+	b.Field(1).(*array.StringBuilder).Append(client)
+	b.Field(2).(*array.StringBuilder).Append(version)
+	b.Field(3).(*array.StringBuilder).Append(method)
+	b.Field(4).(*array.Int64Builder).Append(count)
+	b.Field(5).(*array.Int64Builder).Append(errorCount)
+	b.Field(6).(*array.Float64Builder).Append(errorRate)
+	b.Field(7).(*array.Float64Builder).Append(s.Throughput)
+	for i, v := range []float64{s.Min, s.Avg, s.P50, s.P90, s.P95, s.P99, s.Max} {
+		b.Field(8 + i).(*array.Float64Builder).Append(v)
+	}
+	if hasScore {
+		b.Field(15).(*array.Float64Builder).Append(score)
+	} else {
+		b.Field(15).(*array.Float64Builder).AppendNull()
+	}
+}
+
+func (d *ColumnarDataset) addTimeSeriesRow(runID, client, metric string, timestamp int64, value float64) {
+	b := d.timeSeries
+	b.Field(0).(*array.StringBuilder).Append(runID)
+	b.Field(1).(*array.StringBuilder).Append(client)
+	b.Field(2).(*array.StringBuilder).Append(metric)
+	b.Field(3).(*array.TimestampBuilder).Append(arrow.Timestamp(timestamp))
+	b.Field(4).(*array.Float64Builder).Append(value)
+}
+
+func (d *ColumnarDataset) addRequestRow(runID, client, requestID, method string, latency float64) {
+	b := d.requests
+	b.Field(0).(*array.StringBuilder).Append(runID)
+	b.Field(1).(*array.StringBuilder).Append(client)
+	b.Field(2).(*array.StringBuilder).Append(requestID)
+	b.Field(3).(*array.StringBuilder).Append(method)
+	b.Field(4).(*array.Float64Builder).Append(latency)
+}
+
+// Write writes every table of the dataset to dir once per format, as
+// <table>.parquet or <table>.arrow (Arrow IPC file format). It drains the
+// dataset.
+func (d *ColumnarDataset) Write(dir string, formats ...string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create columnar export directory: %w", err)
+	}
+	tables := []struct {
+		name    string
+		builder *array.RecordBuilder
+	}{
+		{"runs", d.runs},
+		{"metrics", d.metrics},
+		{"time_series", d.timeSeries},
+		{"requests", d.requests},
+	}
+	for _, table := range tables {
+		rec := table.builder.NewRecord()
+		for _, format := range formats {
+			if err := writeRecord(filepath.Join(dir, table.name+"."+format), format, rec); err != nil {
+				rec.Release()
+				return fmt.Errorf("failed to write %s table: %w", table.name, err)
+			}
+		}
+		rec.Release()
+	}
+	return nil
+}
+
+func writeRecord(path, format string, rec arrow.Record) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch format {
+	case ColumnarParquet:
+		props := parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Zstd))
+		w, err := pqarrow.NewFileWriter(rec.Schema(), f, props, pqarrow.NewArrowWriterProperties(pqarrow.WithStoreSchema()))
+		if err != nil {
+			return err
+		}
+		if err := w.Write(rec); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	case ColumnarArrow:
+		w, err := ipc.NewFileWriter(f, ipc.WithSchema(rec.Schema()))
+		if err != nil {
+			return err
+		}
+		if err := w.Write(rec); err != nil {
+			w.Close()
+			return err
+		}
+		return w.Close()
+	default:
+		return fmt.Errorf("unsupported columnar format %q", format)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
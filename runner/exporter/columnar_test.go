@@ -0,0 +1,154 @@
+package exporter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// readColumnarTable reads a table written by ColumnarDataset.Write back
+func readColumnarTable(t *testing.T, path, format string) arrow.Table {
+	t.Helper()
+	mem := memory.NewGoAllocator()
+	switch format {
+	case ColumnarParquet:
+		pf, err := file.OpenParquetFile(path, false)
+		require.NoError(t, err)
+		t.Cleanup(func() { pf.Close() })
+		reader, err := pqarrow.NewFileReader(pf, pqarrow.ArrowReadProperties{}, mem)
+		require.NoError(t, err)
+		table, err := reader.ReadTable(context.Background())
+		require.NoError(t, err)
+		return table
+	default:
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { f.Close() })
+		reader, err := ipc.NewFileReader(f, ipc.WithAllocator(mem))
+		require.NoError(t, err)
+		var records []arrow.Record
+		for i := 0; i < reader.NumRecords(); i++ {
+			rec, err := reader.Record(i)
+			require.NoError(t, err)
+			rec.Retain()
+			records = append(records, rec)
+		}
+		return array.NewTableFromRecords(reader.Schema(), records)
+	}
+}
+
+// column returns the chunk of the single-chunk column name of table
+func column(t *testing.T, table arrow.Table, name string) arrow.Array {
+	t.Helper()
+	indices := table.Schema().FieldIndices(name)
+	require.Len(t, indices, 1, "column %s", name)
+	chunks := table.Column(indices[0]).Data().Chunks()
+	require.Len(t, chunks, 1, "column %s", name)
+	return chunks[0]
+}
+
+// TestColumnarDatasetWrite tests that every table round-trips through
+// Parquet and Arrow IPC with its schema's field names, types and rows
+func TestColumnarDatasetWrite(t *testing.T) {
+	result := &types.BenchmarkResult{
+		StartTime: "2025-01-01 12:00:00",
+		EndTime:   "2025-01-01 12:05:00",
+		Duration:  "5m0s",
+		ClientMetrics: map[string]*types.ClientMetrics{
+			"reth": {
+				Version:       "reth/v1.1.0",
+				TotalRequests: 300,
+				Latency:       types.MetricSummary{P99: 40},
+				Methods:       map[string]types.MetricSummary{"eth_call": {Count: 300, P99: 40}},
+			},
+			"geth": {
+				Version:       "Geth/v1.14.0",
+				TotalRequests: 200,
+				TotalErrors:   2,
+				Latency:       types.MetricSummary{P99: 50},
+				Methods:       map[string]types.MetricSummary{"eth_call": {Count: 200, ErrorCount: 2, P99: 50}},
+				Resources: &types.ClientResources{Samples: []types.ClientResourceSample{
+					{Timestamp: 1735732800000, CPUCores: 1.5, MemoryBytes: 1 << 30},
+				}},
+			},
+		},
+		PerformanceScore: map[string]float64{"geth": 87.5},
+	}
+	run := ColumnarRun{RunID: "run-1", TestName: "mixed", GitBranch: "main", Timestamp: time.Unix(1735732800, 0)}
+	latencies := map[string]map[string]float64{"geth": {"1": 12.5, "2": 30}}
+	methods := map[string]string{"1": "eth_call", "2": "eth_getBalance"}
+
+	dataset := NewColumnarDataset()
+	defer dataset.Release()
+	dataset.AddRun(run, result, latencies, methods)
+	dir := t.TempDir()
+	require.NoError(t, dataset.Write(dir, ColumnarParquet, ColumnarArrow))
+
+	for _, format := range []string{ColumnarParquet, ColumnarArrow} {
+		t.Run(format, func(t *testing.T) {
+			for name, schema := range map[string]*arrow.Schema{
+				"runs":        runsSchema,
+				"metrics":     metricsSchema,
+				"time_series": timeSeriesSchema,
+				"requests":    requestsSchema,
+			} {
+				table := readColumnarTable(t, filepath.Join(dir, name+"."+format), format)
+				defer table.Release()
+				require.Equal(t, schema.NumFields(), int(table.NumCols()), name)
+				for i, want := range schema.Fields() {
+					got := table.Schema().Field(i)
+					assert.Equal(t, want.Name, got.Name, name)
+					assert.True(t, arrow.TypeEqual(want.Type, got.Type), "%s.%s is %s, want %s", name, want.Name, got.Type, want.Type)
+				}
+			}
+
+			runs := readColumnarTable(t, filepath.Join(dir, "runs."+format), format)
+			defer runs.Release()
+			require.EqualValues(t, 1, runs.NumRows())
+			assert.Equal(t, "run-1", column(t, runs, "run_id").(*array.String).Value(0))
+			assert.Equal(t, 300.0, column(t, runs, "duration_seconds").(*array.Float64).Value(0))
+			assert.EqualValues(t, run.Timestamp.UnixMilli(), column(t, runs, "timestamp").(*array.Timestamp).Value(0))
+
+			// Clients in name order, each with its aggregate then its methods
+			metrics := readColumnarTable(t, filepath.Join(dir, "metrics."+format), format)
+			defer metrics.Release()
+			require.EqualValues(t, 4, metrics.NumRows())
+			clients := column(t, metrics, "client").(*array.String)
+			methodCol := column(t, metrics, "method").(*array.String)
+			requests := column(t, metrics, "requests").(*array.Int64)
+			scores := column(t, metrics, "score").(*array.Float64)
+			assert.Equal(t, []string{"geth", "geth", "reth", "reth"}, []string{clients.Value(0), clients.Value(1), clients.Value(2), clients.Value(3)})
+			assert.Equal(t, []string{"all", "eth_call", "all", "eth_call"}, []string{methodCol.Value(0), methodCol.Value(1), methodCol.Value(2), methodCol.Value(3)})
+			assert.Equal(t, int64(200), requests.Value(0))
+			assert.Equal(t, 50.0, column(t, metrics, "latency_p99_ms").(*array.Float64).Value(0))
+			assert.Equal(t, 87.5, scores.Value(0))
+			assert.True(t, scores.IsNull(1), "method rows have no score")
+			assert.True(t, scores.IsNull(2), "reth has no score")
+
+			series := readColumnarTable(t, filepath.Join(dir, "time_series."+format), format)
+			defer series.Release()
+			require.EqualValues(t, 2, series.NumRows())
+			assert.Equal(t, "cpu_cores", column(t, series, "metric").(*array.String).Value(0))
+			assert.Equal(t, 1.5, column(t, series, "value").(*array.Float64).Value(0))
+
+			reqs := readColumnarTable(t, filepath.Join(dir, "requests."+format), format)
+			defer reqs.Release()
+			require.EqualValues(t, 2, reqs.NumRows())
+			assert.Equal(t, "eth_getBalance", column(t, reqs, "method").(*array.String).Value(1))
+			assert.Equal(t, 30.0, column(t, reqs, "latency_ms").(*array.Float64).Value(1))
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"time"
 
@@ -53,9 +54,32 @@ func (de *DataExporter) ExportAll(result *types.BenchmarkResult) error {
 		return fmt.Errorf("failed to export system metrics CSV: %w", err)
 	}
 
+	if err := de.ExportClientResourcesCSV(result, filepath.Join(exportDir, "client_resources.csv")); err != nil {
+		return fmt.Errorf("failed to export client resources CSV: %w", err)
+	}
+
+	if err := de.ExportErrorClassesCSV(result, filepath.Join(exportDir, "error_classes.csv")); err != nil {
+		return fmt.Errorf("failed to export error classes CSV: %w", err)
+	}
+
+	if len(result.PairedComparisons) > 0 {
+		if err := de.ExportPairedComparisonCSV(result, filepath.Join(exportDir, "paired_comparison.csv")); err != nil {
+			return fmt.Errorf("failed to export paired comparison CSV: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// ExportColumnar writes the run as Parquet and/or Arrow tables to
+// exports/columnar
+func (de *DataExporter) ExportColumnar(run ColumnarRun, result *types.BenchmarkResult, latencies map[string]map[string]float64, methods map[string]string, formats []string) error {
+	dataset := NewColumnarDataset()
+	defer dataset.Release()
+	dataset.AddRun(run, result, latencies, methods)
+	return dataset.Write(filepath.Join(de.outputDir, "exports", "columnar"), formats...)
+}
+
 // ExportJSON exports the complete result to JSON
 func (de *DataExporter) ExportJSON(result *types.BenchmarkResult, outputPath string) error {
 	file, err := os.Create(outputPath)
@@ -87,6 +111,9 @@ func (de *DataExporter) ExportMethodMetricsCSV(result *types.BenchmarkResult, ou
 		"Min (ms)", "P50 (ms)", "P75 (ms)", "P90 (ms)", "P95 (ms)", "P99 (ms)", "P99.9 (ms)", "Max (ms)",
 		"Avg (ms)", "Std Dev", "Variance", "CV (%)", "IQR", "MAD",
 		"Throughput (req/s)", "Error Count", "Timeout Rate (%)", "Connection Errors",
+		"Corrected P50 (ms)", "Corrected P95 (ms)", "Corrected P99 (ms)", "Corrected Max (ms)",
+		"Resp Size Avg (B)", "Resp Size P50 (B)", "Resp Size P95 (B)", "Resp Size P99 (B)", "Resp Size Max (B)",
+		"Bandwidth (MB/s)",
 	}
 
 	if err := writer.Write(header); err != nil {
@@ -119,6 +146,16 @@ func (de *DataExporter) ExportMethodMetricsCSV(result *types.BenchmarkResult, ou
 				strconv.FormatInt(int64(metrics.Count)-int64(metrics.SuccessRate*float64(metrics.Count)/100), 10),
 				fmt.Sprintf("%.2f", metrics.TimeoutRate),
 				strconv.FormatInt(metrics.ConnectionErrors, 10),
+				fmt.Sprintf("%.2f", metrics.CorrectedP50),
+				fmt.Sprintf("%.2f", metrics.CorrectedP95),
+				fmt.Sprintf("%.2f", metrics.CorrectedP99),
+				fmt.Sprintf("%.2f", metrics.CorrectedMax),
+				fmt.Sprintf("%.0f", metrics.ResponseBytesAvg),
+				fmt.Sprintf("%.0f", metrics.ResponseBytesP50),
+				fmt.Sprintf("%.0f", metrics.ResponseBytesP95),
+				fmt.Sprintf("%.0f", metrics.ResponseBytesP99),
+				fmt.Sprintf("%.0f", metrics.ResponseBytesMax),
+				fmt.Sprintf("%.3f", metrics.BandwidthMBps),
 			}
 
 			if err := writer.Write(row); err != nil {
@@ -146,6 +183,7 @@ func (de *DataExporter) ExportClientComparisonCSV(result *types.BenchmarkResult,
 		"Client", "Total Requests", "Total Errors", "Error Rate (%)",
 		"Avg Latency (ms)", "P95 Latency (ms)", "Performance Score",
 		"Active Connections", "Connection Reuse (%)", "DNS Resolution (ms)", "TLS Handshake (ms)",
+		"Avg Response Size (B)", "Bandwidth (MB/s)",
 	}
 
 	if err := writer.Write(header); err != nil {
@@ -171,6 +209,8 @@ func (de *DataExporter) ExportClientComparisonCSV(result *types.BenchmarkResult,
 			fmt.Sprintf("%.1f", client.ConnectionMetrics.ConnectionReuse),
 			fmt.Sprintf("%.2f", client.ConnectionMetrics.DNSResolutionTime),
 			fmt.Sprintf("%.2f", client.ConnectionMetrics.TLSHandshakeTime),
+			fmt.Sprintf("%.0f", client.Latency.ResponseBytesAvg),
+			fmt.Sprintf("%.3f", client.Latency.BandwidthMBps),
 		}
 
 		if err := writer.Write(row); err != nil {
@@ -226,7 +266,9 @@ func (de *DataExporter) ExportTimeSeriesCSV(result *types.BenchmarkResult, outpu
 	return nil
 }
 
-// ExportSystemMetricsCSV exports system resource metrics
+// ExportSystemMetricsCSV exports system resource metrics. The generator's
+// sampled series is written under client "generator"; per-client rows carry
+// the run average.
 func (de *DataExporter) ExportSystemMetricsCSV(result *types.BenchmarkResult, outputPath string) error {
 	file, err := os.Create(outputPath)
 	if err != nil {
@@ -242,33 +284,92 @@ func (de *DataExporter) ExportSystemMetricsCSV(result *types.BenchmarkResult, ou
 		"Timestamp", "Client", "CPU Usage (%)", "Memory (MB)", "Memory (%)",
 		"Network Sent (bytes)", "Network Recv (bytes)",
 		"Disk Read (bytes)", "Disk Write (bytes)",
-		"Open Connections", "Goroutines",
+		"Open Connections", "Goroutines", "Host CPU (%)",
 	}
 
 	if err := writer.Write(header); err != nil {
 		return err
 	}
 
+	writeRow := func(clientName string, index int, metrics types.SystemMetrics) error {
+		timestamp := time.UnixMilli(metrics.Timestamp)
+		if metrics.Timestamp == 0 {
+			// Estimate timestamp based on index
+			timestamp = time.Now().Add(time.Duration(index) * time.Second)
+		}
+
+		row := []string{
+			timestamp.Format(time.RFC3339),
+			clientName,
+			fmt.Sprintf("%.2f", metrics.CPUUsage),
+			fmt.Sprintf("%.2f", metrics.MemoryUsage),
+			fmt.Sprintf("%.2f", metrics.MemoryPercent),
+			strconv.FormatInt(metrics.NetworkBytesSent, 10),
+			strconv.FormatInt(metrics.NetworkBytesRecv, 10),
+			strconv.FormatInt(metrics.DiskIORead, 10),
+			strconv.FormatInt(metrics.DiskIOWrite, 10),
+			strconv.FormatInt(metrics.OpenConnections, 10),
+			strconv.Itoa(metrics.GoroutineCount),
+			fmt.Sprintf("%.2f", metrics.HostCPUPercent),
+		}
+		return writer.Write(row)
+	}
+
+	// Export the full series sampled on the generator
+	for i, metrics := range result.SystemMetrics {
+		if err := writeRow("generator", i, metrics); err != nil {
+			return err
+		}
+	}
+
 	// Export system metrics for each client
 	for clientName, client := range result.ClientMetrics {
 		for i, metrics := range client.SystemMetrics {
-			// Estimate timestamp based on index
-			timestamp := time.Now().Add(time.Duration(i) * time.Second)
+			if err := writeRow(clientName, i, metrics); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
 
+// ExportClientResourcesCSV exports the resource samples of clients running on
+// the runner's host, one row per client and interval
+func (de *DataExporter) ExportClientResourcesCSV(result *types.BenchmarkResult, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{
+		"Timestamp", "Client", "Source", "CPU (cores)", "Memory (bytes)",
+		"IO Read (bytes)", "IO Write (bytes)", "Network Recv (bytes)", "Network Sent (bytes)",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for clientName, client := range result.ClientMetrics {
+		if client.Resources == nil {
+			continue
+		}
+		for _, sample := range client.Resources.Samples {
 			row := []string{
-				timestamp.Format(time.RFC3339),
+				time.UnixMilli(sample.Timestamp).Format(time.RFC3339),
 				clientName,
-				fmt.Sprintf("%.2f", metrics.CPUUsage),
-				fmt.Sprintf("%.2f", metrics.MemoryUsage),
-				fmt.Sprintf("%.2f", metrics.MemoryPercent),
-				strconv.FormatInt(metrics.NetworkBytesSent, 10),
-				strconv.FormatInt(metrics.NetworkBytesRecv, 10),
-				strconv.FormatInt(metrics.DiskIORead, 10),
-				strconv.FormatInt(metrics.DiskIOWrite, 10),
-				strconv.FormatInt(metrics.OpenConnections, 10),
-				strconv.Itoa(metrics.GoroutineCount),
+				client.Resources.Source,
+				fmt.Sprintf("%.3f", sample.CPUCores),
+				strconv.FormatInt(sample.MemoryBytes, 10),
+				strconv.FormatInt(sample.IOReadBytes, 10),
+				strconv.FormatInt(sample.IOWriteBytes, 10),
+				strconv.FormatInt(sample.NetRecvBytes, 10),
+				strconv.FormatInt(sample.NetSentBytes, 10),
 			}
-
 			if err := writer.Write(row); err != nil {
 				return err
 			}
@@ -278,6 +379,107 @@ func (de *DataExporter) ExportSystemMetricsCSV(result *types.BenchmarkResult, ou
 	return nil
 }
 
+// ExportErrorClassesCSV exports failed request counts per client, method and
+// error class
+func (de *DataExporter) ExportErrorClassesCSV(result *types.BenchmarkResult, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{"Client", "Method", "Error Class", "Count", "Share of Requests (%)"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for clientName, client := range result.ClientMetrics {
+		for methodName, metrics := range client.Methods {
+			classes := make([]string, 0, len(metrics.ErrorClasses))
+			for class := range metrics.ErrorClasses {
+				classes = append(classes, class)
+			}
+			sort.Strings(classes)
+
+			for _, class := range classes {
+				count := metrics.ErrorClasses[class]
+				share := float64(0)
+				if metrics.Count > 0 {
+					share = float64(count) / float64(metrics.Count) * 100
+				}
+				row := []string{
+					clientName,
+					methodName,
+					class,
+					strconv.FormatInt(count, 10),
+					fmt.Sprintf("%.2f", share),
+				}
+				if err := writer.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExportPairedComparisonCSV exports request-by-request client comparisons, one
+// row per client pair and method ("all" for the cross-method row)
+func (de *DataExporter) ExportPairedComparisonCSV(result *types.BenchmarkResult, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{
+		"Client A", "Client B", "Method", "Pairs", "A Faster", "B Faster", "Ties",
+		"Ratio P10", "Ratio P50", "Ratio P90", "Ratio P99", "Ratio Geomean",
+		"Wilcoxon W", "Wilcoxon Z", "P-Value", "Winner", "Significant",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, paired := range result.PairedComparisons {
+		method := paired.Method
+		if method == "" {
+			method = "all"
+		}
+		row := []string{
+			paired.ClientA,
+			paired.ClientB,
+			method,
+			strconv.Itoa(paired.Pairs),
+			strconv.Itoa(paired.AFaster),
+			strconv.Itoa(paired.BFaster),
+			strconv.Itoa(paired.Ties),
+			fmt.Sprintf("%.3f", paired.RatioP10),
+			fmt.Sprintf("%.3f", paired.RatioP50),
+			fmt.Sprintf("%.3f", paired.RatioP90),
+			fmt.Sprintf("%.3f", paired.RatioP99),
+			fmt.Sprintf("%.3f", paired.RatioGeoMean),
+			fmt.Sprintf("%.1f", paired.WilcoxonW),
+			fmt.Sprintf("%.3f", paired.WilcoxonZ),
+			fmt.Sprintf("%.4g", paired.PValue),
+			paired.Winner,
+			strconv.FormatBool(paired.Significant),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ExportMarkdownSummary exports a markdown summary of the results
 func (de *DataExporter) ExportMarkdownSummary(result *types.BenchmarkResult, outputPath string) error {
 	file, err := os.Create(outputPath)
@@ -317,8 +519,8 @@ func (de *DataExporter) ExportMarkdownSummary(result *types.BenchmarkResult, out
 
 	// Key Metrics
 	fmt.Fprintf(file, "## Key Metrics\n\n")
-	fmt.Fprintf(file, "| Client | Requests | Success Rate | P95 Latency | Throughput |\n")
-	fmt.Fprintf(file, "|--------|----------|--------------|-------------|------------|\n")
+	fmt.Fprintf(file, "| Client | Requests | Success Rate | P95 Latency | Throughput | Bandwidth |\n")
+	fmt.Fprintf(file, "|--------|----------|--------------|-------------|------------|-----------|\n")
 
 	for name, client := range result.ClientMetrics {
 		successRate := 100.0 - client.ErrorRate
@@ -333,8 +535,90 @@ func (de *DataExporter) ExportMarkdownSummary(result *types.BenchmarkResult, out
 			avgThroughput /= float64(methodCount)
 		}
 
-		fmt.Fprintf(file, "| %s | %d | %.1f%% | %.1fms | %.1f req/s |\n",
-			name, client.TotalRequests, successRate, client.Latency.P95, avgThroughput)
+		fmt.Fprintf(file, "| %s | %d | %.1f%% | %.1fms | %.1f req/s | %.2f MB/s |\n",
+			name, client.TotalRequests, successRate, client.Latency.P95, avgThroughput, client.Latency.BandwidthMBps)
+	}
+
+	// Error breakdown
+	var withErrors []string
+	for name, client := range result.ClientMetrics {
+		if len(client.ErrorClasses) > 0 {
+			withErrors = append(withErrors, name)
+		}
+	}
+	if len(withErrors) > 0 {
+		sort.Strings(withErrors)
+		fmt.Fprintf(file, "\n## Error Breakdown\n\n")
+		fmt.Fprintf(file, "| Client | Error Class | Count |\n")
+		fmt.Fprintf(file, "|--------|-------------|-------|\n")
+		for _, name := range withErrors {
+			for _, class := range types.ErrorClasses() {
+				if count := result.ClientMetrics[name].ErrorClasses[class]; count > 0 {
+					fmt.Fprintf(file, "| %s | %s | %d |\n", name, class, count)
+				}
+			}
+		}
+	}
+
+	// Client resources
+	var withResources []string
+	for name, client := range result.ClientMetrics {
+		if client.Resources != nil && len(client.Resources.Samples) > 0 {
+			withResources = append(withResources, name)
+		}
+	}
+	if len(withResources) > 0 {
+		sort.Strings(withResources)
+		fmt.Fprintf(file, "\n## Client Resources\n\n")
+		fmt.Fprintf(file, "| Client | Source | Avg CPU | Peak CPU | Avg Memory | Peak Memory | req/s per Core | Memory per req/s |\n")
+		fmt.Fprintf(file, "|--------|--------|---------|----------|------------|-------------|----------------|------------------|\n")
+		for _, name := range withResources {
+			res := result.ClientMetrics[name].Resources
+			fmt.Fprintf(file, "| %s | %s | %.2f cores | %.2f cores | %.1f MB | %.1f MB | %.1f | %.1f KB |\n",
+				name, res.Source, res.AvgCPUCores, res.PeakCPUCores,
+				float64(res.AvgMemoryBytes)/1024/1024, float64(res.PeakMemoryBytes)/1024/1024,
+				res.RPSPerCore, res.MemoryBytesPerRPS/1024)
+		}
+	}
+
+	// Efficiency
+	if len(result.Efficiency) > 0 {
+		names := make([]string, 0, len(result.Efficiency))
+		for name := range result.Efficiency {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(file, "\n## Efficiency\n\n")
+		fmt.Fprintf(file, "| Client | Requests per CPU-second | req/s per GB | P99 × GB | req/s per Watt | Score |\n")
+		fmt.Fprintf(file, "|--------|-------------------------|--------------|----------|----------------|-------|\n")
+		for _, name := range names {
+			eff := result.Efficiency[name]
+			perWatt := "-"
+			if eff.RPSPerWatt > 0 {
+				perWatt = fmt.Sprintf("%.1f", eff.RPSPerWatt)
+			}
+			fmt.Fprintf(file, "| %s | %.1f | %.1f | %.1f ms·GB | %s | %.1f |\n",
+				name, eff.RequestsPerCPUSecond, eff.RPSPerGB, eff.P99LatencyGB, perWatt, eff.Score)
+		}
+	}
+
+	// Paired comparison
+	if len(result.PairedComparisons) > 0 {
+		fmt.Fprintf(file, "\n## Paired Comparison\n\n")
+		fmt.Fprintf(file, "| Clients | Method | Pairs | Winner | Wins | Median Ratio (A/B) | p-value |\n")
+		fmt.Fprintf(file, "|---------|--------|-------|--------|------|--------------------|---------|\n")
+		for _, paired := range result.PairedComparisons {
+			method := paired.Method
+			if method == "" {
+				method = "all"
+			}
+			winner := paired.Winner
+			if winner == "" {
+				winner = "-"
+			}
+			fmt.Fprintf(file, "| %s vs %s | %s | %d | %s | %.1f%% | %.3f | %.3g |\n",
+				paired.ClientA, paired.ClientB, method, paired.Pairs, winner, paired.WinnerShare(), paired.RatioP50, paired.PValue)
+		}
 	}
 
 	// Recommendations
@@ -0,0 +1,259 @@
+package exporter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// OTLP transport protocols
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http"
+)
+
+// otlpScope names the instrumentation scope of exported metrics and spans
+const otlpScope = "github.com/jsonrpc-bench/runner"
+
+// OTLPExporter sends benchmark results to an OpenTelemetry collector
+type OTLPExporter struct {
+	endpoint string
+	protocol string
+	testName string
+}
+
+// NewOTLPExporter creates an exporter for endpoint, a URL such as
+// http://localhost:4317 for gRPC or http://localhost:4318 for HTTP; an http
+// scheme disables TLS. testName is attached to every metric and span.
+func NewOTLPExporter(endpoint, protocol, testName string) (*OTLPExporter, error) {
+	switch protocol {
+	case OTLPProtocolGRPC, OTLPProtocolHTTP:
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q (use %s or %s)", protocol, OTLPProtocolGRPC, OTLPProtocolHTTP)
+	}
+	return &OTLPExporter{
+		endpoint: endpoint,
+		protocol: protocol,
+		testName: testName,
+	}, nil
+}
+
+// resource describes the benchmark run as the telemetry source
+func (oe *OTLPExporter) resource() *resource.Resource {
+	return resource.NewSchemaless(
+		attribute.String("service.name", "jsonrpc-bench"),
+		attribute.String("benchmark.test_name", oe.testName),
+	)
+}
+
+// ExportMetrics sends run-level and per-method metrics as gauges stamped with
+// the run's end time. Client-level points carry method "all".
+func (oe *OTLPExporter) ExportMetrics(ctx context.Context, result *types.BenchmarkResult) (err error) {
+	var exporter sdkmetric.Exporter
+	switch oe.protocol {
+	case OTLPProtocolGRPC:
+		exporter, err = otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpointURL(oe.endpoint))
+	default:
+		exporter, err = otlpmetrichttp.New(ctx, otlpmetrichttp.WithEndpointURL(oe.endpoint))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+	// Shutdown flushes the exporter, so its error is the export's error too
+	defer func() {
+		if shutdownErr := exporter.Shutdown(ctx); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("failed to shut down OTLP metric exporter: %w", shutdownErr)
+		}
+	}()
+
+	data := &metricdata.ResourceMetrics{
+		Resource: oe.resource(),
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Scope:   instrumentation.Scope{Name: otlpScope},
+			Metrics: buildOTLPMetrics(result, runEndTime(result)),
+		}},
+	}
+	if err := exporter.Export(ctx, data); err != nil {
+		return fmt.Errorf("failed to export OTLP metrics: %w", err)
+	}
+	return nil
+}
+
+// ExportSpans sends one span per sampled request under a root span covering
+// the run. Request spans end at the sample's timestamp and last its latency.
+func (oe *OTLPExporter) ExportSpans(ctx context.Context, result *types.BenchmarkResult, requests []types.SlowRequest) error {
+	var client otlptrace.Client
+	switch oe.protocol {
+	case OTLPProtocolGRPC:
+		client = otlptracegrpc.NewClient(otlptracegrpc.WithEndpointURL(oe.endpoint))
+	default:
+		client = otlptracehttp.NewClient(otlptracehttp.WithEndpointURL(oe.endpoint))
+	}
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(oe.resource()),
+	)
+	oe.recordSpans(ctx, provider.Tracer(otlpScope), result, requests)
+
+	// Shutdown flushes the batcher before closing the exporter
+	if err := provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to export OTLP spans: %w", err)
+	}
+	return nil
+}
+
+// recordSpans starts and ends the run span and its request spans on tracer.
+// Requests without a parsable timestamp are skipped.
+func (oe *OTLPExporter) recordSpans(ctx context.Context, tracer trace.Tracer, result *types.BenchmarkResult, requests []types.SlowRequest) {
+	runCtx, runSpan := tracer.Start(ctx, "benchmark "+oe.testName,
+		trace.WithTimestamp(runStartTime(result)),
+		trace.WithAttributes(attribute.Int("benchmark.sampled_requests", len(requests))),
+	)
+	for _, req := range requests {
+		end, err := time.Parse(time.RFC3339Nano, req.Timestamp)
+		if err != nil {
+			continue
+		}
+		start := end.Add(-time.Duration(req.LatencyMs * float64(time.Millisecond)))
+
+		_, span := tracer.Start(runCtx, req.RPCMethod,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithTimestamp(start),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "jsonrpc"),
+				attribute.String("rpc.method", req.RPCMethod),
+				attribute.String("benchmark.client", req.Client),
+				attribute.String("benchmark.method", req.Method),
+				attribute.String("benchmark.request_id", req.RequestID),
+				attribute.String("benchmark.payload_hash", payloadHash(req.Payload)),
+				attribute.Int("http.response.status_code", req.Status),
+				attribute.Int64("benchmark.response_bytes", req.ResponseBytes),
+			),
+		)
+		if req.Status != 200 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", req.Status))
+		}
+		span.End(trace.WithTimestamp(end))
+	}
+	runSpan.End(trace.WithTimestamp(runEndTime(result)))
+}
+
+// buildOTLPMetrics turns client and method summaries into gauges. Latency is
+// one metric with a "stat" attribute per percentile.
+func buildOTLPMetrics(result *types.BenchmarkResult, at time.Time) []metricdata.Metrics {
+	var requests, errors, errorRate, throughput, latency, score []metricdata.DataPoint[float64]
+	point := func(value float64, attrs ...attribute.KeyValue) metricdata.DataPoint[float64] {
+		return metricdata.DataPoint[float64]{Attributes: attribute.NewSet(attrs...), Time: at, Value: value}
+	}
+	addSummary := func(client, method string, summary types.MetricSummary, count, errorCount int64, rate float64) {
+		base := []attribute.KeyValue{attribute.String("client", client), attribute.String("method", method)}
+		requests = append(requests, point(float64(count), base...))
+		errors = append(errors, point(float64(errorCount), base...))
+		errorRate = append(errorRate, point(rate, base...))
+		throughput = append(throughput, point(summary.Throughput, base...))
+		for _, stat := range []struct {
+			name  string
+			value float64
+		}{
+			{"min", summary.Min}, {"avg", summary.Avg}, {"p50", summary.P50}, {"p90", summary.P90},
+			{"p95", summary.P95}, {"p99", summary.P99}, {"max", summary.Max},
+		} {
+			latency = append(latency, point(stat.value, append(base, attribute.String("stat", stat.name))...))
+		}
+	}
+
+	clients := make([]string, 0, len(result.ClientMetrics))
+	for name := range result.ClientMetrics {
+		clients = append(clients, name)
+	}
+	sort.Strings(clients)
+	for _, name := range clients {
+		client := result.ClientMetrics[name]
+		addSummary(name, "all", client.Latency, client.TotalRequests, client.TotalErrors, client.ErrorRate)
+
+		methods := make([]string, 0, len(client.Methods))
+		for method := range client.Methods {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			summary := client.Methods[method]
+			addSummary(name, method, summary, summary.Count, summary.ErrorCount, summary.ErrorRate)
+		}
+
+		if s, ok := result.PerformanceScore[name]; ok {
+			score = append(score, point(s, attribute.String("client", name)))
+		}
+	}
+
+	gauge := func(name, description, unit string, points []metricdata.DataPoint[float64]) metricdata.Metrics {
+		return metricdata.Metrics{
+			Name:        name,
+			Description: description,
+			Unit:        unit,
+			Data:        metricdata.Gauge[float64]{DataPoints: points},
+		}
+	}
+	metrics := []metricdata.Metrics{
+		gauge("benchmark.requests", "Requests sent during the run", "{request}", requests),
+		gauge("benchmark.errors", "Failed requests during the run", "{request}", errors),
+		gauge("benchmark.error_rate", "Failed requests as a share of all requests", "%", errorRate),
+		gauge("benchmark.throughput", "Requests per second", "{request}/s", throughput),
+		gauge("benchmark.latency", "Request latency by statistic", "ms", latency),
+	}
+	if len(score) > 0 {
+		metrics = append(metrics, gauge("benchmark.score", "Performance score (0-100)", "1", score))
+	}
+	return metrics
+}
+
+// payloadHash is the hex SHA-256 of a request payload, empty without one
+func payloadHash(payload []byte) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// runStartTime and runEndTime parse the run's local time.DateTime bounds,
+// falling back to now
+func runStartTime(result *types.BenchmarkResult) time.Time {
+	return parseRunTime(result.StartTime)
+}
+
+func runEndTime(result *types.BenchmarkResult) time.Time {
+	return parseRunTime(result.EndTime)
+}
+
+func parseRunTime(value string) time.Time {
+	t, err := time.ParseInLocation(time.DateTime, value, time.Local)
+	if err != nil {
+		return time.Now()
+	}
+	return t
+}
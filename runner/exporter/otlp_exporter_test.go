@@ -0,0 +1,141 @@
+package exporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// gaugePoint finds the point of the gauge named name with the given
+// attributes
+func gaugePoint(t *testing.T, metrics []metricdata.Metrics, name string, attrs ...attribute.KeyValue) (float64, bool) {
+	t.Helper()
+	want := attribute.NewSet(attrs...)
+	for _, m := range metrics {
+		if m.Name != name {
+			continue
+		}
+		gauge, ok := m.Data.(metricdata.Gauge[float64])
+		require.True(t, ok, "%s is %T, want a float64 gauge", name, m.Data)
+		for _, p := range gauge.DataPoints {
+			if p.Attributes.Equals(&want) {
+				return p.Value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// TestBuildOTLPMetrics tests the gauges built from client and method
+// summaries
+func TestBuildOTLPMetrics(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 5, 0, 0, time.UTC)
+	result := &types.BenchmarkResult{
+		ClientMetrics: map[string]*types.ClientMetrics{
+			"geth": {
+				TotalRequests: 1000,
+				TotalErrors:   10,
+				ErrorRate:     1,
+				Latency:       types.MetricSummary{P99: 250, Throughput: 200},
+				Methods: map[string]types.MetricSummary{
+					"eth_call": {Count: 400, ErrorCount: 4, P95: 90},
+				},
+			},
+			"reth": {TotalRequests: 900},
+		},
+		PerformanceScore: map[string]float64{"geth": 87.5},
+	}
+	metrics := buildOTLPMetrics(result, at)
+
+	names := make([]string, len(metrics))
+	for i, m := range metrics {
+		names[i] = m.Name
+		for _, p := range m.Data.(metricdata.Gauge[float64]).DataPoints {
+			assert.Equal(t, at, p.Time, m.Name)
+		}
+	}
+	assert.Equal(t, []string{"benchmark.requests", "benchmark.errors", "benchmark.error_rate", "benchmark.throughput", "benchmark.latency", "benchmark.score"}, names)
+
+	geth := attribute.String("client", "geth")
+	all := attribute.String("method", "all")
+	for _, tc := range []struct {
+		name  string
+		attrs []attribute.KeyValue
+		want  float64
+	}{
+		{"benchmark.requests", []attribute.KeyValue{geth, all}, 1000},
+		{"benchmark.errors", []attribute.KeyValue{geth, all}, 10},
+		{"benchmark.throughput", []attribute.KeyValue{geth, all}, 200},
+		{"benchmark.latency", []attribute.KeyValue{geth, all, attribute.String("stat", "p99")}, 250},
+		{"benchmark.requests", []attribute.KeyValue{geth, attribute.String("method", "eth_call")}, 400},
+		{"benchmark.latency", []attribute.KeyValue{geth, attribute.String("method", "eth_call"), attribute.String("stat", "p95")}, 90},
+		{"benchmark.requests", []attribute.KeyValue{attribute.String("client", "reth"), all}, 900},
+		{"benchmark.score", []attribute.KeyValue{geth}, 87.5},
+	} {
+		got, ok := gaugePoint(t, metrics, tc.name, tc.attrs...)
+		if assert.True(t, ok, "no %s point for %v", tc.name, tc.attrs) {
+			assert.Equal(t, tc.want, got, "%s %v", tc.name, tc.attrs)
+		}
+	}
+	_, ok := gaugePoint(t, metrics, "benchmark.score", attribute.String("client", "reth"))
+	assert.False(t, ok, "reth has no score")
+
+	// Without scores there is no score gauge
+	delete(result.PerformanceScore, "geth")
+	assert.Len(t, buildOTLPMetrics(result, at), 5)
+}
+
+// TestRecordSpans tests that sampled requests become client spans under the
+// run span, timed from their end timestamp and latency
+func TestRecordSpans(t *testing.T) {
+	recorder := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(recorder))
+	defer provider.Shutdown(context.Background())
+
+	oe, err := NewOTLPExporter("http://localhost:4318", OTLPProtocolHTTP, "mixed")
+	require.NoError(t, err)
+	result := &types.BenchmarkResult{StartTime: "2025-01-01 12:00:00", EndTime: "2025-01-01 12:05:00"}
+	requests := []types.SlowRequest{
+		{Client: "geth", Method: "call", RPCMethod: "eth_call", RequestID: "7", Timestamp: "2025-01-01T12:01:00.5Z", LatencyMs: 250, Status: 200, Payload: []byte(`{"id":7}`)},
+		{Client: "reth", Method: "balance", RPCMethod: "eth_getBalance", RequestID: "8", Timestamp: "2025-01-01T12:02:00Z", LatencyMs: 10, Status: 503},
+		{Client: "reth", RPCMethod: "eth_blockNumber", Timestamp: "not a time"},
+	}
+	oe.recordSpans(context.Background(), provider.Tracer(otlpScope), result, requests)
+
+	spans := recorder.GetSpans()
+	require.Len(t, spans, 3, "the run span and two request spans")
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	run := byName["benchmark mixed"]
+	assert.Equal(t, time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local), run.StartTime)
+	assert.Equal(t, time.Date(2025, 1, 1, 12, 5, 0, 0, time.Local), run.EndTime)
+	assert.Contains(t, run.Attributes, attribute.Int("benchmark.sampled_requests", 3))
+
+	call := byName["eth_call"]
+	assert.Equal(t, run.SpanContext.SpanID(), call.Parent.SpanID())
+	assert.Equal(t, run.SpanContext.TraceID(), call.SpanContext.TraceID())
+	end := time.Date(2025, 1, 1, 12, 1, 0, 500_000_000, time.UTC)
+	assert.True(t, call.EndTime.Equal(end))
+	assert.True(t, call.StartTime.Equal(end.Add(-250*time.Millisecond)))
+	assert.Contains(t, call.Attributes, attribute.String("benchmark.client", "geth"))
+	assert.Contains(t, call.Attributes, attribute.String("benchmark.payload_hash", payloadHash([]byte(`{"id":7}`))))
+	assert.Equal(t, codes.Unset, call.Status.Code)
+
+	failed := byName["eth_getBalance"]
+	assert.Equal(t, codes.Error, failed.Status.Code)
+	assert.Equal(t, "HTTP 503", failed.Status.Description)
+	assert.Contains(t, failed.Attributes, attribute.Int("http.response.status_code", 503))
+}
@@ -0,0 +1,244 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// RemoteWriteLabels identify a run on every pushed series
+type RemoteWriteLabels struct {
+	TestID    string
+	RunID     string
+	GitCommit string
+}
+
+// RemoteWriteExporter pushes a finished run's summary to a Prometheus
+// remote-write endpoint such as Prometheus, Mimir or VictoriaMetrics
+type RemoteWriteExporter struct {
+	endpoint string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewRemoteWriteExporter creates an exporter for endpoint, the full
+// remote-write URL. Basic auth is sent when username is set.
+func NewRemoteWriteExporter(endpoint, username, password string) *RemoteWriteExporter {
+	return &RemoteWriteExporter{
+		endpoint: endpoint,
+		username: username,
+		password: password,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Export converts result into series and pushes them in one request
+func (rw *RemoteWriteExporter) Export(ctx context.Context, result *types.BenchmarkResult, labels RemoteWriteLabels) error {
+	series := buildRemoteWriteSeries(result, labels)
+	if len(series) == 0 {
+		return nil
+	}
+	body := snappy.Encode(nil, encodeWriteRequest(series))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rw.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote-write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if rw.username != "" {
+		req.SetBasicAuth(rw.username, rw.password)
+	}
+
+	resp, err := rw.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("remote-write request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote-write failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// remoteLabel and remoteSample mirror prometheus.Label and prometheus.Sample
+type remoteLabel struct {
+	name  string
+	value string
+}
+
+type remoteSample struct {
+	value     float64
+	timestamp int64 // Unix ms
+}
+
+type remoteSeries struct {
+	labels  []remoteLabel
+	samples []remoteSample
+}
+
+// seriesBuilder collects samples by label set
+type seriesBuilder struct {
+	base   []remoteLabel
+	series map[string]*remoteSeries
+}
+
+// add appends a sample to the series named name with the base labels plus
+// extra name/value pairs. Empty label values are dropped, as Prometheus does.
+func (b *seriesBuilder) add(name string, value float64, timestamp int64, extra ...string) {
+	labels := append([]remoteLabel{{"__name__", name}}, b.base...)
+	for i := 0; i+1 < len(extra); i += 2 {
+		labels = append(labels, remoteLabel{extra[i], extra[i+1]})
+	}
+	kept := labels[:0]
+	for _, l := range labels {
+		if l.value != "" {
+			kept = append(kept, l)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].name < kept[j].name })
+
+	var key strings.Builder
+	for _, l := range kept {
+		key.WriteString(l.name)
+		key.WriteByte(0)
+		key.WriteString(l.value)
+		key.WriteByte(0)
+	}
+	s, ok := b.series[key.String()]
+	if !ok {
+		s = &remoteSeries{labels: kept}
+		b.series[key.String()] = s
+	}
+	s.samples = append(s.samples, remoteSample{value: value, timestamp: timestamp})
+}
+
+// addRange stamps a summary value at the start and end of the run, so range
+// queries over the run see it throughout
+func (b *seriesBuilder) addRange(name string, value float64, start, end int64, extra ...string) {
+	b.add(name, value, start, extra...)
+	if end > start {
+		b.add(name, value, end, extra...)
+	}
+}
+
+// buildRemoteWriteSeries converts per-client and per-method summaries into
+// series covering the run, and the generator and client resource samples
+// into series at their own timestamps. Client-level series carry method
+// "all". Series are sorted by labels and samples by time.
+func buildRemoteWriteSeries(result *types.BenchmarkResult, labels RemoteWriteLabels) []remoteSeries {
+	b := &seriesBuilder{
+		base: []remoteLabel{
+			{"testid", labels.TestID},
+			{"run_id", labels.RunID},
+			{"git_commit", labels.GitCommit},
+		},
+		series: make(map[string]*remoteSeries),
+	}
+	start, end := runStartTime(result).UnixMilli(), runEndTime(result).UnixMilli()
+
+	addSummary := func(client, version, method string, summary types.MetricSummary, count, errorCount int64, rate float64) {
+		ls := []string{"client", client, "client_version", version, "method", method}
+		b.addRange("jsonrpc_bench_run_requests", float64(count), start, end, ls...)
+		b.addRange("jsonrpc_bench_run_errors", float64(errorCount), start, end, ls...)
+		b.addRange("jsonrpc_bench_run_error_rate_percent", rate, start, end, ls...)
+		b.addRange("jsonrpc_bench_run_requests_per_second", summary.Throughput, start, end, ls...)
+		b.addRange("jsonrpc_bench_run_latency_avg_seconds", summary.Avg/1000, start, end, ls...)
+		// Quantiles 0 and 1 are min and max, as in Prometheus summaries
+		for _, q := range []struct {
+			quantile string
+			value    float64
+		}{
+			{"0", summary.Min}, {"0.5", summary.P50}, {"0.9", summary.P90},
+			{"0.95", summary.P95}, {"0.99", summary.P99}, {"1", summary.Max},
+		} {
+			b.addRange("jsonrpc_bench_run_latency_seconds", q.value/1000, start, end, append(ls, "quantile", q.quantile)...)
+		}
+	}
+
+	for name, client := range result.ClientMetrics {
+		addSummary(name, client.Version, "all", client.Latency, client.TotalRequests, client.TotalErrors, client.ErrorRate)
+		for method, summary := range client.Methods {
+			addSummary(name, client.Version, method, summary, summary.Count, summary.ErrorCount, summary.ErrorRate)
+		}
+		if score, ok := result.PerformanceScore[name]; ok {
+			b.addRange("jsonrpc_bench_run_score", score, start, end, "client", name, "client_version", client.Version)
+		}
+
+		if client.Resources != nil {
+			for _, sample := range client.Resources.Samples {
+				ls := []string{"client", name, "client_version", client.Version}
+				b.add("jsonrpc_bench_run_client_cpu_cores", sample.CPUCores, sample.Timestamp, ls...)
+				b.add("jsonrpc_bench_run_client_memory_bytes", float64(sample.MemoryBytes), sample.Timestamp, ls...)
+				if sample.Watts > 0 {
+					b.add("jsonrpc_bench_run_client_watts", sample.Watts, sample.Timestamp, ls...)
+				}
+			}
+		}
+	}
+
+	for _, sample := range result.SystemMetrics {
+		if sample.Timestamp == 0 {
+			continue
+		}
+		b.add("jsonrpc_bench_run_generator_host_cpu_percent", sample.HostCPUPercent, sample.Timestamp)
+		b.add("jsonrpc_bench_run_generator_process_cpu_percent", sample.CPUUsage, sample.Timestamp)
+		b.add("jsonrpc_bench_run_generator_memory_bytes", sample.MemoryUsage*1024*1024, sample.Timestamp)
+	}
+
+	keys := make([]string, 0, len(b.series))
+	for key := range b.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	series := make([]remoteSeries, 0, len(keys))
+	for _, key := range keys {
+		s := b.series[key]
+		sort.SliceStable(s.samples, func(i, j int) bool { return s.samples[i].timestamp < s.samples[j].timestamp })
+		series = append(series, *s)
+	}
+	return series
+}
+
+// encodeWriteRequest marshals series as a prometheus.WriteRequest protobuf
+func encodeWriteRequest(series []remoteSeries) []byte {
+	var out []byte
+	for _, s := range series {
+		var ts []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l.name)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l.value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		for _, sample := range s.samples {
+			var smp []byte
+			smp = protowire.AppendTag(smp, 1, protowire.Fixed64Type)
+			smp = protowire.AppendFixed64(smp, math.Float64bits(sample.value))
+			smp = protowire.AppendTag(smp, 2, protowire.VarintType)
+			smp = protowire.AppendVarint(smp, uint64(sample.timestamp))
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, smp)
+		}
+		out = protowire.AppendTag(out, 1, protowire.BytesType)
+		out = protowire.AppendBytes(out, ts)
+	}
+	return out
+}
@@ -0,0 +1,98 @@
+package exporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// TestRemoteWriteExport tests that the pushed body is a snappy-compressed
+// prometheus.WriteRequest with sorted labels and the run's values
+func TestRemoteWriteExport(t *testing.T) {
+	var (
+		body    []byte
+		headers http.Header
+		user    string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		user, _, _ = r.BasicAuth()
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	result := &types.BenchmarkResult{
+		StartTime: "2025-01-01 12:00:00",
+		EndTime:   "2025-01-01 12:05:00",
+		ClientMetrics: map[string]*types.ClientMetrics{
+			"geth": {
+				Name:          "geth",
+				Version:       "Geth/v1.14.0",
+				TotalRequests: 1000,
+				TotalErrors:   5,
+				Latency:       types.MetricSummary{P99: 250},
+				Methods: map[string]types.MetricSummary{
+					"eth_call": {Count: 400, P99: 120},
+				},
+			},
+		},
+	}
+	exporter := NewRemoteWriteExporter(server.URL, "bench", "secret")
+	require.NoError(t, exporter.Export(context.Background(), result, RemoteWriteLabels{TestID: "mixed", RunID: "run-1"}))
+
+	assert.Equal(t, "snappy", headers.Get("Content-Encoding"))
+	assert.Equal(t, "application/x-protobuf", headers.Get("Content-Type"))
+	assert.Equal(t, "bench", user)
+
+	decoded, err := snappy.Decode(nil, body)
+	require.NoError(t, err)
+	var req prompb.WriteRequest
+	require.NoError(t, req.Unmarshal(decoded))
+	require.NotEmpty(t, req.Timeseries)
+
+	series := make(map[string]prompb.TimeSeries)
+	for _, ts := range req.Timeseries {
+		require.NotEmpty(t, ts.Labels)
+		assert.Equal(t, "__name__", ts.Labels[0].Name)
+		assert.True(t, sort.SliceIsSorted(ts.Labels, func(i, j int) bool { return ts.Labels[i].Name < ts.Labels[j].Name }),
+			"labels of %v are not sorted", ts.Labels)
+
+		labels := make(map[string]string, len(ts.Labels))
+		for _, l := range ts.Labels {
+			labels[l.Name] = l.Value
+		}
+		assert.Equal(t, "mixed", labels["testid"])
+		assert.Equal(t, "run-1", labels["run_id"])
+		_, hasCommit := labels["git_commit"]
+		assert.False(t, hasCommit, "empty label values are dropped")
+		series[labels["__name__"]+"/"+labels["method"]+"/"+labels["quantile"]] = ts
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local).UnixMilli()
+	end := time.Date(2025, 1, 1, 12, 5, 0, 0, time.Local).UnixMilli()
+	for key, want := range map[string]float64{
+		"jsonrpc_bench_run_requests/all/":                 1000,
+		"jsonrpc_bench_run_errors/all/":                   5,
+		"jsonrpc_bench_run_latency_seconds/all/0.99":      0.25,
+		"jsonrpc_bench_run_requests/eth_call/":            400,
+		"jsonrpc_bench_run_latency_seconds/eth_call/0.99": 0.12,
+	} {
+		ts, ok := series[key]
+		require.True(t, ok, "missing series %s", key)
+		require.Len(t, ts.Samples, 2, key)
+		assert.Equal(t, start, ts.Samples[0].Timestamp, key)
+		assert.Equal(t, end, ts.Samples[1].Timestamp, key)
+		assert.InDelta(t, want, ts.Samples[0].Value, 1e-9, key)
+	}
+}
@@ -288,6 +288,9 @@ const EnhancedHTMLReportTemplate = `
                                 <th>Avg (ms)</th>
                                 <th>Error Rate</th>
                                 <th>Throughput (req/s)</th>
+                                <th>Resp Avg</th>
+                                <th>Resp P99</th>
+                                <th>Bandwidth (MB/s)</th>
                             </tr>
                         </thead>
                         <tbody>
@@ -312,6 +315,9 @@ const EnhancedHTMLReportTemplate = `
                                     {{end}}
                                 </td>
                                 <td class="metric-cell">{{printf "%.2f" $metrics.Throughput}}</td>
+                                <td class="metric-cell">{{bytes $metrics.ResponseBytesAvg}}</td>
+                                <td class="metric-cell">{{bytes $metrics.ResponseBytesP99}}</td>
+                                <td class="metric-cell">{{printf "%.2f" $metrics.BandwidthMBps}}</td>
                             </tr>
                             {{end}}
                         </tbody>
@@ -668,6 +674,7 @@ func GenerateEnhancedHTMLReport(cfg *config.Config, result *types.BenchmarkResul
 	// Create template with custom functions
 	funcMap := template.FuncMap{
 		"printf": fmt.Sprintf,
+		"bytes":  formatBytes,
 	}
 
 	tmpl, err := template.New("report").Funcs(funcMap).Parse(EnhancedHTMLReportTemplate)
@@ -12,6 +12,7 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jsonrpc-bench/runner/config"
@@ -23,8 +24,22 @@ const (
 	K6ScriptFilename   = "k6-script.js"
 	K6ConfigFilename   = "config.json"
 	K6RequestsFilename = "requests.csv"
+	// K6RequestStreamFilename is the gzipped k6 JSON output written when
+	// request capture is enabled
+	K6RequestStreamFilename = "requests-stream.json.gz"
 
 	ReqsCountThresholdFactor = 0.1
+
+	// K6CorrectedDurationMetric is the custom k6 Trend the script records
+	// coordinated-omission-corrected latency into under constant-arrival-rate
+	// scenarios.
+	K6CorrectedDurationMetric = "rpc_corrected_duration"
+	// K6ResponseBytesMetric is the custom k6 Trend holding the response body
+	// size of every request.
+	K6ResponseBytesMetric = "rpc_response_bytes"
+	// K6ErrorsMetric is the custom k6 Counter the script increments for every
+	// failed request, tagged with its error_class.
+	K6ErrorsMetric = "rpc_errors"
 )
 
 // K6Script is the script file content to be used for running k6 tests
@@ -64,6 +79,7 @@ func GenerateK6Config(cfg *config.Config, outputDir string) (string, error) {
 				"testid": cfg.TestName,
 			},
 		},
+		ErrorTaxonomy: types.NewErrorTaxonomy(),
 	}
 
 	// Add thresholds to config
@@ -91,7 +107,8 @@ func GenerateK6Config(cfg *config.Config, outputDir string) (string, error) {
 	// is defined on it. These conditions can never fail, so they never affect the
 	// k6 exit code. Keys are UNQUOTED and use {scenario:C,req_name:M} ordering
 	// because k6 stores the submetric name verbatim and metrics/summary_fallback.go
-	// (lookupSubmetric) matches that exact string. Grows as clients x methods x 3.
+	// (lookupSubmetric) matches that exact string. Grows as clients x methods x 4
+	// (x 5 under constant-arrival-rate, which adds the corrected-latency trend).
 	for _, client := range cfg.ResolvedClients {
 		for _, call := range cfg.Calls {
 			identifier := call.Name
@@ -102,6 +119,28 @@ func GenerateK6Config(cfg *config.Config, outputDir string) (string, error) {
 			config.Options.Thresholds["http_req_duration"+selector] = []string{"max>=0"}
 			config.Options.Thresholds["http_reqs"+selector] = []string{"count>=0"}
 			config.Options.Thresholds["http_req_failed"+selector] = []string{"rate>=0"}
+			config.Options.Thresholds[K6ResponseBytesMetric+selector] = []string{"max>=0"}
+			if cfg.RPS > 0 {
+				config.Options.Thresholds[K6CorrectedDurationMetric+selector] = []string{"max>=0"}
+			}
+		}
+	}
+
+	// Same trick for the error counter, one submetric per error class so
+	// summary.json carries the breakdown without Prometheus. Keys use
+	// {scenario:C,req_name:M,error_class:E} ordering, matched by
+	// metrics/summary_fallback.go (lookupErrorClassSubmetric). This is the
+	// largest group: clients x methods x len(types.ErrorClasses()).
+	for _, client := range cfg.ResolvedClients {
+		for _, call := range cfg.Calls {
+			identifier := call.Name
+			if identifier == "" {
+				identifier = call.Method
+			}
+			for _, class := range types.ErrorClasses() {
+				selector := fmt.Sprintf("{scenario:%s,req_name:%s,error_class:%s}", client.Name, identifier, class)
+				config.Options.Thresholds[K6ErrorsMetric+selector] = []string{"count>=0"}
+			}
 		}
 	}
 
@@ -118,6 +157,10 @@ func GenerateK6Config(cfg *config.Config, outputDir string) (string, error) {
 					Executor: types.K6ScenarioExecutorConstantArrivalRate,
 					Env: map[string]string{
 						"RPC_CLIENT_ENDPOINT": client.URL,
+						// Spacing between scheduled iteration starts; the
+						// script derives each request's intended start time
+						// from it to correct for coordinated omission.
+						"RPC_ARRIVAL_INTERVAL_MS": strconv.FormatFloat(1000.0/float64(cfg.RPS), 'f', -1, 64),
 					},
 					Tags: tags,
 				},
@@ -168,7 +211,9 @@ func GenerateK6Config(cfg *config.Config, outputDir string) (string, error) {
 	return absPath, nil
 }
 
-// GenerateK6Requests generates the k6 requests file and returns the path to the file
+// GenerateK6Requests generates the k6 requests file and returns the path to the file.
+// Requests are drawn from cfg.Seed; a zero seed is replaced by a random one
+// and recorded on cfg so the run's manifest can reproduce it.
 func GenerateK6Requests(cfg *config.Config, outputDir string) (string, error) {
 	requestsPath := path.Join(outputDir, K6RequestsFilename)
 
@@ -180,6 +225,11 @@ func GenerateK6Requests(cfg *config.Config, outputDir string) (string, error) {
 
 	writer := csv.NewWriter(requestsFile)
 
+	for cfg.Seed == 0 {
+		cfg.Seed = rand.Int63()
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
 	// Generate requests
 	reqsCount := 1
 
@@ -201,14 +251,14 @@ func GenerateK6Requests(cfg *config.Config, outputDir string) (string, error) {
 		maxRequests = cfg.Iterations
 	}
 	for reqsCount <= maxRequests {
-		reqRand := rand.Float64() * float64(totalWeight)
+		reqRand := rng.Float64() * float64(totalWeight)
 		cumFreq := 0.0
 		for _, call := range cfg.Calls {
 			cumFreq += float64(call.Weight)
 			if reqRand < cumFreq {
 				id := reqsCount
 
-				rpcCall, err := call.Sample()
+				rpcCall, err := call.Sample(rng)
 				if err != nil {
 					return "", fmt.Errorf("failed to sample call %s: %w", call.Name, err)
 				}
@@ -283,6 +333,21 @@ func GenerateK6Cmd(
 	return cmd, summaryPath, nil
 }
 
+// K6Version returns the version `k6 version` reports, e.g. "v0.52.0", or ""
+// when k6 cannot be run
+func K6Version() string {
+	output, err := exec.Command(K6CommandDefault, "version").Output()
+	if err != nil {
+		return ""
+	}
+	// "k6 v0.52.0 (go1.22.4, linux/amd64)" followed by extension lines
+	fields := strings.Fields(string(output))
+	if len(fields) < 2 || fields[0] != "k6" {
+		return ""
+	}
+	return fields[1]
+}
+
 // configureOutputs configures the outputs for the k6 command
 func configureOutputs(cfg *config.Config, cmd *exec.Cmd) *exec.Cmd {
 	if cfg.Outputs != nil && cfg.Outputs.PrometheusRW != nil {
@@ -290,6 +355,8 @@ func configureOutputs(cfg *config.Config, cmd *exec.Cmd) *exec.Cmd {
 		cmd.Env = append(cmd.Env,
 			fmt.Sprintf("K6_PROMETHEUS_RW_SERVER_URL=%s", cfg.Outputs.PrometheusRW.Endpoint),
 			"K6_PROMETHEUS_RW_TREND_STATS=min,max,avg,med,p(90),p(95),p(99)",
+			// One push per second gives the latency timeline 1s resolution
+			"K6_PROMETHEUS_RW_PUSH_INTERVAL=1s",
 		)
 		if cfg.Outputs.PrometheusRW.BasicAuth.Username != "" {
 			cmd.Env = append(cmd.Env, fmt.Sprintf("K6_PROMETHEUS_RW_USERNAME=%s", cfg.Outputs.PrometheusRW.BasicAuth.Username))
@@ -298,6 +365,10 @@ func configureOutputs(cfg *config.Config, cmd *exec.Cmd) *exec.Cmd {
 			cmd.Env = append(cmd.Env, fmt.Sprintf("K6_PROMETHEUS_RW_PASSWORD=%s", cfg.Outputs.PrometheusRW.BasicAuth.Password))
 		}
 	}
+	if cfg.Outputs != nil && cfg.Outputs.RequestStream != nil {
+		// k6 gzips the JSON output when the file name ends in .gz
+		cmd.Args = append(cmd.Args, "--out", fmt.Sprintf("json=%s", cfg.Outputs.RequestStream.Path))
+	}
 
 	return cmd
 }
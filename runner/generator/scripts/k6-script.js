@@ -3,6 +3,7 @@ import exec from 'k6/execution';
 import fs from 'k6/experimental/fs';
 import csv from 'k6/experimental/csv';
 import { group, check } from 'k6';
+import { Trend, Counter } from 'k6/metrics';
 
 // --- Requests files ---
 const requestsFilePath = __ENV.RPC_REQUESTS_FILE_PATH;
@@ -18,6 +19,87 @@ const config = JSON.parse(configFile);
 
 export const options = config["options"]
 
+// --- Coordinated omission ---
+// Under constant-arrival-rate each iteration has a scheduled start time. When
+// the generator falls behind, requests go out late and http_req_duration
+// hides the queueing delay, so we also record latency from the scheduled start.
+const arrivalIntervalMs = parseFloat(__ENV.RPC_ARRIVAL_INTERVAL_MS || "0");
+const correctedDuration = new Trend('rpc_corrected_duration', true);
+
+// --- Response size ---
+// Bytes of response body per request, tagged like http_req_duration so sizes
+// can be broken down per client and method. The size is read from the
+// Content-Length header. Only a response without one (chunked) or with a
+// compressed body, whose header counts the compressed bytes, is measured from
+// the body. k6 decodes that into a string whose length counts UTF-16 code
+// units, so it is re-measured as UTF-8 bytes.
+const responseBytes = new Trend('rpc_response_bytes');
+
+function responseByteLength(response) {
+  if (!response.body) {
+    return 0;
+  }
+  const headers = response.headers || {};
+  const contentLength = parseInt(headers['Content-Length'], 10);
+  if (!headers['Content-Encoding'] && contentLength >= 0) {
+    return contentLength;
+  }
+  return utf8ByteLength(response.body);
+}
+
+function utf8ByteLength(str) {
+  let bytes = 0;
+  for (let i = 0; i < str.length; i++) {
+    const code = str.charCodeAt(i);
+    if (code < 0x80) {
+      bytes += 1;
+    } else if (code < 0x800) {
+      bytes += 2;
+    } else if (code >= 0xd800 && code <= 0xdbff && i + 1 < str.length) {
+      // A surrogate pair is one code point of four bytes
+      bytes += 4;
+      i++;
+    } else {
+      bytes += 3;
+    }
+  }
+  return bytes;
+}
+
+// --- Error taxonomy ---
+// Every failed request is counted once under an error_class: transport
+// (k6 error codes), HTTP (non-200 statuses) or JSON-RPC (error.code). The
+// code tables come from the runner (types.NewErrorTaxonomy) so both sides
+// use the same class names.
+const taxonomy = config["error_taxonomy"] || { transport_codes: {}, rpc_codes: {} };
+const rpcErrors = new Counter('rpc_errors');
+
+function classifyFailure(response) {
+  if (response.status === 0) {
+    return taxonomy.transport_codes[String(response.error_code)] || 'transport_other';
+  }
+  if (response.status !== 200) {
+    if (response.status === 429) {
+      return 'http_429';
+    }
+    return response.status >= 500 ? 'http_5xx' : 'http_other';
+  }
+  let data;
+  try {
+    data = response.json();
+  } catch (e) {
+    return 'invalid_response';
+  }
+  if (data && data.error !== undefined) {
+    const code = data.error && data.error.code;
+    return taxonomy.rpc_codes[String(code)] || 'rpc_other';
+  }
+  if (!data || data.result === undefined) {
+    return 'invalid_response';
+  }
+  return null;
+}
+
 export default async function () {
   const rpcEndpoint = __ENV.RPC_CLIENT_ENDPOINT;
   
@@ -28,7 +110,10 @@ export default async function () {
 
   const requestData = requestsData[idx];
 
-  // const reqId = requestData[0];
+  // Request ids travel as metadata rather than tags: they are unique per
+  // request and would explode Prometheus series, but the JSON request
+  // stream keeps them so single requests can be traced back to requests.csv.
+  exec.vu.metrics.metadata["req_id"] = requestData[0];
   const reqName = requestData[1];
   const reqMethod = requestData[2];
   const payload = requestData[3];
@@ -42,10 +127,21 @@ export default async function () {
     }
 
     group(reqName, function() {
+      const sentAt = Date.now();
       const response = http.post(rpcEndpoint, payload, {
         headers: headers,
         tags: tags,
       });
+      responseBytes.add(responseByteLength(response), tags);
+      const errorClass = classifyFailure(response);
+      if (errorClass) {
+        rpcErrors.add(1, Object.assign({ "error_class": errorClass }, tags));
+      }
+      if (arrivalIntervalMs > 0) {
+        const intendedStart = exec.scenario.startTime + idx * arrivalIntervalMs;
+        const queued = Math.max(0, sentAt - intendedStart);
+        correctedDuration.add(queued + response.timings.duration, tags);
+      }
       // Checks
       check(response, {
         'status_200': (r) => r.status === 200,
@@ -1,8 +1,11 @@
 package generator
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"text/template"
 	"time"
 
@@ -430,6 +433,19 @@ const UltimateHTMLReportTemplate = `
                         <span class="env-value">{{printf "%.1f" $client.ConnectionMetrics.ConnectionReuse}}%</span>
                     </div>
                 </div>
+
+                <!-- Error Breakdown -->
+                {{if $client.ErrorClasses}}
+                <h4>Errors by Class</h4>
+                <div class="environment-info" style="margin: 10px 0 20px 0;">
+                    {{range $class, $count := $client.ErrorClasses}}
+                    <div class="env-item">
+                        <span class="env-label">{{$class}}</span>
+                        <span class="env-value">{{$count}}</span>
+                    </div>
+                    {{end}}
+                </div>
+                {{end}}
                 
                 <!-- Method Performance Table -->
                 <table>
@@ -452,6 +468,9 @@ const UltimateHTMLReportTemplate = `
                                 CV
                                 <span class="tooltiptext">Coefficient of Variation - Lower is better</span>
                             </th>
+                            <th>Resp P50</th>
+                            <th>Resp P99</th>
+                            <th>MB/s</th>
                         </tr>
                     </thead>
                     <tbody>
@@ -479,6 +498,9 @@ const UltimateHTMLReportTemplate = `
                             <td>{{printf "%.1f" $metrics.Max}}</td>
                             <td>{{printf "%.1f" $metrics.StdDev}}</td>
                             <td>{{printf "%.1f" $metrics.CoeffVar}}%</td>
+                            <td>{{bytes $metrics.ResponseBytesP50}}</td>
+                            <td>{{bytes $metrics.ResponseBytesP99}}</td>
+                            <td>{{printf "%.2f" $metrics.BandwidthMBps}}</td>
                         </tr>
                         {{end}}
                     </tbody>
@@ -511,6 +533,10 @@ const UltimateHTMLReportTemplate = `
                     <span class="env-label">Target RPS</span>
                     <span class="env-value">{{.RPS}}</span>
                 </div>
+                <div class="env-item">
+                    <span class="env-label">Latency</span>
+                    <span class="env-value">{{if eq .LatencyMode "corrected"}}corrected for coordinated omission{{else}}raw (from send time){{end}}</span>
+                </div>
                 <div class="env-item">
                     <span class="env-label">Test Started</span>
                     <span class="env-value">{{.StartTime}}</span>
@@ -543,6 +569,81 @@ const UltimateHTMLReportTemplate = `
         </div>
         {{end}}
         
+        <!-- Latency vs Generator Resources -->
+        {{if .TimelineDatasets}}
+        <div class="chart-section">
+            <h2 class="chart-title">Latency vs Generator Resources</h2>
+            <div style="position: relative; height: 360px;">
+                <canvas id="resourceTimelineChart"></canvas>
+            </div>
+            {{if .ResourceCorrelations}}
+            <table style="margin-top: 20px;">
+                <thead>
+                    <tr>
+                        <th>Client</th>
+                        <th>Resource</th>
+                        <th class="tooltip">
+                            Pearson r
+                            <span class="tooltiptext">Correlation between mean latency and the resource per interval</span>
+                        </th>
+                        <th>Latency Spikes</th>
+                        <th>During Saturation</th>
+                        <th>Flagged</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .ResourceCorrelations}}
+                    <tr>
+                        <td>{{.Client}}</td>
+                        <td>{{.Resource}}</td>
+                        <td>{{printf "%.2f" .Pearson}}</td>
+                        <td>{{.Spikes}}</td>
+                        <td>{{.SaturatedSpikes}}</td>
+                        <td>{{if .Flagged}}<strong>yes</strong>{{else}}no{{end}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+            {{end}}
+        </div>
+        {{end}}
+
+        <!-- Paired Comparison -->
+        {{if .PairedComparisons}}
+        <div class="chart-section">
+            <h2 class="chart-title">Paired Comparison (identical requests)</h2>
+            <table>
+                <thead>
+                    <tr>
+                        <th>Clients</th>
+                        <th>Method</th>
+                        <th>Pairs</th>
+                        <th>Winner</th>
+                        <th>Wins</th>
+                        <th class="tooltip">
+                            Ratio P10 / P50 / P90
+                            <span class="tooltiptext">Client A latency divided by client B latency on the same payload</span>
+                        </th>
+                        <th>p-value</th>
+                    </tr>
+                </thead>
+                <tbody>
+                    {{range .PairedComparisons}}
+                    <tr>
+                        <td>{{.ClientA}} vs {{.ClientB}}</td>
+                        <td>{{if .Method}}{{.Method}}{{else}}<strong>all</strong>{{end}}</td>
+                        <td>{{.Pairs}}</td>
+                        <td>{{if .Winner}}{{.Winner}}{{if .Significant}} <span class="winner-badge">significant</span>{{end}}{{else}}-{{end}}</td>
+                        <td>{{printf "%.1f" .WinnerShare}}%</td>
+                        <td>{{printf "%.2f" .RatioP10}} / {{printf "%.2f" .RatioP50}} / {{printf "%.2f" .RatioP90}}</td>
+                        <td>{{printf "%.3g" .PValue}}</td>
+                    </tr>
+                    {{end}}
+                </tbody>
+            </table>
+        </div>
+        {{end}}
+
         <!-- Recommendations -->
         {{if .Recommendations}}
         <div class="chart-section">
@@ -578,6 +679,26 @@ const UltimateHTMLReportTemplate = `
             document.getElementById('tab-' + tabName).classList.add('active');
             element.classList.add('active');
         }
+        {{if .TimelineDatasets}}
+
+        // Latency timeline overlaid with generator resources
+        new Chart(document.getElementById('resourceTimelineChart').getContext('2d'), {
+            type: 'line',
+            data: { datasets: {{.TimelineDatasets}} },
+            options: {
+                responsive: true,
+                maintainAspectRatio: false,
+                parsing: false,
+                pointRadius: 0,
+                interaction: { mode: 'nearest', axis: 'x', intersect: false },
+                scales: {
+                    x: { type: 'linear', title: { display: true, text: 'Seconds since start' } },
+                    latency: { type: 'linear', position: 'left', beginAtZero: true, title: { display: true, text: 'Mean latency (ms)' } },
+                    percent: { type: 'linear', position: 'right', min: 0, max: 100, grid: { drawOnChartArea: false }, title: { display: true, text: 'Generator CPU / memory (%)' } }
+                }
+            }
+        });
+        {{end}}
     </script>
 </body>
 </html>
@@ -592,6 +713,7 @@ type UltimateReportData struct {
 	StartTime   string
 	Duration    string
 	RPS         int
+	LatencyMode string
 
 	// Summary metrics
 	TotalRequests      int64
@@ -610,6 +732,14 @@ type UltimateReportData struct {
 	PerformanceScore map[string]float64
 	Recommendations  []string
 
+	// Request-by-request comparison, present when requests were captured
+	PairedComparisons []types.PairedComparison
+
+	// Latency timelines overlaid with the generator resource series, as
+	// Chart.js datasets in JSON; empty without timelines
+	TimelineDatasets     string
+	ResourceCorrelations []types.ResourceCorrelation
+
 	// Client data
 	ClientMetrics []*types.ClientMetrics
 	ClientNames   []string
@@ -632,6 +762,7 @@ func GenerateUltimateHTMLReport(cfg *config.Config, result *types.BenchmarkResul
 		"sub": func(a, b float64) float64 {
 			return a - b
 		},
+		"bytes": formatBytes,
 	}
 
 	tmpl, err := template.New("report").Funcs(funcMap).Parse(UltimateHTMLReportTemplate)
@@ -662,11 +793,15 @@ func prepareUltimateReportData(cfg *config.Config, result *types.BenchmarkResult
 		StartTime:        result.StartTime,
 		Duration:         result.Duration,
 		RPS:              cfg.RPS,
+		LatencyMode:      string(result.LatencyMode),
 		Environment:      result.Environment,
 		Comparison:       result.Comparison,
 		PerformanceScore: result.PerformanceScore,
 		Recommendations:  result.Recommendations,
 		EndpointNames:    make(map[string]string),
+
+		PairedComparisons:    result.PairedComparisons,
+		ResourceCorrelations: result.ResourceCorrelations,
 	}
 
 	// Populate methods names from config
@@ -735,5 +870,100 @@ func prepareUltimateReportData(cfg *config.Config, result *types.BenchmarkResult
 		"'rgba(255, 159, 64, 0.2)'",
 	}
 
+	data.TimelineDatasets = buildTimelineDatasets(result, data.ChartColors)
+
 	return data
 }
+
+// timelinePoint is a Chart.js {x, y} point
+type timelinePoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// timelineDataset is a Chart.js line dataset bound to one y axis
+type timelineDataset struct {
+	Label       string          `json:"label"`
+	Data        []timelinePoint `json:"data"`
+	YAxisID     string          `json:"yAxisID"`
+	BorderColor string          `json:"borderColor"`
+	BorderDash  []int           `json:"borderDash,omitempty"`
+}
+
+// buildTimelineDatasets renders each client's latency timeline and the
+// generator's CPU and memory series against seconds since the first sample.
+// It returns "" when no client has a timeline.
+func buildTimelineDatasets(result *types.BenchmarkResult, colors []string) string {
+	names := make([]string, 0, len(result.ClientMetrics))
+	var origin int64
+	for name, client := range result.ClientMetrics {
+		points := client.TimeSeries[types.TimeSeriesLatencyAvg]
+		if len(points) == 0 {
+			continue
+		}
+		names = append(names, name)
+		if origin == 0 || points[0].Timestamp < origin {
+			origin = points[0].Timestamp
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	for _, sample := range result.SystemMetrics {
+		if sample.Timestamp > 0 && sample.Timestamp < origin {
+			origin = sample.Timestamp
+		}
+	}
+	seconds := func(timestamp int64) float64 {
+		return float64(timestamp-origin) / 1000
+	}
+
+	var datasets []timelineDataset
+	for i, name := range names {
+		dataset := timelineDataset{
+			Label:       name + " mean latency",
+			YAxisID:     "latency",
+			BorderColor: strings.Trim(colors[i%len(colors)], "'"),
+		}
+		for _, point := range result.ClientMetrics[name].TimeSeries[types.TimeSeriesLatencyAvg] {
+			dataset.Data = append(dataset.Data, timelinePoint{X: seconds(point.Timestamp), Y: point.Value})
+		}
+		datasets = append(datasets, dataset)
+	}
+
+	cpu := timelineDataset{Label: "generator CPU %", YAxisID: "percent", BorderColor: "rgb(120, 120, 120)", BorderDash: []int{6, 3}}
+	memory := timelineDataset{Label: "generator memory %", YAxisID: "percent", BorderColor: "rgb(180, 180, 180)", BorderDash: []int{2, 2}}
+	for _, sample := range result.SystemMetrics {
+		if sample.Timestamp == 0 {
+			continue
+		}
+		cpu.Data = append(cpu.Data, timelinePoint{X: seconds(sample.Timestamp), Y: sample.HostCPUPercent})
+		memory.Data = append(memory.Data, timelinePoint{X: seconds(sample.Timestamp), Y: sample.MemoryPercent})
+	}
+	if len(cpu.Data) > 0 {
+		datasets = append(datasets, cpu, memory)
+	}
+
+	encoded, err := json.Marshal(datasets)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+// formatBytes renders a byte count with a binary unit suffix for report tables
+func formatBytes(b float64) string {
+	const unit = 1024.0
+	if b < unit {
+		return fmt.Sprintf("%.0f B", b)
+	}
+	suffixes := []string{"KiB", "MiB", "GiB"}
+	value := b / unit
+	i := 0
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
@@ -0,0 +1,25 @@
+// Package stats holds the small statistics helpers shared by the metrics
+// and analyzer packages, so both summarize samples the same way.
+package stats
+
+import "math"
+
+// PercentileSorted returns the percentile (0-100) of ascending values,
+// interpolating linearly between the closest ranks as k6 does. It returns 0
+// for no values.
+func PercentileSorted(sorted []float64, percentile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := percentile / 100 * float64(len(sorted)-1)
+	if index <= 0 {
+		return sorted[0]
+	}
+	if index >= float64(len(sorted)-1) {
+		return sorted[len(sorted)-1]
+	}
+	lower := int(math.Floor(index))
+	upper := int(math.Ceil(index))
+	weight := index - float64(lower)
+	return sorted[lower]*(1-weight) + sorted[upper]*weight
+}
@@ -0,0 +1,28 @@
+package stats
+
+import "testing"
+
+func TestPercentileSorted(t *testing.T) {
+	values := []float64{10, 20, 30, 40, 50}
+	tests := []struct {
+		name       string
+		values     []float64
+		percentile float64
+		want       float64
+	}{
+		{"median", values, 50, 30},
+		{"interpolated", values, 90, 46},
+		{"min", values, 0, 10},
+		{"max", values, 100, 50},
+		{"out of range", values, 150, 50},
+		{"single value", []float64{7}, 99, 7},
+		{"empty", nil, 50, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PercentileSorted(tt.values, tt.percentile); got != tt.want {
+				t.Errorf("PercentileSorted(%v, %v) = %v, want %v", tt.values, tt.percentile, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,615 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+var (
+	// procRoot and cgroupRoot are where procfs and the cgroup v2 hierarchy
+	// are mounted; powercapRoot holds the RAPL energy zones
+	procRoot     = "/proc"
+	cgroupRoot   = "/sys/fs/cgroup"
+	powercapRoot = "/sys/class/powercap"
+)
+
+// clockTicks is USER_HZ, the unit of /proc/<pid>/stat CPU times; it is 100 on
+// every Linux platform Go supports
+const clockTicks = 100
+
+// resourceCounters is a point-in-time reading of a client's cumulative CPU,
+// IO and network counters and its current memory
+type resourceCounters struct {
+	cpuSeconds  float64
+	memoryBytes int64
+	ioRead      int64
+	ioWrite     int64
+	netRecv     int64
+	netSent     int64
+}
+
+// resourceReader reads one client's counters from its cgroup or /proc
+type resourceReader interface {
+	source() string
+	target() string
+	read() (resourceCounters, error)
+}
+
+// cgroupReader reads a cgroup v2 directory. cgroups have no network
+// accounting, so network comes from the namespace of the cgroup's first
+// process; for host-network containers and services that is host-wide.
+// Memory is the working set: memory.current includes the page cache, which
+// for clients that mmap their database is most of it, so the inactive file
+// pages the kernel can reclaim are left out, as container tooling does.
+type cgroupReader struct {
+	dir string
+}
+
+func (r *cgroupReader) source() string { return types.ResourceSourceCgroup }
+func (r *cgroupReader) target() string { return r.dir }
+
+func (r *cgroupReader) read() (resourceCounters, error) {
+	var counters resourceCounters
+
+	cpuStat, err := readKeyValues(filepath.Join(r.dir, "cpu.stat"))
+	if err != nil {
+		return counters, err
+	}
+	counters.cpuSeconds = float64(cpuStat["usage_usec"]) / 1e6
+
+	memory, err := readInt(filepath.Join(r.dir, "memory.current"))
+	if err != nil {
+		return counters, err
+	}
+	// memory.stat is read after memory.current, so the subtraction can
+	// overshoot on a shrinking cache; clamp at zero
+	if stat, err := readKeyValues(filepath.Join(r.dir, "memory.stat")); err == nil {
+		memory = max(0, memory-stat["inactive_file"])
+	}
+	counters.memoryBytes = memory
+
+	// io.stat is optional: the io controller may not be enabled
+	if data, err := os.ReadFile(filepath.Join(r.dir, "io.stat")); err == nil {
+		counters.ioRead, counters.ioWrite = parseIOStat(string(data))
+	}
+
+	if procs, err := os.ReadFile(filepath.Join(r.dir, "cgroup.procs")); err == nil {
+		if fields := strings.Fields(string(procs)); len(fields) > 0 {
+			counters.netRecv, counters.netSent, _ = readNetDev(fields[0])
+		}
+	}
+	return counters, nil
+}
+
+// processReader reads /proc/<pid>. IO counts storage reads and writes; network
+// is the process's namespace, which is host-wide unless it runs in its own.
+type processReader struct {
+	pid string
+}
+
+func (r *processReader) source() string { return types.ResourceSourceProcess }
+func (r *processReader) target() string { return r.pid }
+
+func (r *processReader) read() (resourceCounters, error) {
+	var counters resourceCounters
+
+	stat, err := os.ReadFile(filepath.Join(procRoot, r.pid, "stat"))
+	if err != nil {
+		return counters, err
+	}
+	counters.cpuSeconds, err = parseProcStatCPU(string(stat))
+	if err != nil {
+		return counters, err
+	}
+
+	status, err := readKeyValues(filepath.Join(procRoot, r.pid, "status"))
+	if err != nil {
+		return counters, err
+	}
+	counters.memoryBytes = status["VmRSS:"] * 1024 // kB
+
+	// /proc/<pid>/io is only readable by the owner or root
+	if io, err := readKeyValues(filepath.Join(procRoot, r.pid, "io")); err == nil {
+		counters.ioRead = io["read_bytes:"]
+		counters.ioWrite = io["write_bytes:"]
+	}
+
+	counters.netRecv, counters.netSent, _ = readNetDev(r.pid)
+	return counters, nil
+}
+
+// newResourceReader resolves a client's process or cgroup hint; it returns
+// nil when the client has neither
+func newResourceReader(client *types.ClientConfig) (resourceReader, error) {
+	switch {
+	case client.Cgroup != "":
+		dir := client.Cgroup
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(cgroupRoot, dir)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "cpu.stat")); err != nil {
+			return nil, fmt.Errorf("cgroup %s is not a cgroup v2 directory: %w", client.Cgroup, err)
+		}
+		return &cgroupReader{dir: dir}, nil
+	case client.Process != "":
+		pid, err := resolvePID(client.Process)
+		if err != nil {
+			return nil, err
+		}
+		return &processReader{pid: pid}, nil
+	}
+	return nil, nil
+}
+
+// resolvePID turns a process hint into a pid: a number is taken as is, a path
+// is read as a pidfile and anything else is matched against process names
+func resolvePID(hint string) (string, error) {
+	if _, err := strconv.Atoi(hint); err == nil {
+		if _, err := os.Stat(filepath.Join(procRoot, hint)); err != nil {
+			return "", fmt.Errorf("process %s not found: %w", hint, err)
+		}
+		return hint, nil
+	}
+
+	if strings.ContainsRune(hint, '/') {
+		data, err := os.ReadFile(hint)
+		if err != nil {
+			return "", fmt.Errorf("failed to read pidfile: %w", err)
+		}
+		pid := strings.TrimSpace(string(data))
+		if _, err := strconv.Atoi(pid); err != nil {
+			return "", fmt.Errorf("pidfile %s does not hold a pid", hint)
+		}
+		return resolvePID(pid)
+	}
+
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to list processes: %w", err)
+	}
+	var matches []string
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		comm, err := os.ReadFile(filepath.Join(procRoot, entry.Name(), "comm"))
+		if err == nil && strings.TrimSpace(string(comm)) == hint {
+			matches = append(matches, entry.Name())
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no process named %s", hint)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d processes named %s; use a pid or pidfile", len(matches), hint)
+	}
+}
+
+// ClientResourceCollector samples the resource use of clients running on the
+// runner's host during benchmark execution
+type ClientResourceCollector struct {
+	mu           sync.RWMutex
+	readers      map[string]resourceReader
+	last         map[string]resourceCounters
+	startedAt    time.Time
+	lastAt       time.Time
+	lastHost     hostCounters
+	samples      map[string][]types.ClientResourceSample
+	isCollecting bool
+	stopCh       chan struct{}
+	interval     time.Duration
+	logger       *logrus.Logger
+}
+
+// NewClientResourceCollector creates a collector for the clients with a
+// process or cgroup hint. Clients whose hint can't be resolved are logged and
+// skipped; it returns nil when no client is left.
+func NewClientResourceCollector(clients []*types.ClientConfig, interval time.Duration, logger *logrus.Logger) *ClientResourceCollector {
+	readers := make(map[string]resourceReader)
+	for _, client := range clients {
+		reader, err := newResourceReader(client)
+		if err != nil {
+			logger.WithError(err).WithField("client", client.Name).Warn("Skipping client resource accounting")
+			continue
+		}
+		if reader != nil {
+			readers[client.Name] = reader
+		}
+	}
+	if len(readers) == 0 {
+		return nil
+	}
+
+	return &ClientResourceCollector{
+		readers:  readers,
+		interval: interval,
+		logger:   logger,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// Start begins sampling client resources
+func (cc *ClientResourceCollector) Start() {
+	cc.mu.Lock()
+	if cc.isCollecting {
+		cc.mu.Unlock()
+		return
+	}
+	cc.isCollecting = true
+	cc.samples = make(map[string][]types.ClientResourceSample)
+	cc.last = cc.readAll()
+	cc.lastHost = readHostCounters()
+	cc.lastAt = time.Now()
+	cc.startedAt = cc.lastAt
+	cc.mu.Unlock()
+
+	go cc.collect()
+}
+
+// Stop stops sampling client resources
+func (cc *ClientResourceCollector) Stop() {
+	cc.mu.Lock()
+	if !cc.isCollecting {
+		cc.mu.Unlock()
+		return
+	}
+	cc.isCollecting = false
+	cc.mu.Unlock()
+
+	close(cc.stopCh)
+}
+
+// collect runs the sampling loop
+func (cc *ClientResourceCollector) collect() {
+	ticker := time.NewTicker(cc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cc.sample()
+		case <-cc.stopCh:
+			return
+		}
+	}
+}
+
+// sample records one interval for every client that could be read
+func (cc *ClientResourceCollector) sample() {
+	current := cc.readAll()
+	host := readHostCounters()
+	now := time.Now()
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	elapsed := now.Sub(cc.lastAt).Seconds()
+	hostWatts, hostCPU, hasPower := hostPower(cc.lastHost, host, elapsed)
+	for name, counters := range current {
+		prev, ok := cc.last[name]
+		if !ok || elapsed <= 0 {
+			continue
+		}
+		sample := counterDelta(prev, counters, now, elapsed)
+		if hasPower {
+			// Attribute host power by the client's share of busy CPU time
+			share := math.Min(1, (counters.cpuSeconds-prev.cpuSeconds)/hostCPU)
+			sample.Watts = hostWatts * math.Max(0, share)
+		}
+		cc.samples[name] = append(cc.samples[name], sample)
+	}
+	cc.last = current
+	cc.lastHost = host
+	cc.lastAt = now
+}
+
+// hostCounters is a reading of the host's cumulative package energy and busy
+// CPU time; energy is unavailable without readable RAPL zones
+type hostCounters struct {
+	energyJoules float64
+	hasEnergy    bool
+	cpuSeconds   float64
+}
+
+// readHostCounters reads RAPL package energy and busy CPU time from /proc/stat
+func readHostCounters() hostCounters {
+	var host hostCounters
+	host.energyJoules, host.hasEnergy = readRAPLEnergy()
+	if data, err := os.ReadFile(filepath.Join(procRoot, "stat")); err == nil {
+		host.cpuSeconds = parseHostBusyCPU(string(data))
+	}
+	return host
+}
+
+// hostPower returns the host's average power and busy CPU-seconds between two
+// readings. ok is false without energy readings, when a counter wrapped or
+// when the host was idle.
+func hostPower(prev, current hostCounters, elapsed float64) (watts, cpuSeconds float64, ok bool) {
+	if !prev.hasEnergy || !current.hasEnergy || elapsed <= 0 {
+		return 0, 0, false
+	}
+	energy := current.energyJoules - prev.energyJoules
+	cpuSeconds = current.cpuSeconds - prev.cpuSeconds
+	if energy < 0 || cpuSeconds <= 0 {
+		return 0, 0, false
+	}
+	return energy / elapsed, cpuSeconds, true
+}
+
+// readRAPLEnergy sums energy_uj over the top-level RAPL package zones
+// (intel-rapl:N, also used on AMD); subzones are already part of them
+func readRAPLEnergy() (float64, bool) {
+	zones, err := filepath.Glob(filepath.Join(powercapRoot, "intel-rapl:*"))
+	if err != nil {
+		return 0, false
+	}
+	var microjoules int64
+	found := false
+	for _, zone := range zones {
+		if strings.Count(filepath.Base(zone), ":") != 1 {
+			continue
+		}
+		energy, err := readInt(filepath.Join(zone, "energy_uj"))
+		if err != nil {
+			continue
+		}
+		microjoules += energy
+		found = true
+	}
+	return float64(microjoules) / 1e6, found
+}
+
+// parseHostBusyCPU returns busy CPU-seconds from the aggregate cpu line of
+// /proc/stat: user, nice, system, irq, softirq and steal
+func parseHostBusyCPU(stat string) float64 {
+	for _, line := range strings.Split(stat, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 9 || fields[0] != "cpu" {
+			continue
+		}
+		var ticks int64
+		for _, i := range []int{1, 2, 3, 6, 7, 8} {
+			n, _ := strconv.ParseInt(fields[i], 10, 64)
+			ticks += n
+		}
+		return float64(ticks) / clockTicks
+	}
+	return 0
+}
+
+// readAll reads every client's counters, dropping those that fail: a client
+// that exited or restarted has no meaningful delta
+func (cc *ClientResourceCollector) readAll() map[string]resourceCounters {
+	current := make(map[string]resourceCounters, len(cc.readers))
+	for name, reader := range cc.readers {
+		counters, err := reader.read()
+		if err != nil {
+			cc.logger.WithError(err).WithField("client", name).Debug("Failed to read client resources")
+			continue
+		}
+		current[name] = counters
+	}
+	return current
+}
+
+// counterDelta turns two readings elapsed seconds apart into a sample
+func counterDelta(prev, current resourceCounters, at time.Time, elapsed float64) types.ClientResourceSample {
+	nonNegative := func(v int64) int64 {
+		if v < 0 {
+			return 0
+		}
+		return v
+	}
+	cpu := (current.cpuSeconds - prev.cpuSeconds) / elapsed
+	if cpu < 0 {
+		cpu = 0
+	}
+	return types.ClientResourceSample{
+		Timestamp:    at.UnixMilli(),
+		CPUCores:     cpu,
+		MemoryBytes:  current.memoryBytes,
+		IOReadBytes:  nonNegative(current.ioRead - prev.ioRead),
+		IOWriteBytes: nonNegative(current.ioWrite - prev.ioWrite),
+		NetRecvBytes: nonNegative(current.netRecv - prev.netRecv),
+		NetSentBytes: nonNegative(current.netSent - prev.netSent),
+	}
+}
+
+// GetResources returns each sampled client's series and totals
+func (cc *ClientResourceCollector) GetResources() map[string]*types.ClientResources {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	result := make(map[string]*types.ClientResources, len(cc.samples))
+	for name, samples := range cc.samples {
+		reader := cc.readers[name]
+		result[name] = summarizeResources(reader.source(), reader.target(), samples, cc.startedAt)
+	}
+	return result
+}
+
+// summarizeResources derives totals, averages and peaks from a sample series
+// that started at startedAt. Each sample's CPU rate is integrated over the
+// measured time since the previous sample, not the nominal interval: ticks
+// run late under load, and a window a failed read left unsampled is counted
+// at the rate of the sample that ends it.
+func summarizeResources(source, target string, samples []types.ClientResourceSample, startedAt time.Time) *types.ClientResources {
+	resources := &types.ClientResources{
+		Source:  source,
+		Target:  target,
+		Samples: append([]types.ClientResourceSample(nil), samples...),
+	}
+	if len(samples) == 0 {
+		return resources
+	}
+
+	var memorySum int64
+	var wattsSum float64
+	prevMs := startedAt.UnixMilli()
+	for _, sample := range samples {
+		wattsSum += sample.Watts
+		if elapsedMs := sample.Timestamp - prevMs; elapsedMs > 0 {
+			resources.CPUSeconds += sample.CPUCores * float64(elapsedMs) / 1000
+		}
+		prevMs = sample.Timestamp
+		resources.AvgCPUCores += sample.CPUCores
+		if sample.CPUCores > resources.PeakCPUCores {
+			resources.PeakCPUCores = sample.CPUCores
+		}
+		memorySum += sample.MemoryBytes
+		if sample.MemoryBytes > resources.PeakMemoryBytes {
+			resources.PeakMemoryBytes = sample.MemoryBytes
+		}
+		resources.IOReadBytes += sample.IOReadBytes
+		resources.IOWriteBytes += sample.IOWriteBytes
+		resources.NetRecvBytes += sample.NetRecvBytes
+		resources.NetSentBytes += sample.NetSentBytes
+	}
+	n := len(samples)
+	resources.AvgCPUCores /= float64(n)
+	resources.AvgMemoryBytes = memorySum / int64(n)
+	resources.AvgWatts = wattsSum / float64(n)
+	return resources
+}
+
+// ApplyClientResources attaches sampled resources to their clients and derives
+// requests per second per core and memory per request per second from the
+// run's wall-clock duration
+func ApplyClientResources(clientsMetrics map[string]*types.ClientMetrics, resources map[string]*types.ClientResources, duration time.Duration) {
+	for name, usage := range resources {
+		client, ok := clientsMetrics[name]
+		if !ok {
+			continue
+		}
+		client.Resources = usage
+
+		if duration <= 0 {
+			continue
+		}
+		rps := float64(client.TotalRequests) / duration.Seconds()
+		if rps <= 0 {
+			continue
+		}
+		if usage.AvgCPUCores > 0 {
+			usage.RPSPerCore = rps / usage.AvgCPUCores
+		}
+		usage.MemoryBytesPerRPS = float64(usage.AvgMemoryBytes) / rps
+	}
+}
+
+// parseProcStatCPU returns utime+stime in seconds from /proc/<pid>/stat. The
+// command name may contain spaces, so fields are counted after its closing
+// parenthesis.
+func parseProcStatCPU(stat string) (float64, error) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed stat")
+	}
+	// Fields after the command start at field 3 (state); utime and stime are
+	// fields 14 and 15
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 13 {
+		return 0, fmt.Errorf("malformed stat")
+	}
+	utime, err := strconv.ParseInt(fields[11], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed stat utime: %w", err)
+	}
+	stime, err := strconv.ParseInt(fields[12], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed stat stime: %w", err)
+	}
+	return float64(utime+stime) / clockTicks, nil
+}
+
+// parseIOStat sums rbytes and wbytes over every device in a cgroup io.stat
+func parseIOStat(data string) (read, write int64) {
+	for _, line := range strings.Split(data, "\n") {
+		for _, field := range strings.Fields(line) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				read += n
+			case "wbytes":
+				write += n
+			}
+		}
+	}
+	return read, write
+}
+
+// readNetDev sums received and sent bytes over the non-loopback interfaces of
+// the network namespace pid lives in
+func readNetDev(pid string) (recv, sent int64, err error) {
+	f, err := os.Open(filepath.Join(procRoot, pid, "net", "dev"))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		iface, stats, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || strings.TrimSpace(iface) == "lo" {
+			continue
+		}
+		// Receive bytes is the first column, transmit bytes the ninth
+		fields := strings.Fields(stats)
+		if len(fields) < 9 {
+			continue
+		}
+		r, _ := strconv.ParseInt(fields[0], 10, 64)
+		s, _ := strconv.ParseInt(fields[8], 10, 64)
+		recv += r
+		sent += s
+	}
+	return recv, sent, scanner.Err()
+}
+
+// readKeyValues reads "key value" lines, keeping the first integer of each
+func readKeyValues(path string) (map[string]int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]int64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if n, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values, nil
+}
+
+// readInt reads a file holding a single integer
+func readInt(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
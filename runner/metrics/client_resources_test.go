@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// fakeHost points procRoot and cgroupRoot at a temporary tree for the test
+func fakeHost(t *testing.T) (proc, cgroup string) {
+	t.Helper()
+	dir := t.TempDir()
+	proc, cgroup = filepath.Join(dir, "proc"), filepath.Join(dir, "cgroup")
+	oldProc, oldCgroup := procRoot, cgroupRoot
+	procRoot, cgroupRoot = proc, cgroup
+	t.Cleanup(func() { procRoot, cgroupRoot = oldProc, oldCgroup })
+	return proc, cgroup
+}
+
+const netDevFixture = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:    5000      10    0    0    0     0          0         0     5000      10    0    0    0     0       0          0
+  eth0:    2000      20    0    0    0     0          0         0     3000      30    0    0    0     0       0          0
+`
+
+func TestCgroupReader_ReadsCPUMemoryIOAndNetwork(t *testing.T) {
+	proc, cgroup := fakeHost(t)
+	dir := filepath.Join(cgroup, "system.slice", "geth.service")
+	writeFile(t, filepath.Join(dir, "cpu.stat"), "usage_usec 2500000\nuser_usec 2000000\n")
+	writeFile(t, filepath.Join(dir, "memory.current"), "1310720\n")
+	writeFile(t, filepath.Join(dir, "memory.stat"), "anon 786432\nfile 524288\nactive_file 262144\ninactive_file 262144\n")
+	writeFile(t, filepath.Join(dir, "io.stat"), "8:0 rbytes=100 wbytes=200 rios=1 wios=2\n8:16 rbytes=10 wbytes=20\n")
+	writeFile(t, filepath.Join(dir, "cgroup.procs"), "42\n43\n")
+	writeFile(t, filepath.Join(proc, "42", "net", "dev"), netDevFixture)
+
+	reader, err := newResourceReader(&types.ClientConfig{Name: "geth", Cgroup: "system.slice/geth.service"})
+	if err != nil {
+		t.Fatalf("newResourceReader: %v", err)
+	}
+	counters, err := reader.read()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := resourceCounters{cpuSeconds: 2.5, memoryBytes: 1048576, ioRead: 110, ioWrite: 220, netRecv: 2000, netSent: 3000}
+	if counters != want {
+		t.Fatalf("counters = %+v, want %+v", counters, want)
+	}
+	if reader.source() != types.ResourceSourceCgroup {
+		t.Fatalf("unexpected source %q", reader.source())
+	}
+}
+
+func TestProcessReader_ResolvesNameAndReadsProc(t *testing.T) {
+	proc, _ := fakeHost(t)
+	pidDir := filepath.Join(proc, "77")
+	writeFile(t, filepath.Join(pidDir, "comm"), "nethermind\n")
+	// Command names may contain spaces and parentheses
+	writeFile(t, filepath.Join(pidDir, "stat"), "77 (Nethermind (x)) S 1 77 77 0 -1 4194304 100 0 0 0 150 50 0 0 20 0 30 0\n")
+	writeFile(t, filepath.Join(pidDir, "status"), "Name:\tnethermind\nVmRSS:\t  2048 kB\n")
+	writeFile(t, filepath.Join(pidDir, "io"), "rchar: 9\nwchar: 9\nread_bytes: 4096\nwrite_bytes: 8192\n")
+	writeFile(t, filepath.Join(pidDir, "net", "dev"), netDevFixture)
+	writeFile(t, filepath.Join(proc, "78", "comm"), "geth\n")
+
+	reader, err := newResourceReader(&types.ClientConfig{Name: "nethermind", Process: "nethermind"})
+	if err != nil {
+		t.Fatalf("newResourceReader: %v", err)
+	}
+	if reader.target() != "77" {
+		t.Fatalf("resolved pid %q, want 77", reader.target())
+	}
+	counters, err := reader.read()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := resourceCounters{cpuSeconds: 2, memoryBytes: 2048 * 1024, ioRead: 4096, ioWrite: 8192, netRecv: 2000, netSent: 3000}
+	if counters != want {
+		t.Fatalf("counters = %+v, want %+v", counters, want)
+	}
+}
+
+func TestNewResourceReader_NoHint(t *testing.T) {
+	reader, err := newResourceReader(&types.ClientConfig{Name: "remote"})
+	if err != nil || reader != nil {
+		t.Fatalf("expected no reader and no error, got %v, %v", reader, err)
+	}
+}
+
+func TestHostPower_AttributesRAPLEnergy(t *testing.T) {
+	proc, _ := fakeHost(t)
+	powercap := filepath.Join(filepath.Dir(proc), "powercap")
+	oldPowercap := powercapRoot
+	powercapRoot = powercap
+	t.Cleanup(func() { powercapRoot = oldPowercap })
+
+	// Subzones are part of their package and must not be counted twice
+	writeFile(t, filepath.Join(powercap, "intel-rapl:0", "energy_uj"), "10000000\n")
+	writeFile(t, filepath.Join(powercap, "intel-rapl:1", "energy_uj"), "5000000\n")
+	writeFile(t, filepath.Join(powercap, "intel-rapl:0:0", "energy_uj"), "999000000\n")
+	// user nice system idle iowait irq softirq steal: 400 busy ticks
+	writeFile(t, filepath.Join(proc, "stat"), "cpu  100 50 150 9000 70 40 30 30 0 0\ncpu0 1 1 1 1 1 1 1 1 0 0\n")
+
+	prev := readHostCounters()
+	if !prev.hasEnergy || prev.energyJoules != 15 || prev.cpuSeconds != 4 {
+		t.Fatalf("unexpected host counters: %+v", prev)
+	}
+
+	current := hostCounters{energyJoules: 55, hasEnergy: true, cpuSeconds: 6}
+	watts, cpuSeconds, ok := hostPower(prev, current, 2)
+	if !ok || watts != 20 || cpuSeconds != 2 {
+		t.Fatalf("hostPower = %v, %v, %v; want 20, 2, true", watts, cpuSeconds, ok)
+	}
+
+	// A wrapped energy counter yields no reading
+	if _, _, ok := hostPower(current, prev, 2); ok {
+		t.Fatal("expected no power reading when energy decreases")
+	}
+}
+
+func TestApplyClientResources_DerivesEfficiency(t *testing.T) {
+	clients := map[string]*types.ClientMetrics{"geth": {Name: "geth", TotalRequests: 1000}}
+	samples := []types.ClientResourceSample{
+		{Timestamp: 1000, CPUCores: 1, MemoryBytes: 100 << 20},
+		{Timestamp: 2000, CPUCores: 3, MemoryBytes: 300 << 20},
+	}
+	resources := map[string]*types.ClientResources{
+		"geth":    summarizeResources(types.ResourceSourceCgroup, "geth.service", samples, time.UnixMilli(0)),
+		"unknown": summarizeResources(types.ResourceSourceCgroup, "x", samples, time.UnixMilli(0)),
+	}
+
+	ApplyClientResources(clients, resources, 10*time.Second)
+
+	res := clients["geth"].Resources
+	if res == nil {
+		t.Fatal("expected resources attached to geth")
+	}
+	if res.AvgCPUCores != 2 || res.PeakCPUCores != 3 || res.CPUSeconds != 4 {
+		t.Fatalf("unexpected CPU figures: %+v", res)
+	}
+	if res.AvgMemoryBytes != 200<<20 || res.PeakMemoryBytes != 300<<20 {
+		t.Fatalf("unexpected memory figures: %+v", res)
+	}
+	// 100 req/s on 2 cores; 200 MiB over 100 req/s
+	if res.RPSPerCore != 50 || res.MemoryBytesPerRPS != float64(200<<20)/100 {
+		t.Fatalf("unexpected efficiency: %+v", res)
+	}
+}
+
+func TestSummarizeResources_CPUSecondsFromMeasuredWindows(t *testing.T) {
+	// The second tick ran 1.5s late, so its window is 2.5s rather than 1s
+	samples := []types.ClientResourceSample{
+		{Timestamp: 1000, CPUCores: 1},
+		{Timestamp: 3500, CPUCores: 2},
+	}
+	res := summarizeResources(types.ResourceSourceCgroup, "geth.service", samples, time.UnixMilli(0))
+	if res.CPUSeconds != 6 {
+		t.Fatalf("CPUSeconds = %v, want 6 (1 core for 1s, 2 cores for 2.5s)", res.CPUSeconds)
+	}
+}
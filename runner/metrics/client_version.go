@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// FetchClientVersions asks every client for web3_clientVersion concurrently.
+// Clients that fail to answer are logged and left out.
+func FetchClientVersions(clients []*types.ClientConfig, timeout time.Duration, logger logrus.FieldLogger) map[string]string {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	versions := make(map[string]string)
+	for _, client := range clients {
+		if client == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(client *types.ClientConfig) {
+			defer wg.Done()
+			version, err := fetchClientVersion(ctx, client)
+			if err != nil {
+				logger.WithError(err).WithField("client", client.Name).Debug("Failed to fetch client version")
+				return
+			}
+			mu.Lock()
+			versions[client.Name] = version
+			mu.Unlock()
+		}(client)
+	}
+	wg.Wait()
+	return versions
+}
+
+func fetchClientVersion(ctx context.Context, client *types.ClientConfig) (string, error) {
+	body := []byte(`{"jsonrpc":"2.0","method":"web3_clientVersion","params":[],"id":1}`)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.GetBasicAuthURL(), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range client.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var reply struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+	if reply.Error != nil {
+		return "", fmt.Errorf("rpc error: %s", reply.Error.Message)
+	}
+	if reply.Result == "" {
+		return "", fmt.Errorf("empty client version")
+	}
+	return reply.Result, nil
+}
+
+// ApplyClientVersions records each client's version on its metrics
+func ApplyClientVersions(clientsMetrics map[string]*types.ClientMetrics, versions map[string]string) {
+	for name, version := range versions {
+		if client, ok := clientsMetrics[name]; ok {
+			client.Version = version
+		}
+	}
+}
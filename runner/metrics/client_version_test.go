@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+func TestFetchClientVersions_SkipsClientsThatFail(t *testing.T) {
+	geth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "web3_clientVersion") || r.Header.Get("X-Api-Key") != "secret" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"Geth/v1.14.0"}`))
+	}))
+	defer geth.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+	}))
+	defer broken.Close()
+
+	clients := []*types.ClientConfig{
+		{Name: "geth", URL: geth.URL, Headers: map[string]string{"X-Api-Key": "secret"}},
+		{Name: "broken", URL: broken.URL},
+	}
+	versions := FetchClientVersions(clients, time.Second, logrus.New())
+	if len(versions) != 1 || versions["geth"] != "Geth/v1.14.0" {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+
+	clientsMetrics := map[string]*types.ClientMetrics{"geth": {Name: "geth"}, "broken": {Name: "broken"}}
+	ApplyClientVersions(clientsMetrics, versions)
+	if clientsMetrics["geth"].Version != "Geth/v1.14.0" || clientsMetrics["broken"].Version != "" {
+		t.Fatalf("unexpected applied versions: %+v, %+v", clientsMetrics["geth"], clientsMetrics["broken"])
+	}
+}
@@ -0,0 +1,492 @@
+package metrics
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/internal/stats"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// Formats accepted by ImportResult
+const (
+	ImportFormatK6Summary = "k6-summary"
+	ImportFormatK6Stream  = "k6-stream"
+	ImportFormatFlood     = "flood"
+)
+
+// ImportedMethod is the default name of the method of sources that do not
+// tag requests with one: an untagged k6 summary, untagged stream samples and
+// flood reports
+const ImportedMethod = "requests"
+
+// ImportOptions controls how ImportResult names clients and methods
+type ImportOptions struct {
+	// Clients maps the source name of each target (the k6 scenario or the
+	// flood node name) to the client name it is stored under. When empty,
+	// every source keeps its own name; otherwise unmapped sources are dropped.
+	Clients map[string]string
+	// Method names requests the source does not tag with a method; defaults
+	// to ImportedMethod
+	Method string
+}
+
+// ImportFormats lists the formats ImportResult accepts
+func ImportFormats() []string {
+	return []string{ImportFormatK6Summary, ImportFormatK6Stream, ImportFormatFlood}
+}
+
+// ImportResult parses an externally produced k6 summary.json, k6 JSON output
+// stream or flood report into a benchmark result
+func ImportResult(format, path string, opts ImportOptions) (*types.BenchmarkResult, error) {
+	if opts.Method == "" {
+		opts.Method = ImportedMethod
+	}
+	var (
+		clientsMetrics map[string]*types.ClientMetrics
+		start, end     time.Time
+		duration       time.Duration
+		err            error
+	)
+	switch format {
+	case ImportFormatK6Summary:
+		clientsMetrics, duration, err = importK6Summary(path, opts)
+	case ImportFormatK6Stream:
+		clientsMetrics, start, end, err = importK6Stream(path, opts)
+	case ImportFormatFlood:
+		clientsMetrics, start, end, err = importFloodReport(path, opts)
+	default:
+		return nil, fmt.Errorf("unknown import format %q (want one of %s)", format, strings.Join(ImportFormats(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(clientsMetrics) == 0 {
+		return nil, fmt.Errorf("no client results found in %s", path)
+	}
+	finalizeClientMetrics(clientsMetrics)
+
+	result := &types.BenchmarkResult{
+		SchemaVersion: types.ResultSchemaVersion,
+		Config:        map[string]interface{}{"imported_from": path, "import_format": format},
+		Summary:       map[string]interface{}{},
+		ClientMetrics: clientsMetrics,
+	}
+	if !end.IsZero() {
+		result.Timestamp = end.Local().Format(time.DateTime)
+		result.EndTime = end.Local().Format(time.DateTime)
+	}
+	if !start.IsZero() {
+		result.StartTime = start.Local().Format(time.DateTime)
+		if !end.IsZero() {
+			duration = end.Sub(start)
+		}
+	}
+	if duration > 0 {
+		result.Duration = duration.Round(time.Second).String()
+	}
+	return result, nil
+}
+
+// importClientName maps a source name to its client name; false drops it
+func importClientName(clients map[string]string, source string) (string, bool) {
+	if len(clients) == 0 {
+		return source, source != ""
+	}
+	name, ok := clients[source]
+	return name, ok
+}
+
+// newImportedClient returns an empty client for finalizeClientMetrics to fill
+func newImportedClient(name string) *types.ClientMetrics {
+	return &types.ClientMetrics{
+		Name:         name,
+		Methods:      make(map[string]types.MetricSummary),
+		TimeSeries:   make(map[string][]types.TimeSeriesPoint),
+		ErrorTypes:   make(map[string]int64),
+		StatusCodes:  make(map[int]int64),
+		ErrorClasses: make(map[string]int64),
+	}
+}
+
+// k6SummaryFile is the part of a summary.json ImportResult reads besides the
+// metrics; state is only present in handleSummary output
+type k6SummaryFile struct {
+	State struct {
+		TestRunDurationMs float64 `json:"testRunDurationMs"`
+	} `json:"state"`
+}
+
+// parseSubmetricKey splits a k6 submetric key such as
+// `http_req_duration{scenario:geth,req_name:eth_call}` into its base metric
+// and tags
+func parseSubmetricKey(key string) (string, map[string]string, bool) {
+	open := strings.IndexByte(key, '{')
+	if open < 0 || !strings.HasSuffix(key, "}") {
+		return key, nil, false
+	}
+	tags := make(map[string]string)
+	for _, pair := range strings.Split(key[open+1:len(key)-1], ",") {
+		name, value, ok := strings.Cut(pair, ":")
+		if !ok {
+			continue
+		}
+		tags[name] = value
+	}
+	return key[:open], tags, true
+}
+
+// importK6Summary reads every scenario/req_name submetric of a summary.json
+// and the run duration handleSummary output records. The summary holds no
+// wall-clock times.
+// A summary without such submetrics (k6 only writes submetrics that have a
+// threshold) is imported as the single method opts.Method of the "default"
+// scenario from its top-level metrics.
+func importK6Summary(path string, opts ImportOptions) (map[string]*types.ClientMetrics, time.Duration, error) {
+	summary, err := loadK6Summary(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pairs := make(map[clientMethodPair]struct{})
+	for key := range summary.Metrics {
+		base, tags, ok := parseSubmetricKey(key)
+		if !ok || (base != "http_req_duration" && base != "http_reqs") {
+			continue
+		}
+		if tags["scenario"] == "" || tags["req_name"] == "" || len(tags) != 2 {
+			continue
+		}
+		pairs[clientMethodPair{client: tags["scenario"], method: tags["req_name"]}] = struct{}{}
+	}
+
+	if len(pairs) == 0 {
+		// Re-key the top-level metrics as submetrics so the untagged summary
+		// goes through the same extraction as a tagged one
+		tagged := &k6Summary{Metrics: make(map[string]k6MetricValue, len(summary.Metrics))}
+		for key, value := range summary.Metrics {
+			if !strings.Contains(key, "{") {
+				tagged.Metrics[fmt.Sprintf("%s{scenario:default,req_name:%s}", key, opts.Method)] = value
+			}
+		}
+		summary = tagged
+		pairs[clientMethodPair{client: "default", method: opts.Method}] = struct{}{}
+	}
+
+	clientsMetrics := make(map[string]*types.ClientMetrics)
+	for pair := range pairs {
+		name, ok := importClientName(opts.Clients, pair.client)
+		if !ok {
+			continue
+		}
+		method := extractMethodFromSummary(summary, pair.client, pair.method)
+		if method == nil {
+			continue
+		}
+		client, ok := clientsMetrics[name]
+		if !ok {
+			client = newImportedClient(name)
+			clientsMetrics[name] = client
+		}
+		client.Methods[pair.method] = *method
+	}
+
+	var duration time.Duration
+	if data, err := os.ReadFile(path); err == nil {
+		var file k6SummaryFile
+		if json.Unmarshal(data, &file) == nil {
+			duration = time.Duration(file.State.TestRunDurationMs * float64(time.Millisecond))
+		}
+	}
+	return clientsMetrics, duration, nil
+}
+
+// streamPair accumulates the samples of one client and method of a k6 stream
+type streamPair struct {
+	latencies     []float64
+	failed        int64
+	responseBytes []float64
+}
+
+// importK6Stream reads the per-request samples of a k6 JSON output stream
+// (gzipped when the name ends in .gz). Requests are grouped by their scenario
+// and req_name tags; requests without a req_name go to opts.Method.
+func importK6Stream(path string, opts ImportOptions) (map[string]*types.ClientMetrics, time.Time, time.Time, error) {
+	var start, end time.Time
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, start, end, fmt.Errorf("failed to open k6 stream: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, start, end, fmt.Errorf("failed to read gzipped k6 stream: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	pairs := make(map[clientMethodPair]*streamPair)
+	statusCodes := make(map[string]map[int]int64)
+	seconds := make(map[string]map[int64]*types.TimeSeriesPoint)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var line k6StreamLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, start, end, fmt.Errorf("failed to parse k6 stream line: %w", err)
+		}
+		if line.Type != "Point" {
+			continue
+		}
+		client, ok := importClientName(opts.Clients, line.Data.Tags["scenario"])
+		if !ok {
+			continue
+		}
+		method := line.Data.Tags["req_name"]
+		if method == "" {
+			method = opts.Method
+		}
+		key := clientMethodPair{client: client, method: method}
+		pair, ok := pairs[key]
+		if !ok {
+			pair = &streamPair{}
+			pairs[key] = pair
+		}
+
+		switch line.Metric {
+		case "http_req_duration":
+			pair.latencies = append(pair.latencies, line.Data.Value)
+			status, _ := strconv.Atoi(line.Data.Tags["status"])
+			if statusCodes[client] == nil {
+				statusCodes[client] = make(map[int]int64)
+			}
+			statusCodes[client][status]++
+			at, err := time.Parse(time.RFC3339Nano, line.Data.Time)
+			if err != nil {
+				continue
+			}
+			addTimelineSample(seconds, client, at, line.Data.Value, status < 200 || status >= 300)
+			if start.IsZero() || at.Before(start) {
+				start = at
+			}
+			if at.After(end) {
+				end = at
+			}
+		case "http_req_failed":
+			if line.Data.Value > 0 {
+				pair.failed++
+			}
+		case "rpc_response_bytes":
+			pair.responseBytes = append(pair.responseBytes, line.Data.Value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, start, end, fmt.Errorf("failed to read k6 stream: %w", err)
+	}
+
+	clientsMetrics := make(map[string]*types.ClientMetrics)
+	for key, pair := range pairs {
+		if len(pair.latencies) == 0 {
+			continue
+		}
+		client, ok := clientsMetrics[key.client]
+		if !ok {
+			client = newImportedClient(key.client)
+			clientsMetrics[key.client] = client
+		}
+		client.Methods[key.method] = summarizeStreamPair(pair)
+	}
+	for name, client := range clientsMetrics {
+		for status, count := range statusCodes[name] {
+			client.StatusCodes[status] = count
+		}
+		points := make([]types.TimeSeriesPoint, 0, len(seconds[name]))
+		for _, p := range seconds[name] {
+			p.Value /= float64(p.Count)
+			points = append(points, *p)
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+		client.TimeSeries[types.TimeSeriesLatencyAvg] = points
+	}
+	return clientsMetrics, start, end, nil
+}
+
+// summarizeStreamPair computes a method summary from its raw samples
+func summarizeStreamPair(pair *streamPair) types.MetricSummary {
+	latencies := pair.latencies
+	sort.Float64s(latencies)
+
+	var sum float64
+	for _, v := range latencies {
+		sum += v
+	}
+	count := int64(len(latencies))
+	avg := sum / float64(count)
+	var squares float64
+	for _, v := range latencies {
+		squares += (v - avg) * (v - avg)
+	}
+
+	method := types.MetricSummary{
+		Count:        count,
+		Min:          latencies[0],
+		Max:          latencies[len(latencies)-1],
+		Avg:          avg,
+		P50:          stats.PercentileSorted(latencies, 50),
+		P90:          stats.PercentileSorted(latencies, 90),
+		P95:          stats.PercentileSorted(latencies, 95),
+		P99:          stats.PercentileSorted(latencies, 99),
+		StdDev:       math.Sqrt(squares / float64(count)),
+		ErrorCount:   pair.failed,
+		SuccessCount: count - pair.failed,
+	}
+	if avg > 0 {
+		method.CoeffVar = method.StdDev / avg * 100
+	}
+	method.ErrorRate = float64(pair.failed) / float64(count) * 100
+	method.SuccessRate = 100 - method.ErrorRate
+
+	if sizes := pair.responseBytes; len(sizes) > 0 {
+		sort.Float64s(sizes)
+		var total float64
+		for _, v := range sizes {
+			total += v
+		}
+		method.ResponseBytesAvg = total / float64(len(sizes))
+		method.ResponseBytesP50 = stats.PercentileSorted(sizes, 50)
+		method.ResponseBytesP90 = stats.PercentileSorted(sizes, 90)
+		method.ResponseBytesP95 = stats.PercentileSorted(sizes, 95)
+		method.ResponseBytesP99 = stats.PercentileSorted(sizes, 99)
+		method.ResponseBytesMax = sizes[len(sizes)-1]
+	}
+	return method
+}
+
+// floodReport is flood's results.json. Every per-node series holds one entry
+// per tested rate; latencies are in seconds and success is a fraction.
+type floodReport struct {
+	Results map[string]floodNodeResult `json:"results"`
+}
+
+type floodNodeResult struct {
+	TargetRate            []float64 `json:"target_rate"`
+	ActualRate            []float64 `json:"actual_rate"`
+	Requests              []float64 `json:"requests"`
+	NRequests             []float64 `json:"n_requests"` // older flood versions
+	Throughput            []float64 `json:"throughput"`
+	Success               []float64 `json:"success"`
+	Min                   []float64 `json:"min"`
+	Mean                  []float64 `json:"mean"`
+	P50                   []float64 `json:"p50"`
+	P90                   []float64 `json:"p90"`
+	P95                   []float64 `json:"p95"`
+	P99                   []float64 `json:"p99"`
+	Max                   []float64 `json:"max"`
+	FirstRequestTimestamp []float64 `json:"first_request_timestamp"`
+	LastResponseTimestamp []float64 `json:"last_response_timestamp"`
+}
+
+// importFloodReport reads a flood results.json. Flood tests one call per run,
+// so each node becomes a client with the single method opts.Method. Its rates
+// are merged into one summary with counts summed and percentiles averaged
+// weighted by requests; each rate is kept as a latency timeline point.
+func importFloodReport(path string, opts ImportOptions) (map[string]*types.ClientMetrics, time.Time, time.Time, error) {
+	var start, end time.Time
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, start, end, fmt.Errorf("failed to read flood report: %w", err)
+	}
+	var report floodReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, start, end, fmt.Errorf("failed to parse flood report: %w", err)
+	}
+	at := func(series []float64, i int) float64 {
+		if i < len(series) {
+			return series[i]
+		}
+		return 0
+	}
+	clientsMetrics := make(map[string]*types.ClientMetrics)
+	for node, result := range report.Results {
+		name, ok := importClientName(opts.Clients, node)
+		if !ok {
+			continue
+		}
+		if len(result.Requests) == 0 {
+			result.Requests = result.NRequests
+		}
+		method := types.MetricSummary{Min: math.MaxFloat64}
+		var points []types.TimeSeriesPoint
+		var weighted float64
+		for i := range result.Requests {
+			requests := result.Requests[i]
+			if requests <= 0 {
+				continue
+			}
+			count := int64(requests)
+			errors := int64(math.Round(requests * (1 - at(result.Success, i))))
+			method.Count += count
+			method.ErrorCount += errors
+			method.Avg += at(result.Mean, i) * 1000 * requests
+			method.P50 += at(result.P50, i) * 1000 * requests
+			method.P90 += at(result.P90, i) * 1000 * requests
+			method.P95 += at(result.P95, i) * 1000 * requests
+			method.P99 += at(result.P99, i) * 1000 * requests
+			method.Min = math.Min(method.Min, at(result.Min, i)*1000)
+			method.Max = math.Max(method.Max, at(result.Max, i)*1000)
+			weighted += requests
+
+			first, last := at(result.FirstRequestTimestamp, i), at(result.LastResponseTimestamp, i)
+			if first > 0 {
+				firstAt := time.UnixMilli(int64(first * 1000))
+				if start.IsZero() || firstAt.Before(start) {
+					start = firstAt
+				}
+				points = append(points, types.TimeSeriesPoint{
+					Timestamp:  firstAt.UnixMilli(),
+					Value:      at(result.Mean, i) * 1000,
+					Count:      count,
+					ErrorCount: errors,
+				})
+			}
+			if lastAt := time.UnixMilli(int64(last * 1000)); last > 0 && lastAt.After(end) {
+				end = lastAt
+			}
+		}
+		if method.Count == 0 {
+			continue
+		}
+		method.Avg /= weighted
+		method.P50 /= weighted
+		method.P90 /= weighted
+		method.P95 /= weighted
+		method.P99 /= weighted
+		method.SuccessCount = method.Count - method.ErrorCount
+		method.ErrorRate = float64(method.ErrorCount) / float64(method.Count) * 100
+		method.SuccessRate = 100 - method.ErrorRate
+
+		client, ok := clientsMetrics[name]
+		if !ok {
+			client = newImportedClient(name)
+			clientsMetrics[name] = client
+		}
+		client.Methods[opts.Method] = method
+		sort.Slice(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+		client.TimeSeries[types.TimeSeriesLatencyAvg] = points
+	}
+	return clientsMetrics, start, end, nil
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeImportFixture(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write import fixture: %v", err)
+	}
+	return path
+}
+
+func TestImportResult_K6SummaryMapsScenariosToClients(t *testing.T) {
+	path := writeImportFixture(t, "summary.json", `{
+		"state": {"testRunDurationMs": 60000},
+		"metrics": {
+			"http_req_duration": {"avg": 99},
+			"http_req_duration{scenario:node_a,req_name:eth_call}": {"avg": 10, "min": 2, "max": 40, "med": 9, "p(90)": 20, "p(95)": 25, "p(99)": 35},
+			"http_reqs{scenario:node_a,req_name:eth_call}": {"count": 100},
+			"http_req_failed{req_name:eth_call,scenario:node_a}": {"rate": 0.02},
+			"http_req_duration{scenario:node_b,req_name:eth_call}": {"avg": 20},
+			"http_reqs{scenario:node_b,req_name:eth_call}": {"count": 50}
+		}
+	}`)
+
+	result, err := ImportResult(ImportFormatK6Summary, path, ImportOptions{Clients: map[string]string{"node_a": "geth"}})
+	if err != nil {
+		t.Fatalf("ImportResult: %v", err)
+	}
+	if len(result.ClientMetrics) != 1 {
+		t.Fatalf("expected only the mapped client, got %v", result.ClientMetrics)
+	}
+	geth := result.ClientMetrics["geth"]
+	if geth == nil {
+		t.Fatalf("geth missing from %v", result.ClientMetrics)
+	}
+	method := geth.Methods["eth_call"]
+	if method.Count != 100 || method.ErrorCount != 2 || method.P95 != 25 {
+		t.Fatalf("unexpected method summary %+v", method)
+	}
+	if geth.TotalRequests != 100 || geth.Latency.Avg != 10 {
+		t.Fatalf("client totals not finalized: %+v", geth)
+	}
+	if result.Duration != "1m0s" {
+		t.Fatalf("expected duration from state, got %q", result.Duration)
+	}
+}
+
+func TestImportResult_UntaggedK6Summary(t *testing.T) {
+	path := writeImportFixture(t, "summary.json", `{"metrics": {
+		"http_req_duration": {"values": {"avg": 12, "min": 1, "max": 80, "med": 10, "p(90)": 30, "p(95)": 40, "p(99)": 70}},
+		"http_reqs": {"values": {"count": 500}}
+	}}`)
+
+	result, err := ImportResult(ImportFormatK6Summary, path, ImportOptions{Clients: map[string]string{"default": "reth"}, Method: "eth_getLogs"})
+	if err != nil {
+		t.Fatalf("ImportResult: %v", err)
+	}
+	method, ok := result.ClientMetrics["reth"].Methods["eth_getLogs"]
+	if !ok || method.Count != 500 || method.P99 != 70 {
+		t.Fatalf("unexpected import %+v", result.ClientMetrics["reth"])
+	}
+}
+
+func TestImportResult_K6Stream(t *testing.T) {
+	path := writeStreamFixture(t, t.TempDir(), []string{
+		`{"type":"Metric","metric":"http_req_duration","data":{"type":"trend"}}`,
+		streamPoint("http_req_duration", "geth", "call", "1", 10),
+		streamPoint("http_req_duration", "geth", "call", "2", 30),
+		streamPoint("http_req_failed", "geth", "call", "2", 1),
+		streamPoint("http_req_failed", "geth", "call", "1", 0),
+		streamPoint("rpc_response_bytes", "geth", "call", "1", 100),
+	})
+
+	result, err := ImportResult(ImportFormatK6Stream, path, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportResult: %v", err)
+	}
+	geth := result.ClientMetrics["geth"]
+	method := geth.Methods["call"]
+	if method.Count != 2 || method.ErrorCount != 1 || method.Avg != 20 || method.P50 != 20 || method.ResponseBytesMax != 100 {
+		t.Fatalf("unexpected method summary %+v", method)
+	}
+	if geth.StatusCodes[200] != 2 || len(geth.TimeSeries["latency_avg"]) != 1 {
+		t.Fatalf("unexpected status codes or timeline: %v %v", geth.StatusCodes, geth.TimeSeries)
+	}
+	if result.StartTime == "" || result.EndTime == "" {
+		t.Fatalf("expected times from the stream, got start %q end %q", result.StartTime, result.EndTime)
+	}
+}
+
+func TestImportResult_FloodReportMergesRates(t *testing.T) {
+	path := writeImportFixture(t, "results.json", `{"results": {"node1": {
+		"target_rate": [10, 100],
+		"requests": [100, 300],
+		"success": [1, 0.99],
+		"min": [0.001, 0.002],
+		"mean": [0.010, 0.020],
+		"p50": [0.008, 0.016],
+		"p90": [0.015, 0.030],
+		"p95": [0.020, 0.040],
+		"p99": [0.030, 0.060],
+		"max": [0.050, 0.100],
+		"first_request_timestamp": [1700000000, 1700000030],
+		"last_response_timestamp": [1700000010, 1700000040]
+	}}}`)
+
+	result, err := ImportResult(ImportFormatFlood, path, ImportOptions{Clients: map[string]string{"node1": "erigon"}, Method: "eth_call"})
+	if err != nil {
+		t.Fatalf("ImportResult: %v", err)
+	}
+	erigon := result.ClientMetrics["erigon"]
+	method := erigon.Methods["eth_call"]
+	if method.Count != 400 || method.ErrorCount != 3 || method.Min != 1 || method.Max != 100 {
+		t.Fatalf("unexpected method summary %+v", method)
+	}
+	// Request-weighted: (10*100 + 20*300) / 400
+	if math.Abs(method.Avg-17.5) > 1e-9 {
+		t.Fatalf("expected weighted mean 17.5, got %v", method.Avg)
+	}
+	if len(erigon.TimeSeries["latency_avg"]) != 2 || result.Duration != "40s" {
+		t.Fatalf("unexpected timeline or duration: %v %q", erigon.TimeSeries, result.Duration)
+	}
+}
+
+func TestImportResult_NoMatchingClients(t *testing.T) {
+	path := writeImportFixture(t, "results.json", `{"results": {"node1": {"requests": [1], "mean": [0.01]}}}`)
+	if _, err := ImportResult(ImportFormatFlood, path, ImportOptions{Clients: map[string]string{"other": "geth"}}); err == nil {
+		t.Fatal("expected an error when no source matches --clients")
+	}
+	if _, err := ImportResult("csv", path, ImportOptions{}); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,169 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/sirupsen/logrus"
+
+	"github.com/jsonrpc-bench/runner/config"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// latencyTimelineStep matches the 1s remote-write push interval the generator
+// configures, so consecutive samples are consecutive pushes
+const latencyTimelineStep = time.Second
+
+// CollectLatencyTimeline queries Prometheus for each client's per-second mean
+// latency over [start, end]. k6 remote-writes trend stats cumulatively, so the
+// mean of one interval is recovered from the running average and request
+// count: (avg_t*count_t - avg_{t-1}*count_{t-1}) / (count_t - count_{t-1}).
+func CollectLatencyTimeline(cfg *config.Config, start, end time.Time, logger *logrus.Logger) (map[string][]types.TimeSeriesPoint, error) {
+	if cfg.Outputs == nil || cfg.Outputs.PrometheusRW == nil {
+		return nil, fmt.Errorf("latency timeline requires prometheus remote write output")
+	}
+
+	api, err := newPrometheusAPI(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	value, warnings, err := api.QueryRange(context.Background(),
+		fmt.Sprintf(`{__name__=~"k6_http_req_duration_avg|k6_http_reqs_total",testid="%s"}`, cfg.TestName),
+		v1.Range{Start: start, End: end.Add(latencyTimelineStep), Step: latencyTimelineStep},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query prometheus: %w", err)
+	}
+	for _, warning := range warnings {
+		logger.WithField("warning", warning).Debug("Prometheus range query warning")
+	}
+	matrix, ok := value.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("expected matrix type, got %s", value.Type())
+	}
+
+	return latencyTimelineFromMatrix(matrix), nil
+}
+
+// latencyTimelineFromMatrix turns cumulative k6_http_req_duration_avg and
+// k6_http_reqs_total series into per-interval mean latency per scenario.
+// Series are paired on their labels other than __name__: k6 tags both metrics
+// of a request identically.
+func latencyTimelineFromMatrix(matrix model.Matrix) map[string][]types.TimeSeriesPoint {
+	type seriesPair struct {
+		scenario string
+		isError  bool
+		avg      map[model.Time]float64
+		count    map[model.Time]float64
+	}
+	pairs := make(map[model.Fingerprint]*seriesPair)
+	for _, stream := range matrix {
+		name := stream.Metric[model.MetricNameLabel]
+		labels := stream.Metric.Clone()
+		delete(labels, model.MetricNameLabel)
+		fp := labels.Fingerprint()
+
+		pair, ok := pairs[fp]
+		if !ok {
+			_, isError := labels["error_code"]
+			pair = &seriesPair{
+				scenario: string(labels["scenario"]),
+				isError:  isError,
+				avg:      make(map[model.Time]float64),
+				count:    make(map[model.Time]float64),
+			}
+			pairs[fp] = pair
+		}
+		target := pair.count
+		if name == "k6_http_req_duration_avg" {
+			target = pair.avg
+		}
+		for _, sample := range stream.Values {
+			target[sample.Timestamp] = float64(sample.Value)
+		}
+	}
+
+	type bucket struct {
+		weighted float64
+		count    float64
+		errors   float64
+	}
+	buckets := make(map[string]map[model.Time]*bucket)
+	for _, pair := range pairs {
+		if pair.scenario == "" || len(pair.avg) == 0 || len(pair.count) == 0 {
+			continue
+		}
+		times := make([]model.Time, 0, len(pair.count))
+		for t := range pair.count {
+			if _, ok := pair.avg[t]; ok {
+				times = append(times, t)
+			}
+		}
+		sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+		// A series appears with its first requests, so it starts from zero
+		var prevWeighted, prevCount float64
+		for _, t := range times {
+			count := pair.count[t]
+			weighted := pair.avg[t] * count
+			deltaCount := count - prevCount
+			deltaWeighted := weighted - prevWeighted
+			prevCount, prevWeighted = count, weighted
+			if deltaCount <= 0 || deltaWeighted < 0 {
+				continue
+			}
+
+			byTime, ok := buckets[pair.scenario]
+			if !ok {
+				byTime = make(map[model.Time]*bucket)
+				buckets[pair.scenario] = byTime
+			}
+			b, ok := byTime[t]
+			if !ok {
+				b = &bucket{}
+				byTime[t] = b
+			}
+			b.weighted += deltaWeighted
+			b.count += deltaCount
+			if pair.isError {
+				b.errors += deltaCount
+			}
+		}
+	}
+
+	timeline := make(map[string][]types.TimeSeriesPoint, len(buckets))
+	for scenario, byTime := range buckets {
+		points := make([]types.TimeSeriesPoint, 0, len(byTime))
+		for t, b := range byTime {
+			points = append(points, types.TimeSeriesPoint{
+				Timestamp:  int64(t),
+				Value:      b.weighted / b.count * 1000, // seconds to milliseconds
+				Count:      int64(b.count),
+				ErrorCount: int64(b.errors),
+			})
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+		timeline[scenario] = points
+	}
+	return timeline
+}
+
+// ApplyLatencyTimeline stores each client's latency timeline under
+// types.TimeSeriesLatencyAvg
+func ApplyLatencyTimeline(clientsMetrics map[string]*types.ClientMetrics, timeline map[string][]types.TimeSeriesPoint) {
+	for name, points := range timeline {
+		client, ok := clientsMetrics[name]
+		if !ok || len(points) == 0 {
+			continue
+		}
+		if client.TimeSeries == nil {
+			client.TimeSeries = make(map[string][]types.TimeSeriesPoint)
+		}
+		client.TimeSeries[types.TimeSeriesLatencyAvg] = points
+	}
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+func series(name, scenario, status string, extra model.LabelSet, values ...float64) *model.SampleStream {
+	metric := model.Metric{
+		model.MetricNameLabel: model.LabelValue(name),
+		"scenario":            model.LabelValue(scenario),
+		"status":              model.LabelValue(status),
+		"testid":              "t",
+	}
+	for k, v := range extra {
+		metric[k] = v
+	}
+	stream := &model.SampleStream{Metric: metric}
+	for i, v := range values {
+		stream.Values = append(stream.Values, model.SamplePair{
+			Timestamp: model.Time(int64(i+1) * 1000),
+			Value:     model.SampleValue(v),
+		})
+	}
+	return stream
+}
+
+func TestLatencyTimelineFromMatrix_RecoversIntervalMeans(t *testing.T) {
+	matrix := model.Matrix{
+		// 10 requests at 10ms, then 10 more at 30ms (running avg 20ms), then none
+		series("k6_http_req_duration_avg", "geth", "200", nil, 0.010, 0.020, 0.020),
+		series("k6_http_reqs_total", "geth", "200", nil, 10, 20, 20),
+		// Failed requests are a separate tag set and count as errors
+		series("k6_http_req_duration_avg", "geth", "0", model.LabelSet{"error_code": "1050"}, 0.050),
+		series("k6_http_reqs_total", "geth", "0", model.LabelSet{"error_code": "1050"}, 2),
+	}
+
+	timeline := latencyTimelineFromMatrix(matrix)
+	points := timeline["geth"]
+	if len(points) != 2 {
+		t.Fatalf("expected 2 intervals with requests, got %+v", points)
+	}
+
+	first, second := points[0], points[1]
+	if first.Timestamp != 1000 || first.Count != 12 || first.ErrorCount != 2 {
+		t.Fatalf("unexpected first interval: %+v", first)
+	}
+	// (10*10ms + 2*50ms) / 12
+	if want := 200.0 / 12; first.Value < want-1e-9 || first.Value > want+1e-9 {
+		t.Fatalf("first interval mean = %v, want %v", first.Value, want)
+	}
+	if second.Timestamp != 2000 || second.Count != 10 || second.ErrorCount != 0 {
+		t.Fatalf("unexpected second interval: %+v", second)
+	}
+	if second.Value < 30-1e-9 || second.Value > 30+1e-9 {
+		t.Fatalf("second interval mean = %v, want 30", second.Value)
+	}
+}
+
+func TestApplyLatencyTimeline_SkipsUnknownClients(t *testing.T) {
+	clients := map[string]*types.ClientMetrics{"geth": {Name: "geth"}}
+	ApplyLatencyTimeline(clients, map[string][]types.TimeSeriesPoint{
+		"geth":    {{Timestamp: 1000, Value: 5, Count: 1}},
+		"unknown": {{Timestamp: 1000, Value: 5, Count: 1}},
+	})
+	if got := clients["geth"].TimeSeries[types.TimeSeriesLatencyAvg]; len(got) != 1 {
+		t.Fatalf("expected geth timeline to be set, got %v", got)
+	}
+}
@@ -0,0 +1,380 @@
+package metrics
+
+import (
+	"bufio"
+	"compress/gzip"
+	"container/heap"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// k6StreamLine is one line of k6's JSON output. Only "Point" lines carry
+// samples; "Metric" lines describe metrics and are skipped.
+type k6StreamLine struct {
+	Type   string `json:"type"`
+	Metric string `json:"metric"`
+	Data   struct {
+		Time     string            `json:"time"`
+		Value    float64           `json:"value"`
+		Tags     map[string]string `json:"tags"`
+		Metadata map[string]string `json:"metadata"`
+	} `json:"data"`
+}
+
+// RequestCapture is what ReadRequestStream extracts from a k6 request stream
+type RequestCapture struct {
+	// Slowest holds the N slowest requests per client and method, ordered by
+	// client, method and descending latency
+	Slowest []types.SlowRequest
+	// Latencies maps client -> request id -> latency in ms. Every client
+	// replays the same requests.csv rows, so equal ids are identical payloads.
+	Latencies map[string]map[string]float64
+	// Methods maps request id -> method (req_name)
+	Methods map[string]string
+	// Timeline holds each client's per-second mean latency
+	Timeline map[string][]types.TimeSeriesPoint
+	// Sampled is a uniform sample of requests across clients with their
+	// payloads, ordered by timestamp
+	Sampled []types.SlowRequest
+}
+
+// requestKey identifies one request of one client
+type requestKey struct {
+	client string
+	reqID  string
+}
+
+// slowHeap is a min-heap on latency so the fastest of the kept requests is
+// evicted first
+type slowHeap []types.SlowRequest
+
+func (h slowHeap) Len() int            { return len(h) }
+func (h slowHeap) Less(i, j int) bool  { return h[i].LatencyMs < h[j].LatencyMs }
+func (h slowHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *slowHeap) Push(x interface{}) { *h = append(*h, x.(types.SlowRequest)) }
+func (h *slowHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// ReadRequestStream reads the k6 JSON output at streamPath (gzipped when the
+// name ends in .gz) and keeps the slowest requests per client and method,
+// joined with their payloads from the k6 requests CSV at requestsPath, along
+// with every request's latency per client for paired comparison and a
+// per-second latency timeline per client. sampleN requests are also drawn
+// uniformly at random across clients. Requests are matched on the req_id
+// metadata the k6 script attaches.
+func ReadRequestStream(streamPath, requestsPath string, slowestN, sampleN int) (*RequestCapture, error) {
+	f, err := os.Open(streamPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request stream: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(streamPath, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gzipped request stream: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	heaps := make(map[[2]string]*slowHeap)
+	responseBytes := make(map[requestKey]int64)
+	capture := &RequestCapture{
+		Latencies: make(map[string]map[string]float64),
+		Methods:   make(map[string]string),
+	}
+	seconds := make(map[string]map[int64]*types.TimeSeriesPoint)
+	// Fixed seed: the same stream yields the same sample
+	sampler := rand.New(rand.NewSource(1))
+	var seen int
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var line k6StreamLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return nil, fmt.Errorf("failed to parse request stream line: %w", err)
+		}
+		if line.Type != "Point" {
+			continue
+		}
+		reqID := line.Data.Metadata["req_id"]
+		client := line.Data.Tags["scenario"]
+		if reqID == "" || client == "" {
+			continue
+		}
+
+		switch line.Metric {
+		case "rpc_response_bytes":
+			responseBytes[requestKey{client, reqID}] = int64(line.Data.Value)
+		case "http_req_duration":
+			latencies, ok := capture.Latencies[client]
+			if !ok {
+				latencies = make(map[string]float64)
+				capture.Latencies[client] = latencies
+			}
+			latencies[reqID] = line.Data.Value
+			capture.Methods[reqID] = line.Data.Tags["req_name"]
+			status, _ := strconv.Atoi(line.Data.Tags["status"])
+			if at, err := time.Parse(time.RFC3339Nano, line.Data.Time); err == nil {
+				addTimelineSample(seconds, client, at, line.Data.Value, status != 200)
+			}
+			req := types.SlowRequest{
+				RequestID: reqID,
+				Client:    client,
+				Method:    line.Data.Tags["req_name"],
+				RPCMethod: line.Data.Tags["rpc_method"],
+				LatencyMs: line.Data.Value,
+				Status:    status,
+				Timestamp: line.Data.Time,
+			}
+
+			// Reservoir sampling keeps every request equally likely
+			if sampleN > 0 {
+				seen++
+				if len(capture.Sampled) < sampleN {
+					capture.Sampled = append(capture.Sampled, req)
+				} else if j := sampler.Intn(seen); j < sampleN {
+					capture.Sampled[j] = req
+				}
+			}
+
+			if slowestN <= 0 {
+				continue
+			}
+			key := [2]string{req.Client, req.Method}
+			h, ok := heaps[key]
+			if !ok {
+				h = &slowHeap{}
+				heaps[key] = h
+			}
+			if h.Len() < slowestN {
+				heap.Push(h, req)
+			} else if req.LatencyMs > (*h)[0].LatencyMs {
+				(*h)[0] = req
+				heap.Fix(h, 0)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read request stream: %w", err)
+	}
+
+	capture.Timeline = make(map[string][]types.TimeSeriesPoint, len(seconds))
+	for client, byTime := range seconds {
+		points := make([]types.TimeSeriesPoint, 0, len(byTime))
+		for _, p := range byTime {
+			// Value holds the latency sum until here
+			p.Value /= float64(p.Count)
+			points = append(points, *p)
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].Timestamp < points[j].Timestamp })
+		capture.Timeline[client] = points
+	}
+
+	for _, h := range heaps {
+		capture.Slowest = append(capture.Slowest, *h...)
+	}
+	sort.Slice(capture.Slowest, func(i, j int) bool {
+		a, b := capture.Slowest[i], capture.Slowest[j]
+		if a.Client != b.Client {
+			return a.Client < b.Client
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.LatencyMs > b.LatencyMs
+	})
+
+	sort.Slice(capture.Sampled, func(i, j int) bool {
+		return capture.Sampled[i].Timestamp < capture.Sampled[j].Timestamp
+	})
+
+	wanted := make(map[string]struct{}, len(capture.Slowest)+len(capture.Sampled))
+	for _, requests := range [][]types.SlowRequest{capture.Slowest, capture.Sampled} {
+		for i := range requests {
+			req := &requests[i]
+			req.ResponseBytes = responseBytes[requestKey{req.Client, req.RequestID}]
+			wanted[req.RequestID] = struct{}{}
+		}
+	}
+	payloads, err := loadRequestPayloads(requestsPath, wanted)
+	if err != nil {
+		return nil, err
+	}
+	for _, requests := range [][]types.SlowRequest{capture.Slowest, capture.Sampled} {
+		for i := range requests {
+			if payload, ok := payloads[requests[i].RequestID]; ok {
+				requests[i].Payload = payload
+			}
+		}
+	}
+
+	return capture, nil
+}
+
+// addTimelineSample adds one request to its client's one-second bucket
+func addTimelineSample(seconds map[string]map[int64]*types.TimeSeriesPoint, client string, at time.Time, latencyMs float64, failed bool) {
+	byTime, ok := seconds[client]
+	if !ok {
+		byTime = make(map[int64]*types.TimeSeriesPoint)
+		seconds[client] = byTime
+	}
+	second := at.Truncate(time.Second).UnixMilli()
+	p, ok := byTime[second]
+	if !ok {
+		p = &types.TimeSeriesPoint{Timestamp: second}
+		byTime[second] = p
+	}
+	p.Value += latencyMs
+	p.Count++
+	if failed {
+		p.ErrorCount++
+	}
+}
+
+// loadRequestPayloads reads the k6 requests CSV (id, name, method, payload)
+// and returns the payloads of the wanted request ids
+func loadRequestPayloads(requestsPath string, wanted map[string]struct{}) (map[string]json.RawMessage, error) {
+	payloads := make(map[string]json.RawMessage, len(wanted))
+	if len(wanted) == 0 {
+		return payloads, nil
+	}
+
+	f, err := os.Open(requestsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open requests file: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read requests file: %w", err)
+		}
+		if len(record) < 4 {
+			continue
+		}
+		if _, ok := wanted[record[0]]; !ok {
+			continue
+		}
+		if json.Valid([]byte(record[3])) {
+			payloads[record[0]] = json.RawMessage(record[3])
+		}
+	}
+	return payloads, nil
+}
+
+// WriteRequestLatenciesCSV writes one row per request id with its method and
+// the latency of every client (empty when a client did not run the request)
+func WriteRequestLatenciesCSV(path string, capture *RequestCapture) error {
+	clients := make([]string, 0, len(capture.Latencies))
+	for client := range capture.Latencies {
+		clients = append(clients, client)
+	}
+	sort.Strings(clients)
+
+	ids := make([]string, 0, len(capture.Methods))
+	for id := range capture.Methods {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return ids[i] < ids[j]
+	})
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create request latencies file: %w", err)
+	}
+	defer f.Close()
+
+	writer := csv.NewWriter(f)
+	header := append([]string{"request_id", "method"}, clients...)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write request latencies: %w", err)
+	}
+	for _, id := range ids {
+		row := []string{id, capture.Methods[id]}
+		for _, client := range clients {
+			value := ""
+			if latency, ok := capture.Latencies[client][id]; ok {
+				value = strconv.FormatFloat(latency, 'f', 3, 64)
+			}
+			row = append(row, value)
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write request latencies: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadRequestLatenciesCSV reads a file written by WriteRequestLatenciesCSV
+// back into a capture holding only Latencies and Methods
+func ReadRequestLatenciesCSV(path string) (*RequestCapture, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request latencies file: %w", err)
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request latencies: %w", err)
+	}
+	if len(records) == 0 || len(records[0]) < 2 {
+		return nil, fmt.Errorf("request latencies file has no header")
+	}
+
+	clients := records[0][2:]
+	capture := &RequestCapture{
+		Latencies: make(map[string]map[string]float64, len(clients)),
+		Methods:   make(map[string]string, len(records)-1),
+	}
+	for _, client := range clients {
+		capture.Latencies[client] = make(map[string]float64)
+	}
+	for _, record := range records[1:] {
+		id := record[0]
+		capture.Methods[id] = record[1]
+		for i, client := range clients {
+			if 2+i >= len(record) || record[2+i] == "" {
+				continue
+			}
+			latency, err := strconv.ParseFloat(record[2+i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid latency for request %s on %s: %w", id, client, err)
+			}
+			capture.Latencies[client][id] = latency
+		}
+	}
+	return capture, nil
+}
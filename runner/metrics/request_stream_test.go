@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeStreamFixture(t *testing.T, dir string, lines []string) string {
+	t.Helper()
+	path := filepath.Join(dir, "requests-stream.json.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create stream fixture: %v", err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	for _, line := range lines {
+		if _, err := gz.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("write stream fixture: %v", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("close stream fixture: %v", err)
+	}
+	return path
+}
+
+func streamPoint(metric, client, reqName, reqID string, value float64) string {
+	return fmt.Sprintf(`{"type":"Point","metric":%q,"data":{"time":"2024-01-01T00:00:00Z","value":%g,"tags":{"scenario":%q,"req_name":%q,"rpc_method":"eth_call","status":"200"},"metadata":{"req_id":%q}}}`,
+		metric, value, client, reqName, reqID)
+}
+
+func TestReadRequestStream_KeepsSlowestPerClientAndMethod(t *testing.T) {
+	dir := t.TempDir()
+	stream := writeStreamFixture(t, dir, []string{
+		`{"type":"Metric","metric":"http_req_duration","data":{"type":"trend"}}`,
+		streamPoint("http_req_duration", "geth", "call", "1", 10),
+		streamPoint("http_req_duration", "geth", "call", "2", 50),
+		streamPoint("http_req_duration", "geth", "call", "3", 30),
+		streamPoint("rpc_response_bytes", "geth", "call", "2", 2048),
+		streamPoint("http_req_duration", "nethermind", "call", "1", 5),
+		// Points without a request id cannot be replayed and are ignored
+		streamPoint("http_req_duration", "geth", "call", "", 999),
+	})
+	requests := filepath.Join(dir, "requests.csv")
+	csv := "1,call,eth_call,\"{\"\"id\"\":1,\"\"method\"\":\"\"eth_call\"\",\"\"params\"\":[]}\"\n" +
+		"2,call,eth_call,\"{\"\"id\"\":2,\"\"method\"\":\"\"eth_call\"\",\"\"params\"\":[\"\"0x2\"\"]}\"\n" +
+		"3,call,eth_call,\"{\"\"id\"\":3,\"\"method\"\":\"\"eth_call\"\",\"\"params\"\":[\"\"0x3\"\"]}\"\n"
+	if err := os.WriteFile(requests, []byte(csv), 0644); err != nil {
+		t.Fatalf("write requests fixture: %v", err)
+	}
+
+	capture, err := ReadRequestStream(stream, requests, 2, 0)
+	if err != nil {
+		t.Fatalf("ReadRequestStream: %v", err)
+	}
+	if len(capture.Slowest) != 3 {
+		t.Fatalf("expected 3 requests (2 geth + 1 nethermind), got %d: %+v", len(capture.Slowest), capture.Slowest)
+	}
+
+	first, second := capture.Slowest[0], capture.Slowest[1]
+	if first.Client != "geth" || first.RequestID != "2" || first.LatencyMs != 50 {
+		t.Fatalf("expected slowest geth request id 2 at 50ms first, got %+v", first)
+	}
+	if second.RequestID != "3" {
+		t.Fatalf("expected geth request id 3 second, got %+v", second)
+	}
+	if first.ResponseBytes != 2048 || first.Status != 200 || first.RPCMethod != "eth_call" {
+		t.Fatalf("unexpected request details: %+v", first)
+	}
+	if string(first.Payload) != `{"id":2,"method":"eth_call","params":["0x2"]}` {
+		t.Fatalf("unexpected payload: %s", first.Payload)
+	}
+	if capture.Slowest[2].Client != "nethermind" || capture.Slowest[2].RequestID != "1" {
+		t.Fatalf("expected nethermind request last, got %+v", capture.Slowest[2])
+	}
+
+	if got := capture.Latencies["geth"]; len(got) != 3 || got["1"] != 10 || got["3"] != 30 {
+		t.Fatalf("expected all three geth latencies by request id, got %v", got)
+	}
+	if got := capture.Latencies["nethermind"]["1"]; got != 5 {
+		t.Fatalf("expected nethermind latency for request 1, got %v", got)
+	}
+	if capture.Methods["2"] != "call" {
+		t.Fatalf("expected request 2 mapped to method call, got %q", capture.Methods["2"])
+	}
+
+	// All fixture points share one second: geth's 10, 50 and 30ms average 30ms
+	if got := capture.Timeline["geth"]; len(got) != 1 || got[0].Count != 3 || got[0].Value != 30 {
+		t.Fatalf("unexpected geth timeline: %+v", got)
+	}
+}
+
+func TestReadRequestStream_SamplesRequestsWithPayloads(t *testing.T) {
+	dir := t.TempDir()
+	stream := writeStreamFixture(t, dir, []string{
+		streamPoint("http_req_duration", "geth", "call", "1", 10),
+		streamPoint("http_req_duration", "geth", "call", "2", 20),
+		streamPoint("http_req_duration", "nethermind", "call", "1", 5),
+		streamPoint("http_req_duration", "nethermind", "call", "2", 15),
+	})
+	requests := filepath.Join(dir, "requests.csv")
+	csv := "1,call,eth_call,\"{\"\"id\"\":1}\"\n2,call,eth_call,\"{\"\"id\"\":2}\"\n"
+	if err := os.WriteFile(requests, []byte(csv), 0644); err != nil {
+		t.Fatalf("write requests fixture: %v", err)
+	}
+
+	capture, err := ReadRequestStream(stream, requests, 0, 3)
+	if err != nil {
+		t.Fatalf("ReadRequestStream: %v", err)
+	}
+	if len(capture.Slowest) != 0 {
+		t.Fatalf("expected no slowest requests with slowestN=0, got %d", len(capture.Slowest))
+	}
+	if len(capture.Sampled) != 3 {
+		t.Fatalf("expected 3 sampled requests, got %d", len(capture.Sampled))
+	}
+	for _, req := range capture.Sampled {
+		if len(req.Payload) == 0 {
+			t.Fatalf("expected payload on sampled request %+v", req)
+		}
+	}
+}
+
+func TestWriteRequestLatenciesCSV(t *testing.T) {
+	capture := &RequestCapture{
+		Latencies: map[string]map[string]float64{
+			"geth":       {"2": 12.5, "10": 3},
+			"nethermind": {"2": 8},
+		},
+		Methods: map[string]string{"2": "call", "10": "logs"},
+	}
+	path := filepath.Join(t.TempDir(), "request_latencies.csv")
+	if err := WriteRequestLatenciesCSV(path, capture); err != nil {
+		t.Fatalf("WriteRequestLatenciesCSV: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	want := "request_id,method,geth,nethermind\n2,call,12.500,8.000\n10,logs,3.000,\n"
+	if string(data) != want {
+		t.Fatalf("unexpected CSV:\n%s\nwant:\n%s", data, want)
+	}
+
+	read, err := ReadRequestLatenciesCSV(path)
+	if err != nil {
+		t.Fatalf("ReadRequestLatenciesCSV: %v", err)
+	}
+	if !reflect.DeepEqual(read.Latencies, capture.Latencies) || !reflect.DeepEqual(read.Methods, capture.Methods) {
+		t.Fatalf("round trip mismatch: %+v, %+v", read.Latencies, read.Methods)
+	}
+}
+
+func TestReadRequestStream_MissingStream(t *testing.T) {
+	if _, err := ReadRequestStream(filepath.Join(t.TempDir(), "missing.json.gz"), "", 5, 0); err == nil {
+		t.Fatalf("expected an error for a missing stream")
+	}
+}
@@ -43,6 +43,7 @@ func newClientsMetricsSkeleton(cfg *config.Config) map[string]*types.ClientMetri
 			ConnectionMetrics: types.ConnectionMetrics{},
 			ErrorTypes:        make(map[string]int64),
 			StatusCodes:       make(map[int]int64),
+			ErrorClasses:      make(map[string]int64),
 			TotalRequests:     0,
 			TotalErrors:       0,
 			Latency: types.MetricSummary{
@@ -57,35 +58,14 @@ func newClientsMetricsSkeleton(cfg *config.Config) map[string]*types.ClientMetri
 func collectPrometheusClientsMetrics(cfg *config.Config, timestamp time.Time, summaryPath string, logger *logrus.Logger) (map[string]*types.ClientMetrics, error) {
 	clientsMetrics := newClientsMetricsSkeleton(cfg)
 
-	// Parse prometheus endpoint. The query API lives at the base URL; the
-	// remote-write target on cfg.Outputs.PrometheusRW.Endpoint already has
-	// the write path appended and would 404 when the Prometheus client
-	// composes <base>/api/v1/query on top of it.
-	queryAddr := cfg.Outputs.PrometheusRW.QueryURL
-	if queryAddr == "" {
-		queryAddr = cfg.Outputs.PrometheusRW.Endpoint
-	}
-	prometheusURL, err := url.Parse(queryAddr)
+	api, err := newPrometheusAPI(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("invalid prometheus endpoint: %w", err)
-	}
-	// Set basic auth if provided
-	if cfg.Outputs.PrometheusRW.BasicAuth.Username != "" && cfg.Outputs.PrometheusRW.BasicAuth.Password != "" {
-		prometheusURL.User = url.UserPassword(cfg.Outputs.PrometheusRW.BasicAuth.Username, cfg.Outputs.PrometheusRW.BasicAuth.Password)
+		return nil, err
 	}
 
-	// Create prometheus http api client
-	client, err := prometheus.NewClient(prometheus.Config{
-		Address: prometheusURL.String(),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
-	}
-	api := v1.NewAPI(client)
-
 	// Get benchmark metrics
 	query, _, err := api.Query(context.Background(),
-		fmt.Sprintf(`{__name__=~"k6_http_req.+",testid="%s"}`, cfg.TestName),
+		fmt.Sprintf(`{__name__=~"k6_(http_req.+|rpc_.+)",testid="%s"}`, cfg.TestName),
 		timestamp,
 	)
 	if err != nil {
@@ -166,6 +146,27 @@ func collectPrometheusClientsMetrics(cfg *config.Config, timestamp time.Time, su
 			} else if metricType == "blocked" || metricType == "connecting" {
 				client.ConnectionMetrics.TCPHandshakeTime += milliseconds
 			}
+		} else if strings.HasPrefix(string(metricName), "k6_rpc_corrected_duration_") {
+			// Coordinated-omission-corrected latency recorded by the k6 script
+			metricIndicator := strings.TrimPrefix(string(metricName), "k6_rpc_corrected_duration_")
+			if !setCorrectedStat(&method, metricIndicator, float64(metricValue)*1000) {
+				continue
+			}
+		} else if strings.HasPrefix(string(metricName), "k6_rpc_response_bytes_") {
+			// Response body sizes are plain numbers, not durations
+			metricIndicator := strings.TrimPrefix(string(metricName), "k6_rpc_response_bytes_")
+			if !setResponseBytesStat(&method, metricIndicator, float64(metricValue)) {
+				continue
+			}
+		} else if strings.EqualFold(string(metricName), "k6_rpc_errors_total") {
+			errorClass, ok := sample.Metric["error_class"]
+			if !ok {
+				continue
+			}
+			if method.ErrorClasses == nil {
+				method.ErrorClasses = make(map[string]int64)
+			}
+			method.ErrorClasses[string(errorClass)] += int64(metricValue)
 		} else if strings.EqualFold(string(metricName), "k6_http_reqs_total") { // Parse total requests metrics per tags
 			errorCode, isError := sample.Metric["error_code"]
 			method.Count += int64(metricValue)
@@ -196,6 +197,35 @@ func collectPrometheusClientsMetrics(cfg *config.Config, timestamp time.Time, su
 	return clientsMetrics, nil
 }
 
+// newPrometheusAPI creates a query client for the Prometheus k6 writes to
+func newPrometheusAPI(cfg *config.Config) (v1.API, error) {
+	// Parse prometheus endpoint. The query API lives at the base URL; the
+	// remote-write target on cfg.Outputs.PrometheusRW.Endpoint already has
+	// the write path appended and would 404 when the Prometheus client
+	// composes <base>/api/v1/query on top of it.
+	queryAddr := cfg.Outputs.PrometheusRW.QueryURL
+	if queryAddr == "" {
+		queryAddr = cfg.Outputs.PrometheusRW.Endpoint
+	}
+	prometheusURL, err := url.Parse(queryAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prometheus endpoint: %w", err)
+	}
+	// Set basic auth if provided
+	if cfg.Outputs.PrometheusRW.BasicAuth.Username != "" && cfg.Outputs.PrometheusRW.BasicAuth.Password != "" {
+		prometheusURL.User = url.UserPassword(cfg.Outputs.PrometheusRW.BasicAuth.Username, cfg.Outputs.PrometheusRW.BasicAuth.Password)
+	}
+
+	// Create prometheus http api client
+	client, err := prometheus.NewClient(prometheus.Config{
+		Address: prometheusURL.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
+	}
+	return v1.NewAPI(client), nil
+}
+
 // finalizeClientMetrics recomputes per-client totals, aggregate latency and
 // throughput from the collected per-method data, regardless of whether that
 // data came from Prometheus or the summary.json fallback.
@@ -210,6 +240,12 @@ func finalizeClientMetrics(clientsMetrics map[string]*types.ClientMetrics) {
 			totalRequests += method.Count
 			totalErrors += method.ErrorCount
 			totalSuccess += method.SuccessCount
+			for class, count := range method.ErrorClasses {
+				if client.ErrorClasses == nil {
+					client.ErrorClasses = make(map[string]int64)
+				}
+				client.ErrorClasses[class] += count
+			}
 		}
 
 		// Update client totals
@@ -250,6 +286,9 @@ func finalizeClientMetrics(clientsMetrics map[string]*types.ClientMetrics) {
 			}
 		}
 
+		finalizeCorrectedLatency(client)
+		finalizeResponseBytes(client)
+
 		if totalCount > 0 {
 			client.Latency.Avg = totalLatency / float64(totalCount)
 			client.Latency.Min = minLatency
@@ -268,6 +307,154 @@ func finalizeClientMetrics(clientsMetrics map[string]*types.ClientMetrics) {
 	}
 }
 
+// setCorrectedStat stores one k6 trend stat (avg, min, med, max, p90, p95,
+// p99) of the corrected-latency trend on the method summary. It returns false
+// for stats the summary has no field for.
+func setCorrectedStat(method *types.MetricSummary, indicator string, milliseconds float64) bool {
+	switch indicator {
+	case "avg":
+		method.CorrectedAvg = milliseconds
+	case "min":
+		method.CorrectedMin = milliseconds
+	case "med":
+		method.CorrectedP50 = milliseconds
+	case "max":
+		method.CorrectedMax = milliseconds
+	case "p90":
+		method.CorrectedP90 = milliseconds
+	case "p95":
+		method.CorrectedP95 = milliseconds
+	case "p99":
+		method.CorrectedP99 = milliseconds
+	default:
+		return false
+	}
+	return true
+}
+
+// finalizeCorrectedLatency aggregates the per-method corrected latency into
+// the client summary the same way raw latency is aggregated: count-weighted
+// average, averaged percentiles and the overall min/max. Methods without
+// corrected data are skipped so a partially populated run isn't dragged
+// towards zero.
+func finalizeCorrectedLatency(client *types.ClientMetrics) {
+	var totalLatency float64
+	var totalCount int64
+	var minLatency, maxLatency float64 = 999999, 0
+	var p50Sum, p90Sum, p95Sum, p99Sum float64
+	var methodCount int
+
+	for _, method := range client.Methods {
+		if !method.HasCorrected() {
+			continue
+		}
+		totalLatency += method.CorrectedAvg * float64(method.Count)
+		totalCount += method.Count
+		p50Sum += method.CorrectedP50
+		p90Sum += method.CorrectedP90
+		p95Sum += method.CorrectedP95
+		p99Sum += method.CorrectedP99
+		methodCount++
+
+		if method.CorrectedMin < minLatency {
+			minLatency = method.CorrectedMin
+		}
+		if method.CorrectedMax > maxLatency {
+			maxLatency = method.CorrectedMax
+		}
+	}
+
+	if methodCount == 0 || totalCount == 0 {
+		return
+	}
+	client.Latency.CorrectedAvg = totalLatency / float64(totalCount)
+	client.Latency.CorrectedMin = minLatency
+	client.Latency.CorrectedMax = maxLatency
+	client.Latency.CorrectedP50 = p50Sum / float64(methodCount)
+	client.Latency.CorrectedP90 = p90Sum / float64(methodCount)
+	client.Latency.CorrectedP95 = p95Sum / float64(methodCount)
+	client.Latency.CorrectedP99 = p99Sum / float64(methodCount)
+}
+
+// setResponseBytesStat stores one k6 trend stat of the response size trend on
+// the method summary. It returns false for stats the summary has no field for.
+func setResponseBytesStat(method *types.MetricSummary, indicator string, bytes float64) bool {
+	switch indicator {
+	case "avg":
+		method.ResponseBytesAvg = bytes
+	case "med":
+		method.ResponseBytesP50 = bytes
+	case "max":
+		method.ResponseBytesMax = bytes
+	case "p90":
+		method.ResponseBytesP90 = bytes
+	case "p95":
+		method.ResponseBytesP95 = bytes
+	case "p99":
+		method.ResponseBytesP99 = bytes
+	default:
+		return false
+	}
+	return true
+}
+
+// finalizeResponseBytes aggregates per-method response sizes into the client
+// summary: count-weighted average, averaged percentiles and the overall max.
+func finalizeResponseBytes(client *types.ClientMetrics) {
+	var totalBytes float64
+	var totalCount int64
+	var maxBytes float64
+	var p50Sum, p90Sum, p95Sum, p99Sum float64
+	var methodCount int
+
+	for _, method := range client.Methods {
+		if method.ResponseBytesMax == 0 {
+			continue
+		}
+		totalBytes += method.ResponseBytesAvg * float64(method.Count)
+		totalCount += method.Count
+		p50Sum += method.ResponseBytesP50
+		p90Sum += method.ResponseBytesP90
+		p95Sum += method.ResponseBytesP95
+		p99Sum += method.ResponseBytesP99
+		methodCount++
+		if method.ResponseBytesMax > maxBytes {
+			maxBytes = method.ResponseBytesMax
+		}
+	}
+
+	if methodCount == 0 || totalCount == 0 {
+		return
+	}
+	client.Latency.ResponseBytesAvg = totalBytes / float64(totalCount)
+	client.Latency.ResponseBytesMax = maxBytes
+	client.Latency.ResponseBytesP50 = p50Sum / float64(methodCount)
+	client.Latency.ResponseBytesP90 = p90Sum / float64(methodCount)
+	client.Latency.ResponseBytesP95 = p95Sum / float64(methodCount)
+	client.Latency.ResponseBytesP99 = p99Sum / float64(methodCount)
+}
+
+// ApplyBandwidth derives response bandwidth in MB/s for every method and
+// client from the average response size, the request count and the wall-clock
+// duration of the run. Collection doesn't know how long k6 actually ran, so
+// the caller supplies it once the run has finished.
+func ApplyBandwidth(clientsMetrics map[string]*types.ClientMetrics, duration time.Duration) {
+	seconds := duration.Seconds()
+	if seconds <= 0 {
+		return
+	}
+	for _, client := range clientsMetrics {
+		var clientBytes float64
+		for methodName, method := range client.Methods {
+			bytes := method.ResponseBytesAvg * float64(method.Count)
+			method.BandwidthMBps = bytes / seconds / 1e6
+			client.Methods[methodName] = method
+			clientBytes += bytes
+		}
+		client.Latency.BandwidthMBps = clientBytes / seconds / 1e6
+	}
+}
+
 func calculateStdDev(values types.MetricSummary) float64 {
 	return (values.Max - values.Min) / 4
 }
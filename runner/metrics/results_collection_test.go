@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/jsonrpc-bench/runner/config"
+	"github.com/jsonrpc-bench/runner/types"
 )
 
 func summaryForAllPairs(cfg *config.Config) map[string]k6MetricValue {
@@ -75,3 +76,92 @@ func TestCollectClientsMetrics_NilOutputs_UsesSummary(t *testing.T) {
 		t.Fatalf("expected %d clients, got %d", len(cfg.ResolvedClients), len(got))
 	}
 }
+
+func TestCollectClientsMetrics_AggregatesCorrectedLatency(t *testing.T) {
+	cfg := makeCfg()
+
+	metrics := summaryForAllPairs(cfg)
+	for _, client := range cfg.ResolvedClients {
+		for i, call := range cfg.Calls {
+			base := "{req_name:" + call.Name + ",scenario:" + client.Name + "}"
+			metrics["rpc_corrected_duration"+base] = k6MetricValue{
+				Avg: 20 * float64(i+1), Min: 2, Max: 500, Med: 10, P90: 40, P95: 60, P99: 200 * float64(i+1),
+			}
+		}
+	}
+	path := writeSummary(t, t.TempDir(), metrics)
+
+	logger, _ := makeLogger()
+	got, err := CollectClientsMetrics(cfg, time.Time{}, path, logger)
+	if err != nil {
+		t.Fatalf("CollectClientsMetrics returned error: %v", err)
+	}
+
+	for _, client := range cfg.ResolvedClients {
+		latency := got[client.Name].Latency
+		if latency.CorrectedP99 != 300 {
+			t.Errorf("%s CorrectedP99 = %.1f, want mean of method p99s (300)", client.Name, latency.CorrectedP99)
+		}
+		if latency.CorrectedAvg != 30 {
+			t.Errorf("%s CorrectedAvg = %.1f, want count-weighted 30", client.Name, latency.CorrectedAvg)
+		}
+		if latency.CorrectedMax != 500 || latency.CorrectedMin != 2 {
+			t.Errorf("%s corrected min/max = %.1f/%.1f", client.Name, latency.CorrectedMin, latency.CorrectedMax)
+		}
+		if latency.P99 != 90 {
+			t.Errorf("%s raw P99 changed: %.1f", client.Name, latency.P99)
+		}
+	}
+}
+
+func TestCollectClientsMetrics_ResponseBytesAndBandwidth(t *testing.T) {
+	cfg := makeCfg()
+
+	metrics := summaryForAllPairs(cfg)
+	for _, client := range cfg.ResolvedClients {
+		for i, call := range cfg.Calls {
+			base := "{req_name:" + call.Name + ",scenario:" + client.Name + "}"
+			size := 1000 * float64(i+1)
+			metrics["rpc_response_bytes"+base] = k6MetricValue{
+				Avg: size, Min: 10, Max: 4 * size, Med: size, P90: 2 * size, P95: 3 * size, P99: 4 * size,
+			}
+		}
+	}
+	path := writeSummary(t, t.TempDir(), metrics)
+
+	logger, _ := makeLogger()
+	got, err := CollectClientsMetrics(cfg, time.Time{}, path, logger)
+	if err != nil {
+		t.Fatalf("CollectClientsMetrics returned error: %v", err)
+	}
+	ApplyBandwidth(got, 10*time.Second)
+
+	for _, client := range cfg.ResolvedClients {
+		cm := got[client.Name]
+		method := cm.Methods["eth_chainId"]
+		if method.ResponseBytesP99 != 8000 || method.ResponseBytesAvg != 2000 {
+			t.Errorf("%s.eth_chainId response sizes not populated: %+v", client.Name, method)
+		}
+		// 200 requests x 2000 bytes over 10s = 0.04 MB/s
+		if diff := method.BandwidthMBps - 0.04; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s.eth_chainId BandwidthMBps = %v, want 0.04", client.Name, method.BandwidthMBps)
+		}
+		// (200x1000 + 200x2000) bytes over 10s = 0.06 MB/s
+		if diff := cm.Latency.BandwidthMBps - 0.06; diff > 1e-9 || diff < -1e-9 {
+			t.Errorf("%s client BandwidthMBps = %v, want 0.06", client.Name, cm.Latency.BandwidthMBps)
+		}
+		if cm.Latency.ResponseBytesAvg != 1500 || cm.Latency.ResponseBytesMax != 8000 {
+			t.Errorf("%s client response sizes = avg %.0f max %.0f", client.Name, cm.Latency.ResponseBytesAvg, cm.Latency.ResponseBytesMax)
+		}
+	}
+}
+
+func TestApplyBandwidth_ZeroDuration(t *testing.T) {
+	cm := map[string]*types.ClientMetrics{
+		"geth": {Methods: map[string]types.MetricSummary{"eth_call": {Count: 10, ResponseBytesAvg: 100}}},
+	}
+	ApplyBandwidth(cm, 0)
+	if bw := cm["geth"].Methods["eth_call"].BandwidthMBps; bw != 0 {
+		t.Errorf("BandwidthMBps = %v with zero duration, want 0", bw)
+	}
+}
@@ -142,6 +142,23 @@ func metricFloat(v k6MetricValue, valueKey string) float64 {
 	return 0
 }
 
+// lookupErrorClassSubmetric finds the rpc_errors submetric for one error
+// class. The generator registers it as {scenario:C,req_name:M,error_class:E};
+// the req_name-first ordering is tolerated for the same reason as in
+// lookupSubmetric.
+func lookupErrorClassSubmetric(s *k6Summary, clientName, methodName, class string) (k6MetricValue, bool) {
+	candidates := [2]string{
+		fmt.Sprintf("rpc_errors{scenario:%s,req_name:%s,error_class:%s}", clientName, methodName, class),
+		fmt.Sprintf("rpc_errors{req_name:%s,scenario:%s,error_class:%s}", methodName, clientName, class),
+	}
+	for _, k := range candidates {
+		if v, ok := s.Metrics[k]; ok {
+			return v, true
+		}
+	}
+	return k6MetricValue{}, false
+}
+
 func extractMethodFromSummary(s *k6Summary, clientName, methodName string) *types.MetricSummary {
 	if s == nil {
 		return nil
@@ -168,6 +185,23 @@ func extractMethodFromSummary(s *k6Summary, clientName, methodName string) *type
 			method.CoeffVar = (method.StdDev / method.Avg) * 100
 		}
 	}
+	if corrected, ok := lookupSubmetric(s, "rpc_corrected_duration", clientName, methodName); ok {
+		method.CorrectedMin = pickFloat(corrected.Min, metricFloat(corrected, "min"))
+		method.CorrectedMax = pickFloat(corrected.Max, metricFloat(corrected, "max"))
+		method.CorrectedAvg = pickFloat(corrected.Avg, metricFloat(corrected, "avg"))
+		method.CorrectedP50 = pickFloat(corrected.Med, metricFloat(corrected, "med"))
+		method.CorrectedP90 = pickFloat(corrected.P90, metricFloat(corrected, "p(90)"))
+		method.CorrectedP95 = pickFloat(corrected.P95, metricFloat(corrected, "p(95)"))
+		method.CorrectedP99 = pickFloat(corrected.P99, metricFloat(corrected, "p(99)"))
+	}
+	if size, ok := lookupSubmetric(s, "rpc_response_bytes", clientName, methodName); ok {
+		method.ResponseBytesAvg = pickFloat(size.Avg, metricFloat(size, "avg"))
+		method.ResponseBytesP50 = pickFloat(size.Med, metricFloat(size, "med"))
+		method.ResponseBytesP90 = pickFloat(size.P90, metricFloat(size, "p(90)"))
+		method.ResponseBytesP95 = pickFloat(size.P95, metricFloat(size, "p(95)"))
+		method.ResponseBytesP99 = pickFloat(size.P99, metricFloat(size, "p(99)"))
+		method.ResponseBytesMax = pickFloat(size.Max, metricFloat(size, "max"))
+	}
 	if hasReqs {
 		if reqs.Count > 0 {
 			method.Count = reqs.Count
@@ -176,6 +210,24 @@ func extractMethodFromSummary(s *k6Summary, clientName, methodName string) *type
 		}
 	}
 
+	for _, class := range types.ErrorClasses() {
+		v, ok := lookupErrorClassSubmetric(s, clientName, methodName, class)
+		if !ok {
+			continue
+		}
+		count := v.Count
+		if count == 0 {
+			count = int64(metricFloat(v, "count"))
+		}
+		if count == 0 {
+			continue
+		}
+		if method.ErrorClasses == nil {
+			method.ErrorClasses = make(map[string]int64)
+		}
+		method.ErrorClasses[class] = count
+	}
+
 	if failed, ok := lookupSubmetric(s, "http_req_failed", clientName, methodName); ok && method.Count > 0 {
 		failRate := pickFloat(failed.Rate, metricFloat(failed, "rate"))
 		method.ErrorCount = int64(float64(method.Count)*failRate + 0.5)
@@ -160,3 +160,104 @@ func TestApplySummaryFallback_MissingSummaryFile(t *testing.T) {
 		t.Errorf("expected one warn about unreadable summary, got:\n%s", out)
 	}
 }
+
+func TestExtractMethodFromSummary_CorrectedLatency(t *testing.T) {
+	s := &k6Summary{Metrics: map[string]k6MetricValue{
+		"http_req_duration{scenario:geth,req_name:eth_blockNumber}": {
+			Avg: 10, Min: 1, Max: 50, Med: 8, P90: 20, P95: 30, P99: 45,
+		},
+		"http_reqs{scenario:geth,req_name:eth_blockNumber}": {Count: 100},
+		"rpc_corrected_duration{scenario:geth,req_name:eth_blockNumber}": {
+			Avg: 40, Min: 1, Max: 900, Med: 12, P90: 200, P95: 400, P99: 850,
+		},
+	}}
+
+	got := extractMethodFromSummary(s, "geth", "eth_blockNumber")
+	if got == nil {
+		t.Fatal("expected a method summary")
+	}
+	if got.P99 != 45 {
+		t.Errorf("raw P99 = %.1f, want 45", got.P99)
+	}
+	if got.CorrectedP99 != 850 || got.CorrectedP50 != 12 || got.CorrectedMax != 900 || got.CorrectedAvg != 40 {
+		t.Errorf("corrected latency not populated: %+v", got)
+	}
+
+	corrected := got.WithLatencyMode(types.LatencyModeCorrected)
+	if corrected.P99 != 850 || corrected.P95 != 400 || corrected.Avg != 40 {
+		t.Errorf("corrected view = %+v", corrected)
+	}
+	if raw := got.WithLatencyMode(types.LatencyModeRaw); raw.P99 != 45 {
+		t.Errorf("raw view P99 = %.1f, want 45", raw.P99)
+	}
+}
+
+func TestExtractMethodFromSummary_NoCorrectedTrend(t *testing.T) {
+	s := &k6Summary{Metrics: map[string]k6MetricValue{
+		"http_req_duration{scenario:geth,req_name:eth_blockNumber}": {Avg: 10, Max: 50, P99: 45},
+		"http_reqs{scenario:geth,req_name:eth_blockNumber}":         {Count: 100},
+	}}
+
+	got := extractMethodFromSummary(s, "geth", "eth_blockNumber")
+	if got == nil {
+		t.Fatal("expected a method summary")
+	}
+	if got.HasCorrected() {
+		t.Errorf("shared-iterations run should have no corrected latency: %+v", got)
+	}
+	// Asking for corrected latency without data falls back to raw
+	if view := got.WithLatencyMode(types.LatencyModeCorrected); view.P99 != 45 {
+		t.Errorf("corrected view without data P99 = %.1f, want raw 45", view.P99)
+	}
+}
+
+func TestExtractMethodFromSummary_ErrorClasses(t *testing.T) {
+	s := &k6Summary{Metrics: map[string]k6MetricValue{
+		"http_req_duration{scenario:geth,req_name:eth_getLogs}":                               {Avg: 10, Max: 50},
+		"http_reqs{scenario:geth,req_name:eth_getLogs}":                                       {Count: 100},
+		"http_req_failed{scenario:geth,req_name:eth_getLogs}":                                 {Rate: 0.02},
+		"rpc_errors{scenario:geth,req_name:eth_getLogs,error_class:rpc_limit_exceeded}":       {Count: 8},
+		"rpc_errors{scenario:geth,req_name:eth_getLogs,error_class:transport_timeout}":        {Values: map[string]float64{"count": 2}},
+		"rpc_errors{scenario:geth,req_name:eth_getLogs,error_class:http_5xx}":                 {Count: 0},
+		"rpc_errors{scenario:nethermind,req_name:eth_getLogs,error_class:rpc_limit_exceeded}": {Count: 50},
+	}}
+
+	got := extractMethodFromSummary(s, "geth", "eth_getLogs")
+	if got == nil {
+		t.Fatal("expected a method summary")
+	}
+	want := map[string]int64{
+		types.ErrorClassRPCLimitExceeded: 8,
+		types.ErrorClassTransportTimeout: 2,
+	}
+	if len(got.ErrorClasses) != len(want) {
+		t.Fatalf("ErrorClasses = %v, want %v", got.ErrorClasses, want)
+	}
+	for class, count := range want {
+		if got.ErrorClasses[class] != count {
+			t.Errorf("ErrorClasses[%s] = %d, want %d", class, got.ErrorClasses[class], count)
+		}
+	}
+}
+
+func TestCollectClientsMetrics_SumsErrorClassesPerClient(t *testing.T) {
+	cfg := makeCfg()
+	metrics := summaryForAllPairs(cfg)
+	metrics["rpc_errors{scenario:geth,req_name:eth_blockNumber,error_class:http_429}"] = k6MetricValue{Count: 3}
+	metrics["rpc_errors{scenario:geth,req_name:eth_chainId,error_class:http_429}"] = k6MetricValue{Count: 4}
+	metrics["rpc_errors{scenario:geth,req_name:eth_chainId,error_class:rpc_method_not_found}"] = k6MetricValue{Count: 1}
+	path := writeSummary(t, t.TempDir(), metrics)
+
+	logger, _ := makeLogger()
+	got, err := collectSummaryClientsMetrics(cfg, path, logger)
+	if err != nil {
+		t.Fatalf("collectSummaryClientsMetrics returned error: %v", err)
+	}
+	geth := got["geth"].ErrorClasses
+	if geth[types.ErrorClassHTTP429] != 7 || geth[types.ErrorClassRPCMethodNotFound] != 1 {
+		t.Errorf("geth ErrorClasses = %v", geth)
+	}
+	if len(got["nethermind"].ErrorClasses) != 0 {
+		t.Errorf("nethermind should have no error classes, got %v", got["nethermind"].ErrorClasses)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jsonrpc-bench/runner/storage"
 	"github.com/jsonrpc-bench/runner/types"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
@@ -66,6 +67,9 @@ func (sc *SystemCollector) Start() error {
 		}
 	}
 
+	// Prime host CPU so the first sample covers the first interval
+	cpu.Percent(0, false)
+
 	go sc.collect()
 	return nil
 }
@@ -114,6 +118,7 @@ func (sc *SystemCollector) collect() {
 // collectMetric collects a single metric snapshot
 func (sc *SystemCollector) collectMetric() types.SystemMetrics {
 	metric := types.SystemMetrics{
+		Timestamp:      time.Now().UnixMilli(),
 		GoroutineCount: runtime.NumGoroutine(),
 	}
 
@@ -122,6 +127,11 @@ func (sc *SystemCollector) collectMetric() types.SystemMetrics {
 		metric.CPUUsage = cpuPercent
 	}
 
+	// Host CPU usage since the previous sample, k6 included
+	if hostPercent, err := cpu.Percent(0, false); err == nil && len(hostPercent) > 0 {
+		metric.HostCPUPercent = hostPercent[0]
+	}
+
 	// Memory usage
 	if memInfo, err := mem.VirtualMemory(); err == nil {
 		metric.MemoryPercent = memInfo.UsedPercent
@@ -167,12 +177,13 @@ func (sc *SystemCollector) GetAverageMetrics() types.SystemMetrics {
 	}
 
 	avg := types.SystemMetrics{}
-	var cpuSum, memSum, memPercentSum float64
+	var cpuSum, hostCPUSum, memSum, memPercentSum float64
 	var netSentSum, netRecvSum, diskReadSum, diskWriteSum, connSum int64
 	var goroutineSum int
 
 	for _, m := range metrics {
 		cpuSum += m.CPUUsage
+		hostCPUSum += m.HostCPUPercent
 		memSum += m.MemoryUsage
 		memPercentSum += m.MemoryPercent
 		netSentSum += m.NetworkBytesSent
@@ -185,6 +196,7 @@ func (sc *SystemCollector) GetAverageMetrics() types.SystemMetrics {
 
 	n := float64(len(metrics))
 	avg.CPUUsage = cpuSum / n
+	avg.HostCPUPercent = hostCPUSum / n
 	avg.MemoryUsage = memSum / n
 	avg.MemoryPercent = memPercentSum / n
 	avg.NetworkBytesSent = netSentSum / int64(n)
@@ -219,5 +231,8 @@ func GetEnvironmentInfo() types.EnvironmentInfo {
 	// Network type (simplified - could be enhanced)
 	env.NetworkType = "ethernet" // Default assumption
 
+	// Commit of the checkout the runner was started from, if any
+	env.GitCommit, _ = storage.GitInfo()
+
 	return env
 }
@@ -2,7 +2,6 @@ package storage
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -57,6 +56,25 @@ func NewHistoricStorage(cfg *config.StorageConfig, logger *logrus.Logger) (*Hist
 
 // SaveRun saves a benchmark result to both file and database storage
 func (h *HistoricStorage) SaveRun(result *types.BenchmarkResult, cfg *config.Config) (*types.HistoricRun, error) {
+	return h.saveRun(result, cfg, time.Now(), extractTestName(cfg))
+}
+
+// SaveImportedRun saves a result imported from another tool that ran at the
+// given time. Unlike benchmark runs it is stored under cfg.TestName.
+func (h *HistoricStorage) SaveImportedRun(result *types.BenchmarkResult, cfg *config.Config, at time.Time) (*types.HistoricRun, error) {
+	testName := extractTestName(cfg)
+	if cfg != nil && cfg.TestName != "" {
+		testName = cfg.TestName
+	}
+	return h.saveRun(result, cfg, at, testName)
+}
+
+func (h *HistoricStorage) saveRun(result *types.BenchmarkResult, cfg *config.Config, at time.Time, testName string) (*types.HistoricRun, error) {
+	// Results built in memory already have the current shape
+	if result.SchemaVersion == 0 {
+		result.SchemaVersion = types.ResultSchemaVersion
+	}
+
 	// Generate run ID
 	runID := h.generateRunID()
 	h.log.WithField("run_id", runID).Info("Saving historic run")
@@ -78,6 +96,13 @@ func (h *HistoricStorage) SaveRun(result *types.BenchmarkResult, cfg *config.Con
 		// Continue execution even if saving config fails
 	}
 
+	// The manifest covers every file of the run directory, so it comes last
+	manifest := NewBenchmarkManifest(types.ManifestKindHistoric, cfg, result)
+	manifest.RunID = runID
+	if err := WriteManifest(runDir, manifest); err != nil {
+		h.log.WithError(err).Error("Failed to write run manifest")
+	}
+
 	// Calculate config hash
 	configHash := h.calculateConfigHash(cfg)
 
@@ -93,10 +118,10 @@ func (h *HistoricStorage) SaveRun(result *types.BenchmarkResult, cfg *config.Con
 	// Create historic run record
 	run := &types.HistoricRun{
 		ID:            runID,
-		Timestamp:     time.Now(),
+		Timestamp:     at,
 		GitCommit:     gitCommit,
 		GitBranch:     gitBranch,
-		TestName:      extractTestName(cfg),
+		TestName:      testName,
 		Description:   extractDescription(cfg),
 		ConfigHash:    configHash,
 		ResultPath:    runDir,
@@ -200,7 +225,12 @@ func (h *HistoricStorage) getGitInfo() (commit, branch string) {
 	if !h.gitEnabled {
 		return "", ""
 	}
+	return GitInfo()
+}
 
+// GitInfo returns the commit and branch of the git checkout the runner was
+// started from, or empty strings outside a checkout
+func GitInfo() (commit, branch string) {
 	// Get commit hash
 	if cmd := exec.Command("git", "rev-parse", "HEAD"); cmd != nil {
 		if output, err := cmd.Output(); err == nil {
@@ -230,15 +260,52 @@ func (h *HistoricStorage) createRunDirectory(runID string) string {
 func (h *HistoricStorage) copyResults(result *types.BenchmarkResult, destDir string) error {
 	// This would copy HTML report, JSON results, CSV exports etc.
 	// Implementation depends on how results are structured
+
+	// Captured slow requests only exist in memory, so persist them here to
+	// keep them replayable from the run directory
+	if len(result.SlowestRequests) > 0 {
+		data, err := json.MarshalIndent(result.SlowestRequests, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal slowest requests: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(destDir, "slowest_requests.json"), data, 0644); err != nil {
+			return fmt.Errorf("failed to write slowest requests: %w", err)
+		}
+	}
+
+	// The full result lets LoadRun return more than the database summary
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(destDir, "results.json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+
+	// Per-request latencies are written next to the run outputs when
+	// requests were captured
+	if result.ResponsesDir != "" {
+		data, err := os.ReadFile(filepath.Join(result.ResponsesDir, "request_latencies.csv"))
+		if err == nil {
+			if err := os.WriteFile(filepath.Join(destDir, "request_latencies.csv"), data, 0644); err != nil {
+				return fmt.Errorf("failed to copy request latencies: %w", err)
+			}
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read request latencies: %w", err)
+		}
+	}
 	return nil
 }
 
-// calculateConfigHash creates hash of configuration for comparison
+// calculateConfigHash creates hash of configuration for comparison, the
+// manifest's configHash shortened to 16 characters. It is empty when the
+// config cannot be encoded.
 func (h *HistoricStorage) calculateConfigHash(cfg *config.Config) string {
-	// Create a deterministic hash of the configuration
-	configStr := fmt.Sprintf("%+v", cfg)
-	hash := sha256.Sum256([]byte(configStr))
-	return fmt.Sprintf("%x", hash)[:16]
+	hash := configHash(cfg)
+	if len(hash) < 16 {
+		return hash
+	}
+	return hash[:16]
 }
 
 // convertToTimeSeriesMetrics converts BenchmarkResult to time-series metrics
@@ -247,7 +314,7 @@ func (h *HistoricStorage) convertToTimeSeriesMetrics(result *types.BenchmarkResu
 
 	// Convert client metrics to time-series format
 	for clientName, clientMetrics := range result.ClientMetrics {
-		timestamp := time.Now()
+		timestamp := run.Timestamp
 
 		// Add all latency percentiles and metrics for overall client performance
 		metrics = append(metrics,
@@ -310,6 +377,29 @@ func (h *HistoricStorage) convertToTimeSeriesMetrics(result *types.BenchmarkResu
 			},
 		)
 
+		// Efficiency metrics, trended alongside latency when resources were sampled
+		if eff := result.Efficiency[clientName]; eff != nil {
+			efficiencyValues := []struct {
+				name  string
+				value float64
+			}{
+				{types.MetricRequestsPerCPUSecond, eff.RequestsPerCPUSecond},
+				{types.MetricRPSPerGB, eff.RPSPerGB},
+				{types.MetricP99LatencyGB, eff.P99LatencyGB},
+				{types.MetricRPSPerWatt, eff.RPSPerWatt},
+			}
+			for _, v := range efficiencyValues {
+				if v.value == 0 {
+					continue
+				}
+				metrics = append(metrics, types.TimeSeriesMetric{
+					Time: timestamp, RunID: run.ID, Client: clientName, Method: "all",
+					MetricName: v.name, Value: v.value,
+					Tags: map[string]string{"git_commit": run.GitCommit, "test_name": run.TestName},
+				})
+			}
+		}
+
 		// Add method-specific metrics with all percentiles
 		for methodName, methodMetrics := range clientMetrics.Methods {
 			metrics = append(metrics,
@@ -385,31 +475,129 @@ func (h *HistoricStorage) LoadRun(runID string) (*types.BenchmarkResult, error)
 		return nil, err
 	}
 
+	// Files of runs saved with a manifest are checked before they are read
+	if _, err := os.Stat(filepath.Join(run.ResultPath, types.ManifestFilename)); err == nil {
+		if _, verification, err := VerifyManifest(run.ResultPath); err != nil {
+			h.log.WithError(err).WithField("run_id", sanitize.LogValue(runID)).Warn("Failed to verify run manifest")
+		} else if !verification.OK() {
+			h.log.WithFields(logrus.Fields{
+				"run_id":   sanitize.LogValue(runID),
+				"missing":  verification.Missing,
+				"modified": verification.Modified,
+				"unlisted": verification.Unlisted,
+			}).Warn("Run files do not match their manifest")
+		}
+	}
+
 	// Load full results from file storage if available
 	resultPath := filepath.Join(run.ResultPath, "results.json")
-	if _, err := os.Stat(resultPath); err == nil {
-		// Load from file - implementation depends on file format
+	if data, err := os.ReadFile(resultPath); err == nil {
+		var result types.BenchmarkResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", resultPath, err)
+		}
+		return &result, nil
 	}
 
-	// For now, return minimal result based on database data
-	return &types.BenchmarkResult{
-		Duration: run.Duration,
-		// ClientMetrics would be reconstructed from database
-	}, nil
+	// Runs saved before results.json was written are rebuilt from their
+	// time-series metrics
+	metrics, err := h.db.QueryMetrics(types.MetricQuery{RunID: runID})
+	if err != nil {
+		return nil, err
+	}
+	return resultFromMetrics(run, metrics), nil
+}
+
+// VerifyRun checks a historic run's directory against its manifest
+func (h *HistoricStorage) VerifyRun(runID string) (*types.RunManifest, *types.ManifestVerification, error) {
+	run, err := h.db.GetRun(runID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return VerifyManifest(run.ResultPath)
+}
+
+// resultFromMetrics rebuilds client and method summaries from the metrics
+// written by convertToTimeSeriesMetrics; method "all" is the client level
+func resultFromMetrics(run *types.HistoricRun, metrics []types.TimeSeriesMetric) *types.BenchmarkResult {
+	result := &types.BenchmarkResult{
+		SchemaVersion: types.ResultSchemaVersion,
+		Timestamp:     run.Timestamp.Format(time.DateTime),
+		Duration:      run.Duration,
+		ClientMetrics: make(map[string]*types.ClientMetrics),
+	}
+	summaries := make(map[string]map[string]*types.MetricSummary)
+	for _, m := range metrics {
+		if _, ok := summaries[m.Client]; !ok {
+			summaries[m.Client] = make(map[string]*types.MetricSummary)
+		}
+		s, ok := summaries[m.Client][m.Method]
+		if !ok {
+			s = &types.MetricSummary{}
+			summaries[m.Client][m.Method] = s
+		}
+		switch m.MetricName {
+		case types.MetricLatencyAvg:
+			s.Avg = m.Value
+		case types.MetricLatencyMin:
+			s.Min = m.Value
+		case types.MetricLatencyMax:
+			s.Max = m.Value
+		case types.MetricLatencyP50:
+			s.P50 = m.Value
+		case types.MetricLatencyP90:
+			s.P90 = m.Value
+		case types.MetricLatencyP95:
+			s.P95 = m.Value
+		case types.MetricLatencyP99:
+			s.P99 = m.Value
+		case types.MetricSuccessRate:
+			s.SuccessRate = m.Value
+		case types.MetricErrorRate:
+			s.ErrorRate = m.Value
+		case types.MetricThroughput:
+			s.Throughput = m.Value
+		case "total_requests":
+			s.Count = int64(m.Value)
+		}
+	}
+
+	for clientName, methods := range summaries {
+		client := &types.ClientMetrics{Name: clientName, Methods: make(map[string]types.MetricSummary)}
+		for method, s := range methods {
+			if method == "all" {
+				client.Latency = *s
+				client.TotalRequests = s.Count
+				client.ErrorRate = s.ErrorRate
+				client.TotalErrors = int64(float64(s.Count) * s.ErrorRate / 100)
+				continue
+			}
+			client.Methods[method] = *s
+		}
+		result.ClientMetrics[clientName] = client
+	}
+	return result
 }
 
 // Helper functions for extracting data from config and results
+// extractTestName names benchmark runs. Existing history, baselines and
+// regression lookups are all keyed on "default_test", so it is kept.
 func extractTestName(cfg *config.Config) string {
-	// Extract test name from config
 	return "default_test"
 }
 
 func extractDescription(cfg *config.Config) string {
-	return ""
+	if cfg == nil {
+		return ""
+	}
+	return cfg.Description
 }
 
 func extractTags(cfg *config.Config) []string {
-	return []string{}
+	if cfg == nil || cfg.Tags == nil {
+		return []string{}
+	}
+	return cfg.Tags
 }
 
 // extractClients extracts client names from benchmark results
@@ -759,16 +947,25 @@ func diffClientMetrics(base, curr map[string]*types.ClientMetrics, runID, baseli
 				continue
 			}
 
-			for _, m := range []struct {
+			type metricDiff struct {
 				name        string
 				base, curr  float64
 				higherWorse bool
 				threshold   float64
-			}{
+			}
+			diffs := []metricDiff{
 				{"avg_latency", baseMethod.Avg, currMethod.Avg, true, 5},
 				{"p95_latency", baseMethod.P95, currMethod.P95, true, 5},
 				{"success_rate", baseMethod.SuccessRate, currMethod.SuccessRate, false, 1},
-			} {
+			}
+			if baseMethod.HasCorrected() && currMethod.HasCorrected() {
+				diffs = append(diffs,
+					metricDiff{"corrected_p95_latency", baseMethod.CorrectedP95, currMethod.CorrectedP95, true, 5},
+					metricDiff{"corrected_p99_latency", baseMethod.CorrectedP99, currMethod.CorrectedP99, true, 5},
+				)
+			}
+
+			for _, m := range diffs {
 				delta := m.curr - m.base
 				pct := pctDelta(m.base, m.curr)
 				worse := (m.higherWorse && pct > m.threshold) || (!m.higherWorse && delta < -m.threshold)
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsonrpc-bench/runner/config"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// TestResultFromMetrics tests that client and method summaries are rebuilt
+// from the stored time-series metrics of a run
+func TestResultFromMetrics(t *testing.T) {
+	run := &types.HistoricRun{ID: "run-1", Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Duration: "1m0s"}
+	metric := func(method, name string, value float64) types.TimeSeriesMetric {
+		return types.TimeSeriesMetric{RunID: run.ID, Client: "geth", Method: method, MetricName: name, Value: value}
+	}
+	metrics := []types.TimeSeriesMetric{
+		metric("all", types.MetricLatencyP99, 120),
+		metric("all", types.MetricErrorRate, 2),
+		metric("all", types.MetricThroughput, 50),
+		metric("all", "total_requests", 1000),
+		metric("eth_call", types.MetricLatencyP50, 8),
+		metric("eth_call", types.MetricErrorRate, 1),
+	}
+
+	result := resultFromMetrics(run, metrics)
+
+	require.Contains(t, result.ClientMetrics, "geth")
+	client := result.ClientMetrics["geth"]
+	assert.Equal(t, int64(1000), client.TotalRequests)
+	assert.Equal(t, int64(20), client.TotalErrors)
+	assert.Equal(t, 120.0, client.Latency.P99)
+	assert.Equal(t, 50.0, client.Latency.Throughput)
+	require.Contains(t, client.Methods, "eth_call")
+	assert.Equal(t, 8.0, client.Methods["eth_call"].P50)
+	assert.Equal(t, 1.0, client.Methods["eth_call"].ErrorRate)
+	assert.NotContains(t, client.Methods, "all")
+	assert.Equal(t, "2025-01-02 03:04:05", result.Timestamp)
+	assert.Equal(t, "1m0s", result.Duration)
+}
+
+// TestCalculateConfigHash tests that the hash depends on the config's content,
+// not on the addresses of its calls
+func TestCalculateConfigHash(t *testing.T) {
+	h := &HistoricStorage{}
+	build := func() *config.Config {
+		return &config.Config{TestName: "mixed", Calls: []*config.Call{{Name: "balance", Method: "eth_getBalance", Weight: 1}}}
+	}
+
+	hash := h.calculateConfigHash(build())
+	assert.Len(t, hash, 16)
+	assert.Equal(t, hash, h.calculateConfigHash(build()))
+
+	changed := build()
+	changed.Calls[0].Weight = 2
+	assert.NotEqual(t, hash, h.calculateConfigHash(changed))
+}
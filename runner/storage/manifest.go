@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/jsonrpc-bench/runner/config"
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// NewBenchmarkManifest fills a manifest's reproducibility metadata from a
+// benchmark's configuration and result. Artifacts are added by WriteManifest.
+func NewBenchmarkManifest(kind string, cfg *config.Config, result *types.BenchmarkResult) *types.RunManifest {
+	manifest := &types.RunManifest{
+		Kind:         kind,
+		ConfigHash:   configHash(cfg),
+		RunnerCommit: result.Environment.GitCommit,
+		Environment:  result.Environment,
+	}
+	manifest.Hostname, _ = os.Hostname()
+	if cfg != nil {
+		manifest.TestName = cfg.TestName
+		// A calls file is sent as is; otherwise requests are drawn with the seed
+		if cfg.CallsFile != "" {
+			manifest.RequestSetHash, _ = HashFile(cfg.CallsFile)
+		} else if cfg.Seed != 0 {
+			seed := cfg.Seed
+			manifest.Seed = &seed
+			if result.ResponsesDir != "" {
+				// generator.K6RequestsFilename; generator imports storage
+				manifest.RequestSetHash, _ = HashFile(filepath.Join(result.ResponsesDir, "requests.csv"))
+			}
+		}
+	}
+	if result.Environment.K6Version != "" {
+		manifest.EngineVersion = "k6 " + result.Environment.K6Version
+	}
+	for name, client := range result.ClientMetrics {
+		if client.Version == "" {
+			continue
+		}
+		if manifest.ClientVersions == nil {
+			manifest.ClientVersions = make(map[string]string)
+		}
+		manifest.ClientVersions[name] = client.Version
+	}
+	return manifest
+}
+
+// configHash is the SHA-256 hex digest of the configuration's JSON form
+func configHash(cfg *config.Config) string {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// HashFile returns the SHA-256 hex digest of a file's content
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WriteManifest hashes every file under dir into the manifest's artifacts and
+// writes it to dir/manifest.json. Use it for directories that belong to a
+// single run.
+func WriteManifest(dir string, manifest *types.RunManifest) error {
+	return writeManifest(dir, manifest, nil)
+}
+
+// WriteScopedManifest is WriteManifest for a directory shared with other runs:
+// only the files include selects are hashed, and verification ignores the
+// other files of dir. include gets the slash-separated path relative to dir.
+func WriteScopedManifest(dir string, manifest *types.RunManifest, include func(rel string, info fs.FileInfo) bool) error {
+	manifest.Scoped = true
+	return writeManifest(dir, manifest, include)
+}
+
+// ModifiedSince selects the files written at or after since, i.e. by the run
+// that started then. since is truncated to the second for filesystems with
+// coarse timestamps.
+func ModifiedSince(since time.Time) func(rel string, info fs.FileInfo) bool {
+	since = since.Truncate(time.Second)
+	return func(_ string, info fs.FileInfo) bool {
+		return !info.ModTime().Before(since)
+	}
+}
+
+func writeManifest(dir string, manifest *types.RunManifest, include func(rel string, info fs.FileInfo) bool) error {
+	artifacts, err := hashArtifacts(dir, include)
+	if err != nil {
+		return err
+	}
+	manifest.Artifacts = artifacts
+	if manifest.CreatedAt.IsZero() {
+		manifest.CreatedAt = time.Now()
+	}
+	return saveManifest(dir, manifest)
+}
+
+func saveManifest(dir string, manifest *types.RunManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, types.ManifestFilename), data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest reads dir/manifest.json
+func ReadManifest(dir string) (*types.RunManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, types.ManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	var manifest types.RunManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// RewriteArtifact runs rewrite, which replaces the file rel of dir in place,
+// and updates its entry of dir/manifest.json so the directory still verifies.
+// A file that no longer matches its manifest entry is not rewritten, so
+// earlier changes are not hidden. Without a manifest listing rel, rewrite
+// just runs.
+func RewriteArtifact(dir, rel string, rewrite func() error) error {
+	manifest, err := ReadManifest(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return rewrite()
+	}
+	if err != nil {
+		return err
+	}
+	entry := -1
+	for i, artifact := range manifest.Artifacts {
+		if artifact.Path == rel {
+			entry = i
+			break
+		}
+	}
+	if entry < 0 {
+		return rewrite()
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	sum, err := HashFile(path)
+	if err != nil {
+		return err
+	}
+	if sum != manifest.Artifacts[entry].SHA256 {
+		return fmt.Errorf("%s does not match its manifest; check it with runner verify", rel)
+	}
+	if err := rewrite(); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if sum, err = HashFile(path); err != nil {
+		return err
+	}
+	manifest.Artifacts[entry].Size = info.Size()
+	manifest.Artifacts[entry].SHA256 = sum
+	return saveManifest(dir, manifest)
+}
+
+// VerifyManifest checks the files under dir against dir/manifest.json. Files
+// a scoped manifest does not list are not reported as unlisted.
+func VerifyManifest(dir string) (*types.RunManifest, *types.ManifestVerification, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	current, err := hashArtifacts(dir, nil)
+	if err != nil {
+		return manifest, nil, err
+	}
+
+	found := make(map[string]types.ManifestArtifact, len(current))
+	for _, artifact := range current {
+		found[artifact.Path] = artifact
+	}
+	verification := &types.ManifestVerification{}
+	for _, want := range manifest.Artifacts {
+		verification.Checked++
+		got, ok := found[want.Path]
+		if !ok {
+			verification.Missing = append(verification.Missing, want.Path)
+			continue
+		}
+		delete(found, want.Path)
+		if got.SHA256 != want.SHA256 || got.Size != want.Size {
+			verification.Modified = append(verification.Modified, want.Path)
+		}
+	}
+	if !manifest.Scoped {
+		for path := range found {
+			verification.Unlisted = append(verification.Unlisted, path)
+		}
+	}
+	sort.Strings(verification.Unlisted)
+	return manifest, verification, nil
+}
+
+// hashArtifacts hashes every regular file under dir except the manifest,
+// restricted to the files include selects when it is not nil
+func hashArtifacts(dir string, include func(rel string, info fs.FileInfo) bool) ([]types.ManifestArtifact, error) {
+	var artifacts []types.ManifestArtifact
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == types.ManifestFilename {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if include != nil && !include(rel, info) {
+			return nil
+		}
+		sum, err := HashFile(path)
+		if err != nil {
+			return err
+		}
+		artifacts = append(artifacts, types.ManifestArtifact{Path: rel, Size: info.Size(), SHA256: sum})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash artifacts in %s: %w", dir, err)
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Path < artifacts[j].Path })
+	return artifacts, nil
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jsonrpc-bench/runner/types"
+)
+
+// TestVerifyManifest tests that missing, modified and added files are
+// reported against the manifest written for a run directory
+func TestVerifyManifest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	write("results.json", `{"client_metrics":{}}`)
+	write("report.html", "<html></html>")
+	write("responses/geth.json", "{}")
+
+	require.NoError(t, WriteManifest(dir, &types.RunManifest{Kind: types.ManifestKindBenchmark}))
+
+	manifest, verification, err := VerifyManifest(dir)
+	require.NoError(t, err)
+	assert.True(t, verification.OK())
+	assert.Equal(t, 3, verification.Checked)
+	require.Len(t, manifest.Artifacts, 3)
+	assert.Equal(t, "report.html", manifest.Artifacts[0].Path)
+	assert.Equal(t, "responses/geth.json", manifest.Artifacts[1].Path)
+	assert.False(t, manifest.CreatedAt.IsZero())
+
+	write("results.json", `{"client_metrics":{"geth":{}}}`)
+	require.NoError(t, os.Remove(filepath.Join(dir, "report.html")))
+	write("notes.txt", "added later")
+
+	_, verification, err = VerifyManifest(dir)
+	require.NoError(t, err)
+	assert.False(t, verification.OK())
+	assert.Equal(t, []string{"report.html"}, verification.Missing)
+	assert.Equal(t, []string{"results.json"}, verification.Modified)
+	assert.Equal(t, []string{"notes.txt"}, verification.Unlisted)
+}
+
+// TestVerifyScopedManifest tests that a scoped manifest covers only the files
+// it selected and ignores the other files of a shared directory
+func TestVerifyScopedManifest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	write("old-results.json", "{}")
+	write("results.json", `{"client_metrics":{}}`)
+
+	include := func(rel string, _ fs.FileInfo) bool { return rel == "results.json" }
+	require.NoError(t, WriteScopedManifest(dir, &types.RunManifest{Kind: types.ManifestKindBenchmark}, include))
+
+	manifest, verification, err := VerifyManifest(dir)
+	require.NoError(t, err)
+	assert.True(t, manifest.Scoped)
+	require.Len(t, manifest.Artifacts, 1)
+	assert.Equal(t, "results.json", manifest.Artifacts[0].Path)
+	assert.True(t, verification.OK())
+
+	write("later-run.json", "{}")
+	write("results.json", `{"client_metrics":{"geth":{}}}`)
+
+	_, verification, err = VerifyManifest(dir)
+	require.NoError(t, err)
+	assert.Empty(t, verification.Unlisted)
+	assert.Equal(t, []string{"results.json"}, verification.Modified)
+}
+
+// TestModifiedSince tests the selection of files written by a run
+func TestModifiedSince(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.json")
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0644))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	assert.True(t, ModifiedSince(info.ModTime())("results.json", info))
+	assert.False(t, ModifiedSince(info.ModTime().Add(time.Minute))("results.json", info))
+}
+
+// TestRewriteArtifact tests that a deliberate rewrite keeps the manifest
+// verifying, and that a file already differing from its manifest is refused
+func TestRewriteArtifact(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"client_metrics":{}}`), 0644))
+	require.NoError(t, WriteManifest(dir, &types.RunManifest{Kind: types.ManifestKindHistoric}))
+
+	rewrite := func(content string) func() error {
+		return func() error { return os.WriteFile(path, []byte(content), 0644) }
+	}
+	require.NoError(t, RewriteArtifact(dir, "results.json", rewrite(`{"schema_version":2,"client_metrics":{}}`)))
+	_, verification, err := VerifyManifest(dir)
+	require.NoError(t, err)
+	assert.True(t, verification.OK())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"client_metrics":{"tampered":{}}}`), 0644))
+	assert.Error(t, RewriteArtifact(dir, "results.json", rewrite(`{}`)))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "tampered")
+
+	// Without a manifest the rewrite just runs
+	other := t.TempDir()
+	ran := false
+	require.NoError(t, RewriteArtifact(other, "results.json", func() error { ran = true; return nil }))
+	assert.True(t, ran)
+}
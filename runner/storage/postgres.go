@@ -190,6 +190,12 @@ func (d *Database) ListRuns(filter types.RunFilter) ([]*types.HistoricRun, error
 	if !filter.Since.IsZero() {
 		query += fmt.Sprintf(" AND timestamp >= $%d", argCount)
 		args = append(args, filter.Since)
+		argCount++
+	}
+
+	if !filter.Until.IsZero() {
+		query += fmt.Sprintf(" AND timestamp <= $%d", argCount)
+		args = append(args, filter.Until)
 	}
 
 	query += " ORDER BY timestamp DESC"
@@ -279,6 +285,12 @@ func (d *Database) QueryMetrics(query types.MetricQuery) ([]types.TimeSeriesMetr
 		argCount++
 	}
 
+	if query.RunID != "" {
+		sqlQuery += fmt.Sprintf(" AND run_id = $%d", argCount)
+		args = append(args, query.RunID)
+		argCount++
+	}
+
 	if query.Client != "" {
 		sqlQuery += fmt.Sprintf(" AND client = $%d", argCount)
 		args = append(args, query.Client)
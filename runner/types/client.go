@@ -14,6 +14,13 @@ type ClientConfig struct {
 	MaxRetries int               `yaml:"max_retries,omitempty" json:"max_retries,omitempty"`
 	RateLimit  *RateLimitConfig  `yaml:"rate_limit,omitempty" json:"rate_limit,omitempty"`
 	Auth       *AuthConfig       `yaml:"auth,omitempty" json:"auth,omitempty"`
+
+	// Process and Cgroup locate a client running on the runner's host so its
+	// resource use can be sampled; at most one may be set. Process is a pid,
+	// a pidfile path or a process name; Cgroup is a cgroup v2 path relative to
+	// /sys/fs/cgroup (or absolute).
+	Process string `yaml:"process,omitempty" json:"process,omitempty"`
+	Cgroup  string `yaml:"cgroup,omitempty" json:"cgroup,omitempty"`
 }
 
 func (c *ClientConfig) GetBasicAuthURL() string {
@@ -0,0 +1,151 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Error classes a failed benchmark request is bucketed into. Transport classes
+// come from k6 error codes, HTTP classes from non-200 statuses and the rpc_*
+// classes from the JSON-RPC error object of an otherwise successful response.
+const (
+	ErrorClassTransportTimeout = "transport_timeout"
+	ErrorClassTransportRefused = "transport_refused"
+	ErrorClassTransportReset   = "transport_reset"
+	ErrorClassTransportOther   = "transport_other"
+
+	ErrorClassHTTP429   = "http_429"
+	ErrorClassHTTP5xx   = "http_5xx"
+	ErrorClassHTTPOther = "http_other"
+
+	ErrorClassRPCExecution           = "rpc_execution_error"
+	ErrorClassRPCResourceNotFound    = "rpc_resource_not_found"
+	ErrorClassRPCResourceUnavailable = "rpc_resource_unavailable"
+	ErrorClassRPCLimitExceeded       = "rpc_limit_exceeded"
+	ErrorClassRPCMethodNotFound      = "rpc_method_not_found"
+	ErrorClassRPCInvalidRequest      = "rpc_invalid_request"
+	ErrorClassRPCInvalidParams       = "rpc_invalid_params"
+	ErrorClassRPCInternal            = "rpc_internal_error"
+	ErrorClassRPCParse               = "rpc_parse_error"
+	ErrorClassRPCOther               = "rpc_other"
+
+	// ErrorClassInvalidResponse covers 200 responses whose body is not JSON
+	// or carries neither a result nor an error
+	ErrorClassInvalidResponse = "invalid_response"
+)
+
+// k6TransportErrorClasses maps k6 error codes
+// (https://grafana.com/docs/k6/latest/javascript-api/error-codes/) to
+// transport classes. Any other code in the 1000-1999 range is
+// ErrorClassTransportOther.
+var k6TransportErrorClasses = map[int]string{
+	1050: ErrorClassTransportTimeout, // request timeout
+	1211: ErrorClassTransportTimeout, // dial timeout
+	1212: ErrorClassTransportRefused, // connection refused
+	1201: ErrorClassTransportReset,   // broken pipe on write
+	1220: ErrorClassTransportReset,   // connection reset by peer
+}
+
+// rpcErrorClasses maps JSON-RPC error codes to classes. Codes follow the
+// JSON-RPC 2.0 spec and EIP-1474; geth reports reverts as 3 or -32000.
+var rpcErrorClasses = map[int]string{
+	3:      ErrorClassRPCExecution,
+	-32000: ErrorClassRPCExecution,
+	-32001: ErrorClassRPCResourceNotFound,
+	-32002: ErrorClassRPCResourceUnavailable,
+	-32005: ErrorClassRPCLimitExceeded,
+	-32600: ErrorClassRPCInvalidRequest,
+	-32601: ErrorClassRPCMethodNotFound,
+	-32602: ErrorClassRPCInvalidParams,
+	-32603: ErrorClassRPCInternal,
+	-32700: ErrorClassRPCParse,
+}
+
+// ErrorClasses lists every class in display order: transport, HTTP, JSON-RPC
+func ErrorClasses() []string {
+	return []string{
+		ErrorClassTransportTimeout, ErrorClassTransportRefused, ErrorClassTransportReset, ErrorClassTransportOther,
+		ErrorClassHTTP429, ErrorClassHTTP5xx, ErrorClassHTTPOther,
+		ErrorClassRPCExecution, ErrorClassRPCResourceNotFound, ErrorClassRPCResourceUnavailable,
+		ErrorClassRPCLimitExceeded, ErrorClassRPCMethodNotFound, ErrorClassRPCInvalidRequest,
+		ErrorClassRPCInvalidParams, ErrorClassRPCInternal, ErrorClassRPCParse, ErrorClassRPCOther,
+		ErrorClassInvalidResponse,
+	}
+}
+
+// ClassifyTransportError returns the class for a k6 transport error code
+func ClassifyTransportError(k6ErrorCode int) string {
+	if class, ok := k6TransportErrorClasses[k6ErrorCode]; ok {
+		return class
+	}
+	return ErrorClassTransportOther
+}
+
+// ClassifyHTTPStatus returns the class for a non-200 HTTP status
+func ClassifyHTTPStatus(status int) string {
+	switch {
+	case status == 429:
+		return ErrorClassHTTP429
+	case status >= 500:
+		return ErrorClassHTTP5xx
+	}
+	return ErrorClassHTTPOther
+}
+
+// ClassifyRPCError returns the class for a JSON-RPC error code
+func ClassifyRPCError(code int) string {
+	if class, ok := rpcErrorClasses[code]; ok {
+		return class
+	}
+	return ErrorClassRPCOther
+}
+
+// Environment classes group the JSON-RPC error classes that reflect how a
+// node is configured (disabled namespaces, pruned state, capped ranges)
+// rather than a wrong answer. Compare reports them apart from real
+// differences.
+const (
+	EnvClassNamespaceDisabled = "namespace_disabled"
+	EnvClassNoState           = "no_state"
+	EnvClassRangeCap          = "range_cap"
+)
+
+// ClassifyEnvironmentError returns the environment class of a JSON-RPC
+// error, derived from its ClassifyRPCError class, or "" for an ordinary
+// error such as a revert
+func ClassifyEnvironmentError(code int, message string) string {
+	switch ClassifyRPCError(code) {
+	case ErrorClassRPCMethodNotFound, ErrorClassRPCInvalidRequest:
+		return EnvClassNamespaceDisabled
+	case ErrorClassRPCResourceUnavailable:
+		return EnvClassNoState
+	case ErrorClassRPCInvalidParams:
+		lower := strings.ToLower(message)
+		if strings.Contains(lower, "range") || strings.Contains(lower, "logs") || strings.Contains(lower, "limit") {
+			return EnvClassRangeCap
+		}
+	}
+	return ""
+}
+
+// ErrorTaxonomy is the classification table handed to the k6 script through
+// config.json so the script and the Go side agree on class names
+type ErrorTaxonomy struct {
+	TransportCodes map[string]string `json:"transport_codes"`
+	RPCCodes       map[string]string `json:"rpc_codes"`
+}
+
+// NewErrorTaxonomy builds the script-side classification table
+func NewErrorTaxonomy() *ErrorTaxonomy {
+	t := &ErrorTaxonomy{
+		TransportCodes: make(map[string]string, len(k6TransportErrorClasses)),
+		RPCCodes:       make(map[string]string, len(rpcErrorClasses)),
+	}
+	for code, class := range k6TransportErrorClasses {
+		t.TransportCodes[strconv.Itoa(code)] = class
+	}
+	for code, class := range rpcErrorClasses {
+		t.RPCCodes[strconv.Itoa(code)] = class
+	}
+	return t
+}
@@ -60,4 +60,7 @@ type K6Options struct {
 // K6Config is a configuration for a k6 test
 type K6Config struct {
 	Options K6Options `json:"options"`
+	// ErrorTaxonomy lets the script classify failures with the same class
+	// names the runner reports; k6 itself ignores it
+	ErrorTaxonomy *ErrorTaxonomy `json:"error_taxonomy,omitempty"`
 }
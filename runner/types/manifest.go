@@ -0,0 +1,67 @@
+package types
+
+import "time"
+
+// ManifestFilename is the manifest's name inside a run directory
+const ManifestFilename = "manifest.json"
+
+// Kinds of run a manifest describes
+const (
+	ManifestKindBenchmark = "benchmark"
+	ManifestKindCompare   = "compare"
+	ManifestKindHistoric  = "historic"
+)
+
+// RunManifest ties the artifacts of a run directory together with what is
+// needed to reproduce the run
+type RunManifest struct {
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+	RunID     string    `json:"run_id,omitempty"`
+	TestName  string    `json:"test_name,omitempty"`
+
+	// Seed drives request generation (benchmark) or corpus sampling (compare);
+	// nil when the run drew nothing at random
+	Seed *int64 `json:"seed,omitempty"`
+	// ConfigHash and RequestSetHash are SHA-256 hex digests of the effective
+	// configuration and of the requests sent
+	ConfigHash     string `json:"config_hash"`
+	RequestSetHash string `json:"request_set_hash,omitempty"`
+	// EngineVersion is the load engine that sent the requests, e.g. "k6 v0.52.0"
+	EngineVersion string `json:"engine_version,omitempty"`
+	// RunnerCommit is the commit of the runner checkout, when known
+	RunnerCommit string `json:"runner_commit,omitempty"`
+	// ClientVersions maps client name to its web3_clientVersion
+	ClientVersions map[string]string `json:"client_versions,omitempty"`
+	Hostname       string            `json:"hostname,omitempty"`
+	Environment    EnvironmentInfo   `json:"environment"`
+
+	// Artifacts lists every file of the run directory except the manifest,
+	// ordered by path. A Scoped manifest lists only the files the run wrote
+	// into a directory shared with other runs; other files are not checked.
+	Artifacts []ManifestArtifact `json:"artifacts"`
+	Scoped    bool               `json:"scoped,omitempty"`
+}
+
+// ManifestArtifact is one file of a run directory
+type ManifestArtifact struct {
+	Path   string `json:"path"` // relative to the run directory, slash-separated
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ManifestVerification is the outcome of checking a run directory against
+// its manifest
+type ManifestVerification struct {
+	Checked  int      `json:"checked"`
+	Missing  []string `json:"missing,omitempty"`
+	Modified []string `json:"modified,omitempty"`
+	// Unlisted files were added after the manifest was written
+	Unlisted []string `json:"unlisted,omitempty"`
+}
+
+// OK reports whether every listed artifact is present and unmodified and no
+// file was added
+func (v *ManifestVerification) OK() bool {
+	return len(v.Missing) == 0 && len(v.Modified) == 0 && len(v.Unlisted) == 0
+}
@@ -31,6 +31,12 @@ const (
 	MetricThroughput  = "throughput"
 	MetricCPUUsage    = "cpu_usage"
 	MetricMemoryUsage = "memory_usage"
+
+	// Efficiency metrics, present for clients with sampled resources
+	MetricRequestsPerCPUSecond = "requests_per_cpu_second"
+	MetricRPSPerGB             = "rps_per_gb"
+	MetricP99LatencyGB         = "p99_latency_gb"
+	MetricRPSPerWatt           = "rps_per_watt"
 )
 
 // GrafanaResponse represents the response format for Grafana queries
@@ -0,0 +1,47 @@
+package types
+
+// PairedComparison compares two clients on the requests both of them served.
+// Every client replays the same requests.csv rows, so each pair is the same
+// payload sent to both; ratios are ClientA latency over ClientB latency.
+type PairedComparison struct {
+	ClientA string `json:"client_a"`
+	ClientB string `json:"client_b"`
+	// Method is the req_name the pairs were restricted to; empty means all
+	// methods
+	Method string `json:"method,omitempty"`
+	Pairs  int    `json:"pairs"`
+
+	// Per-payload winners: which client answered each identical request faster
+	AFaster int `json:"a_faster"`
+	BFaster int `json:"b_faster"`
+	Ties    int `json:"ties"`
+
+	RatioP10     float64 `json:"ratio_p10"`
+	RatioP50     float64 `json:"ratio_p50"`
+	RatioP90     float64 `json:"ratio_p90"`
+	RatioP99     float64 `json:"ratio_p99"`
+	RatioGeoMean float64 `json:"ratio_geomean"`
+
+	// Wilcoxon signed-rank test on the latency differences (normal
+	// approximation with tie correction, two-sided)
+	WilcoxonW float64 `json:"wilcoxon_w"`
+	WilcoxonZ float64 `json:"wilcoxon_z"`
+	PValue    float64 `json:"p_value"`
+
+	// Winner is the client faster on more payloads; empty on a draw.
+	// Significant is set only when the Wilcoxon test agrees on the direction.
+	Winner      string `json:"winner,omitempty"`
+	Significant bool   `json:"significant"`
+}
+
+// WinnerShare is the percentage of paired payloads the winner was faster on
+func (p PairedComparison) WinnerShare() float64 {
+	if p.Pairs == 0 || p.Winner == "" {
+		return 0
+	}
+	wins := p.AFaster
+	if p.Winner == p.ClientB {
+		wins = p.BFaster
+	}
+	return float64(wins) / float64(p.Pairs) * 100
+}
@@ -0,0 +1,148 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ResultSchemaVersion is the BenchmarkResult.SchemaVersion this build writes.
+// Bump it with every change that older payloads would decode inconsistently
+// and register an upgrader from the previous version in resultUpgraders.
+const ResultSchemaVersion = 1
+
+// resultUpgrader migrates a decoded result payload in place from the version
+// it is registered under to the next one
+type resultUpgrader func(result map[string]interface{}) error
+
+// resultUpgraders maps each version to the upgrader to the next version.
+// Payloads without schema_version predate versioning and are version 0.
+var resultUpgraders = map[int]resultUpgrader{
+	0: upgradeResultV0,
+}
+
+// UnmarshalJSON upgrades payloads of older schema versions to the current
+// shape before decoding, so results.json files and FullResults blobs decode
+// the same whichever runner wrote them
+func (r *BenchmarkResult) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	upgraded, _, err := UpgradeResultJSON(data)
+	if err != nil {
+		return err
+	}
+	// plain has no UnmarshalJSON, so this decodes the fields
+	type plain BenchmarkResult
+	return json.Unmarshal(upgraded, (*plain)(r))
+}
+
+// UpgradeResultJSON migrates a result payload to ResultSchemaVersion and
+// returns it with the version it was read at. Current payloads are returned
+// unchanged; fields the upgraders don't know about are kept.
+func UpgradeResultJSON(data []byte) ([]byte, int, error) {
+	var header struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, 0, fmt.Errorf("failed to read benchmark result schema version: %w", err)
+	}
+	from := 0
+	if header.SchemaVersion != nil {
+		from = *header.SchemaVersion
+	}
+	if from == ResultSchemaVersion {
+		return data, from, nil
+	}
+	if from > ResultSchemaVersion || from < 0 {
+		return nil, from, fmt.Errorf("benchmark result has schema version %d, this runner supports up to %d", from, ResultSchemaVersion)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep integers such as request counts exact through the round trip
+	decoder.UseNumber()
+	var result map[string]interface{}
+	if err := decoder.Decode(&result); err != nil {
+		return nil, from, fmt.Errorf("failed to decode benchmark result: %w", err)
+	}
+	if result == nil {
+		return nil, from, fmt.Errorf("benchmark result is not an object")
+	}
+
+	for version := from; version < ResultSchemaVersion; version++ {
+		upgrade, ok := resultUpgraders[version]
+		if !ok {
+			return nil, from, fmt.Errorf("no upgrader from benchmark result schema version %d", version)
+		}
+		if err := upgrade(result); err != nil {
+			return nil, from, fmt.Errorf("failed to upgrade benchmark result from schema version %d: %w", version, err)
+		}
+		result["schema_version"] = version + 1
+	}
+
+	upgraded, err := json.Marshal(result)
+	if err != nil {
+		return nil, from, fmt.Errorf("failed to encode upgraded benchmark result: %w", err)
+	}
+	return upgraded, from, nil
+}
+
+// upgradeResultV0 normalizes results written before versioning:
+//   - null client entries are dropped and missing client names filled from
+//     their key
+//   - clients that only recorded method_details get their methods rebuilt
+//     from it
+//   - performance_score keeps numeric scores only and is never null
+//   - an environment that is not an object is dropped
+func upgradeResultV0(result map[string]interface{}) error {
+	if clients, ok := result["client_metrics"].(map[string]interface{}); ok {
+		for name, raw := range clients {
+			client, ok := raw.(map[string]interface{})
+			if !ok {
+				delete(clients, name)
+				continue
+			}
+			if clientName, _ := client["name"].(string); clientName == "" {
+				client["name"] = name
+			}
+			methods, _ := client["methods"].(map[string]interface{})
+			if methods == nil {
+				methods = make(map[string]interface{})
+				client["methods"] = methods
+			}
+			if len(methods) == 0 {
+				details, _ := client["method_details"].(map[string]interface{})
+				for method, raw := range details {
+					detail, ok := raw.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					summary := make(map[string]interface{}, len(detail))
+					for key, value := range detail {
+						if key != "name" {
+							summary[key] = value
+						}
+					}
+					methods[method] = summary
+				}
+			}
+		}
+	} else {
+		result["client_metrics"] = map[string]interface{}{}
+	}
+
+	scores := make(map[string]interface{})
+	if raw, ok := result["performance_score"].(map[string]interface{}); ok {
+		for client, score := range raw {
+			if _, ok := score.(json.Number); ok {
+				scores[client] = score
+			}
+		}
+	}
+	result["performance_score"] = scores
+
+	if _, ok := result["environment"].(map[string]interface{}); !ok {
+		delete(result, "environment")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUnmarshalUpgradesUnversionedResults(t *testing.T) {
+	legacy := `{
+		"client_metrics": {
+			"geth": {
+				"total_requests": 9007199254740993,
+				"method_details": {"eth_call": {"name": "Call", "p95": 12.5, "count": 10}}
+			},
+			"broken": null
+		},
+		"performance_score": {"geth": 80, "notes": "n/a"},
+		"environment": "linux",
+		"custom_field": true
+	}`
+
+	var result BenchmarkResult
+	if err := json.Unmarshal([]byte(legacy), &result); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if result.SchemaVersion != ResultSchemaVersion {
+		t.Fatalf("expected schema version %d, got %d", ResultSchemaVersion, result.SchemaVersion)
+	}
+	if _, ok := result.ClientMetrics["broken"]; ok {
+		t.Fatal("null client entry was kept")
+	}
+	geth := result.ClientMetrics["geth"]
+	if geth.Name != "geth" || geth.TotalRequests != 9007199254740993 {
+		t.Fatalf("client not normalized: %+v", geth)
+	}
+	if method := geth.Methods["eth_call"]; method.P95 != 12.5 || method.Count != 10 {
+		t.Fatalf("methods not rebuilt from method_details: %+v", geth.Methods)
+	}
+	if len(result.PerformanceScore) != 1 || result.PerformanceScore["geth"] != 80 {
+		t.Fatalf("unexpected performance scores %v", result.PerformanceScore)
+	}
+
+	upgraded, from, err := UpgradeResultJSON([]byte(legacy))
+	if err != nil || from != 0 {
+		t.Fatalf("UpgradeResultJSON: from %d, err %v", from, err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(upgraded, &fields); err != nil || fields["custom_field"] != true {
+		t.Fatalf("unknown field not kept: %s", upgraded)
+	}
+}
+
+func TestUpgradeResultJSON_CurrentAndNewerVersions(t *testing.T) {
+	current := []byte(`{"schema_version":1,"client_metrics":{}}`)
+	out, from, err := UpgradeResultJSON(current)
+	if err != nil || from != ResultSchemaVersion || string(out) != string(current) {
+		t.Fatalf("current payload should be returned unchanged, got %s (from %d, err %v)", out, from, err)
+	}
+	if _, _, err := UpgradeResultJSON([]byte(`{"schema_version":99}`)); err == nil {
+		t.Fatal("expected an error for a newer schema version")
+	}
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // ResponseDiff represents a difference between client responses
 type ResponseDiff struct {
 	Method       string                 `json:"method"`
@@ -40,6 +45,80 @@ type MetricSummary struct {
 	SuccessCount     int64   `json:"success_count"`
 	TimeoutRate      float64 `json:"timeout_rate"`
 	ConnectionErrors int64   `json:"connection_errors"`
+
+	// Coordinated-omission-corrected latency, measured from each request's
+	// scheduled start time instead of its send time. Only populated for
+	// constant-arrival-rate runs.
+	CorrectedMin float64 `json:"corrected_min,omitempty"`
+	CorrectedMax float64 `json:"corrected_max,omitempty"`
+	CorrectedAvg float64 `json:"corrected_avg,omitempty"`
+	CorrectedP50 float64 `json:"corrected_p50,omitempty"`
+	CorrectedP90 float64 `json:"corrected_p90,omitempty"`
+	CorrectedP95 float64 `json:"corrected_p95,omitempty"`
+	CorrectedP99 float64 `json:"corrected_p99,omitempty"`
+
+	// Response body size in bytes per request, and the bandwidth the
+	// responses add up to over the run in MB/s (10^6 bytes)
+	ResponseBytesAvg float64 `json:"response_bytes_avg,omitempty"`
+	ResponseBytesP50 float64 `json:"response_bytes_p50,omitempty"`
+	ResponseBytesP90 float64 `json:"response_bytes_p90,omitempty"`
+	ResponseBytesP95 float64 `json:"response_bytes_p95,omitempty"`
+	ResponseBytesP99 float64 `json:"response_bytes_p99,omitempty"`
+	ResponseBytesMax float64 `json:"response_bytes_max,omitempty"`
+	BandwidthMBps    float64 `json:"bandwidth_mbps,omitempty"`
+
+	// ErrorClasses counts failed requests per error class (see ErrorClasses)
+	ErrorClasses map[string]int64 `json:"error_classes,omitempty"`
+}
+
+// HasCorrected reports whether coordinated-omission-corrected latency was
+// recorded for this measurement.
+func (m MetricSummary) HasCorrected() bool {
+	return m.CorrectedMax > 0
+}
+
+// WithLatencyMode returns a copy of the summary whose latency fields (Min,
+// Max, Avg, P50, P90, P95, P99) hold the figures for the given mode. Raw mode,
+// or a summary without corrected data, is returned unchanged.
+func (m MetricSummary) WithLatencyMode(mode LatencyMode) MetricSummary {
+	if mode != LatencyModeCorrected || !m.HasCorrected() {
+		return m
+	}
+	m.Min = m.CorrectedMin
+	m.Max = m.CorrectedMax
+	m.Avg = m.CorrectedAvg
+	m.P50 = m.CorrectedP50
+	m.P90 = m.CorrectedP90
+	m.P95 = m.CorrectedP95
+	m.P99 = m.CorrectedP99
+	// Percentiles k6 does not report for the corrected trend would otherwise
+	// silently mix raw values into a corrected view.
+	m.P75 = 0
+	m.P999 = 0
+	return m
+}
+
+// LatencyMode selects which latency figures reports and regression checks use
+type LatencyMode string
+
+const (
+	// LatencyModeRaw uses latency measured from the moment a request was sent
+	LatencyModeRaw LatencyMode = "raw"
+	// LatencyModeCorrected uses latency measured from the moment a request was
+	// scheduled, which includes time spent queued behind a saturated generator
+	LatencyModeCorrected LatencyMode = "corrected"
+)
+
+// ParseLatencyMode validates a latency mode string. An empty string maps to
+// LatencyModeRaw.
+func ParseLatencyMode(s string) (LatencyMode, error) {
+	switch LatencyMode(s) {
+	case "", LatencyModeRaw:
+		return LatencyModeRaw, nil
+	case LatencyModeCorrected:
+		return LatencyModeCorrected, nil
+	}
+	return "", fmt.Errorf("invalid latency mode %q (expected %q or %q)", s, LatencyModeRaw, LatencyModeCorrected)
 }
 
 // TimeSeriesPoint represents a single data point in time series
@@ -50,8 +129,13 @@ type TimeSeriesPoint struct {
 	ErrorCount int64   `json:"error_count,omitempty"`
 }
 
+// TimeSeriesLatencyAvg is the ClientMetrics.TimeSeries key of the per-interval
+// mean latency timeline
+const TimeSeriesLatencyAvg = "latency_avg"
+
 // SystemMetrics represents system resource usage metrics
 type SystemMetrics struct {
+	Timestamp        int64   `json:"timestamp,omitempty"` // Unix ms; zero for averages
 	CPUUsage         float64 `json:"cpu_usage_percent"`
 	MemoryUsage      float64 `json:"memory_usage_mb"`
 	MemoryPercent    float64 `json:"memory_percent"`
@@ -61,6 +145,9 @@ type SystemMetrics struct {
 	DiskIOWrite      int64   `json:"disk_io_write_bytes"`
 	OpenConnections  int64   `json:"open_connections"`
 	GoroutineCount   int     `json:"goroutine_count"`
+	// HostCPUPercent is host-wide CPU use (0-100); unlike CPUUsage, which is
+	// the runner process alone, it includes k6
+	HostCPUPercent float64 `json:"host_cpu_percent,omitempty"`
 }
 
 // MethodMetrics represents metrics for a specific method with optional name
@@ -72,6 +159,7 @@ type MethodMetrics struct {
 // ClientMetrics represents metrics for a specific client
 type ClientMetrics struct {
 	Name          string                    `json:"name"`
+	Version       string                    `json:"version,omitempty"` // web3_clientVersion, when the client answered it
 	TotalRequests int64                     `json:"total_requests"`
 	TotalErrors   int64                     `json:"total_errors"`
 	ErrorRate     float64                   `json:"error_rate"`
@@ -85,6 +173,56 @@ type ClientMetrics struct {
 	SystemMetrics     []SystemMetrics              `json:"system_metrics"`
 	ErrorTypes  map[string]int64 `json:"error_types"`
 	StatusCodes map[int]int64    `json:"status_codes"`
+	// ErrorClasses sums the per-method error class counts
+	ErrorClasses map[string]int64 `json:"error_classes,omitempty"`
+	// Resources is the client's own resource use, sampled when it runs on
+	// the runner's host
+	Resources *ClientResources `json:"resources,omitempty"`
+}
+
+// Sources of ClientResources
+const (
+	ResourceSourceCgroup  = "cgroup"
+	ResourceSourceProcess = "process"
+)
+
+// ClientResourceSample is a local client's resource use over one interval.
+// CPU, IO and network are deltas over the interval; memory is a snapshot.
+type ClientResourceSample struct {
+	Timestamp    int64   `json:"timestamp"` // Unix ms
+	CPUCores     float64 `json:"cpu_cores"` // CPU-seconds used per second
+	MemoryBytes  int64   `json:"memory_bytes"`
+	IOReadBytes  int64   `json:"io_read_bytes"`
+	IOWriteBytes int64   `json:"io_write_bytes"`
+	NetRecvBytes int64   `json:"net_recv_bytes"`
+	NetSentBytes int64   `json:"net_sent_bytes"`
+	// Watts estimates the client's power draw: host package power from RAPL
+	// scaled by its share of busy CPU time; zero when RAPL isn't readable
+	Watts float64 `json:"watts,omitempty"`
+}
+
+// ClientResources is a local client's resource use during the run, read from
+// its cgroup or /proc, with totals and efficiency figures derived from it
+type ClientResources struct {
+	Source  string                 `json:"source"` // ResourceSourceCgroup or ResourceSourceProcess
+	Target  string                 `json:"target"` // cgroup path or pid
+	Samples []ClientResourceSample `json:"samples"`
+
+	CPUSeconds      float64 `json:"cpu_seconds"`
+	AvgCPUCores     float64 `json:"avg_cpu_cores"`
+	PeakCPUCores    float64 `json:"peak_cpu_cores"`
+	AvgMemoryBytes  int64   `json:"avg_memory_bytes"`
+	PeakMemoryBytes int64   `json:"peak_memory_bytes"`
+	IOReadBytes     int64   `json:"io_read_bytes"`
+	IOWriteBytes    int64   `json:"io_write_bytes"`
+	NetRecvBytes    int64   `json:"net_recv_bytes"`
+	NetSentBytes    int64   `json:"net_sent_bytes"`
+	AvgWatts        float64 `json:"avg_watts,omitempty"`
+
+	// RPSPerCore is requests per second divided by the average cores used;
+	// MemoryBytesPerRPS is the average memory divided by requests per second
+	RPSPerCore        float64 `json:"rps_per_core,omitempty"`
+	MemoryBytesPerRPS float64 `json:"memory_bytes_per_rps,omitempty"`
 }
 
 // ConnectionMetrics represents connection-related metrics
@@ -103,6 +241,9 @@ type ConnectionMetrics struct {
 
 // BenchmarkResult represents the results of a benchmark run
 type BenchmarkResult struct {
+	// SchemaVersion is the ResultSchemaVersion the result was written with;
+	// older payloads are upgraded when decoded
+	SchemaVersion int                       `json:"schema_version"`
 	Config        interface{}               `json:"config"`
 	Summary       map[string]interface{}    `json:"summary"`
 	ClientMetrics map[string]*ClientMetrics `json:"client_metrics"`
@@ -118,6 +259,117 @@ type BenchmarkResult struct {
 	PerformanceScore map[string]float64 `json:"performance_score"`
 	Recommendations  []string           `json:"recommendations"`
 	Environment      EnvironmentInfo    `json:"environment"`
+
+	// LatencyMode records which latency figures the Latency and Methods
+	// summaries were projected to; empty means raw.
+	LatencyMode LatencyMode `json:"latency_mode,omitempty"`
+
+	// SlowestRequests holds the N slowest requests per client and method when
+	// request capture was enabled, ordered by client, method and latency
+	SlowestRequests []SlowRequest `json:"slowest_requests,omitempty"`
+
+	// PairedComparisons compares clients request by request on identical
+	// payloads when request capture was enabled
+	PairedComparisons []PairedComparison `json:"paired_comparisons,omitempty"`
+
+	// SystemMetrics is the generator host's resource series sampled during
+	// the run; ClientMetrics.SystemMetrics only carries its average
+	SystemMetrics []SystemMetrics `json:"system_metrics,omitempty"`
+
+	// ResourceCorrelations relates each client's latency timeline to the
+	// generator's resource series
+	ResourceCorrelations []ResourceCorrelation `json:"resource_correlations,omitempty"`
+
+	// Efficiency normalizes each client's throughput and latency by the
+	// resources it used; only clients with sampled resources appear
+	Efficiency map[string]*ClientEfficiency `json:"efficiency,omitempty"`
+}
+
+// ClientEfficiency is a client's performance per unit of hardware, derived
+// from ClientMetrics.Resources
+type ClientEfficiency struct {
+	// RequestsPerCPUSecond is requests served per CPU-second consumed
+	RequestsPerCPUSecond float64 `json:"requests_per_cpu_second"`
+	// RPSPerGB is requests per second per GB of average resident memory
+	RPSPerGB float64 `json:"rps_per_gb"`
+	// P99LatencyGB is p99 latency in ms times average resident memory in GB;
+	// lower is better
+	P99LatencyGB float64 `json:"p99_latency_gb"`
+	// RPSPerWatt is requests per second per estimated watt; zero without RAPL
+	RPSPerWatt float64 `json:"rps_per_watt,omitempty"`
+	// Score ranks clients on efficiency alone (0-100)
+	Score float64 `json:"score"`
+}
+
+// Generator resources latency is correlated against
+const (
+	ResourceCPU     = "cpu"
+	ResourceMemory  = "memory"
+	ResourceNetwork = "network"
+)
+
+// ResourceCorrelation relates one client's latency timeline to one generator
+// resource. A spike is an interval whose mean latency exceeds twice the run's
+// median; Flagged means most spikes fell on saturated samples, so the load
+// generator rather than the client may explain them.
+type ResourceCorrelation struct {
+	Client          string  `json:"client"`
+	Resource        string  `json:"resource"`
+	Pearson         float64 `json:"pearson"`
+	Samples         int     `json:"samples"`
+	Spikes          int     `json:"spikes"`
+	SaturatedSpikes int     `json:"saturated_spikes"`
+	Flagged         bool    `json:"flagged"`
+}
+
+// SlowRequest is a single captured request with everything needed to replay it
+type SlowRequest struct {
+	RequestID     string          `json:"request_id"`
+	Client        string          `json:"client"`
+	Method        string          `json:"method"` // req_name, as in ClientMetrics.Methods
+	RPCMethod     string          `json:"rpc_method"`
+	LatencyMs     float64         `json:"latency_ms"`
+	Status        int             `json:"status"`
+	ResponseBytes int64           `json:"response_bytes"`
+	Timestamp     string          `json:"timestamp,omitempty"`
+	Payload       json.RawMessage `json:"payload,omitempty"`
+}
+
+// HasCorrectedLatency reports whether any client in the result recorded
+// coordinated-omission-corrected latency.
+func (r *BenchmarkResult) HasCorrectedLatency() bool {
+	for _, client := range r.ClientMetrics {
+		if client != nil && client.Latency.HasCorrected() {
+			return true
+		}
+	}
+	return false
+}
+
+// WithLatencyMode returns a view of the result whose client and method
+// latency summaries hold the figures for the given mode. The receiver is not
+// modified; in raw mode, or when no corrected latency was recorded, it is
+// returned as is.
+func (r *BenchmarkResult) WithLatencyMode(mode LatencyMode) *BenchmarkResult {
+	if r == nil || mode != LatencyModeCorrected || !r.HasCorrectedLatency() {
+		return r
+	}
+	view := *r
+	view.LatencyMode = mode
+	view.ClientMetrics = make(map[string]*ClientMetrics, len(r.ClientMetrics))
+	for name, client := range r.ClientMetrics {
+		if client == nil {
+			continue
+		}
+		projected := *client
+		projected.Latency = client.Latency.WithLatencyMode(mode)
+		projected.Methods = make(map[string]MetricSummary, len(client.Methods))
+		for method, summary := range client.Methods {
+			projected.Methods[method] = summary.WithLatencyMode(mode)
+		}
+		view.ClientMetrics[name] = &projected
+	}
+	return &view
 }
 
 // ComparisonResult represents comparison between clients or runs
@@ -139,4 +391,5 @@ type EnvironmentInfo struct {
 	GoVersion     string  `json:"go_version"`
 	K6Version     string  `json:"k6_version"`
 	NetworkType   string  `json:"network_type"`
+	GitCommit     string  `json:"git_commit,omitempty"`
 }